@@ -5,16 +5,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"log/slog"
 	"path/filepath"
 	"regexp"
-	jargon_stemmer "search-index/jargon-stemmer"
 	"sort"
 	"strings"
 	"time"
 )
 
 func generateCheatsheetsData(ctx context.Context) ([]CheatsheetData, error) {
-	fmt.Println("📖 Generating cheatsheets data...")
+	slog.Info("📖 Generating cheatsheets data...")
 
 	// Path to cheatsheet files
 	basePath := "../frontend/data/cheatsheets"
@@ -36,7 +36,7 @@ func generateCheatsheetsData(ctx context.Context) ([]CheatsheetData, error) {
 
 		data, category, err := processCheatsheetFile(file, basePath)
 		if err != nil {
-			fmt.Printf("⚠️  Warning: Failed to process %s: %v\n", file, err)
+			slog.Warn(fmt.Sprintf("⚠️  Warning: Failed to process %s: %v", file, err))
 			continue
 		}
 
@@ -63,10 +63,10 @@ func generateCheatsheetsData(ctx context.Context) ([]CheatsheetData, error) {
 	categoriesCount := len(categoriesSet)
 	individualCheatsheets := len(cheatsheetsData) - categoriesCount
 
-	fmt.Printf("📖 Summary:\n")
-	fmt.Printf("  Categories found: %d\n", categoriesCount)
-	fmt.Printf("  Individual cheatsheets: %d\n", individualCheatsheets)
-	fmt.Printf("  Total entries: %d\n", len(cheatsheetsData))
+	slog.Info("📖 Summary:")
+	slog.Info(fmt.Sprintf("  Categories found: %d", categoriesCount))
+	slog.Info(fmt.Sprintf("  Individual cheatsheets: %d", individualCheatsheets))
+	slog.Info(fmt.Sprintf("  Total entries: %d", len(cheatsheetsData)))
 
 	return cheatsheetsData, nil
 }
@@ -141,16 +141,26 @@ func createCategoryEntry(category string) CheatsheetData {
 	}
 }
 
+// Patterns of the cheatsheet HTML metadata, compiled once rather than for every page
+var (
+	descRegex         = regexp.MustCompile(`<meta\s+name=["']description["']\s+content=["']([^"']*)["']`)
+	ogDescRegex       = regexp.MustCompile(`<meta\s+property=["']og:description["']\s+content=["']([^"']*)["']`)
+	titleRegex        = regexp.MustCompile(`<title[^>]*>([^<]+)</title>`)
+	ogTitleRegex      = regexp.MustCompile(`<meta\s+property="og:title"\s+content="([^"]+)"`)
+	twitterTitleRegex = regexp.MustCompile(`<meta\s+property="twitter:title"\s+content="([^"]+)"`)
+	h1Regex           = regexp.MustCompile(`<h1[^>]*>([^<]+)</h1>`)
+	nonASCIIRegex     = regexp.MustCompile(`[^\x20-\x7E]`)
+	spacesRegex       = regexp.MustCompile(`\s+`)
+)
+
 func extractHTMLDescription(content string) string {
 	// Extract description from meta tag (using regex similar to Python)
-	descRegex := regexp.MustCompile(`<meta\s+name=["']description["']\s+content=["']([^"']*)["']`)
 	match := descRegex.FindStringSubmatch(content)
 	if len(match) > 1 {
 		return strings.TrimSpace(match[1])
 	}
 
 	// Fallback: try property="og:description"
-	ogDescRegex := regexp.MustCompile(`<meta\s+property=["']og:description["']\s+content=["']([^"']*)["']`)
 	match = ogDescRegex.FindStringSubmatch(content)
 	if len(match) > 1 {
 		return strings.TrimSpace(match[1])
@@ -161,7 +171,6 @@ func extractHTMLDescription(content string) string {
 
 func extractHTMLTitle(content string) string {
 	// 1. Try <title> tag first
-	titleRegex := regexp.MustCompile(`<title[^>]*>([^<]+)</title>`)
 	match := titleRegex.FindStringSubmatch(content)
 	if len(match) > 1 {
 		title := strings.TrimSpace(match[1])
@@ -171,7 +180,6 @@ func extractHTMLTitle(content string) string {
 	}
 
 	// 2. Try <meta property="og:title">
-	ogTitleRegex := regexp.MustCompile(`<meta\s+property="og:title"\s+content="([^"]+)"`)
 	match = ogTitleRegex.FindStringSubmatch(content)
 	if len(match) > 1 {
 		title := strings.TrimSpace(match[1])
@@ -181,7 +189,6 @@ func extractHTMLTitle(content string) string {
 	}
 
 	// 3. Try <meta property="twitter:title">
-	twitterTitleRegex := regexp.MustCompile(`<meta\s+property="twitter:title"\s+content="([^"]+)"`)
 	match = twitterTitleRegex.FindStringSubmatch(content)
 	if len(match) > 1 {
 		title := strings.TrimSpace(match[1])
@@ -191,7 +198,6 @@ func extractHTMLTitle(content string) string {
 	}
 
 	// 4. Try H1 tag
-	h1Regex := regexp.MustCompile(`<h1[^>]*>([^<]+)</h1>`)
 	match = h1Regex.FindStringSubmatch(content)
 	if len(match) > 1 {
 		title := strings.TrimSpace(match[1])
@@ -220,11 +226,10 @@ func cleanTitle(title string) string {
 	title = strings.ReplaceAll(title, "&nbsp;", " ")
 	
 	// Remove emojis and other Unicode symbols (keep only basic ASCII letters, numbers, spaces, and common punctuation)
-	reg := regexp.MustCompile(`[^\x20-\x7E]`)
-	title = reg.ReplaceAllString(title, "")
+	title = nonASCIIRegex.ReplaceAllString(title, "")
 	
 	// Clean up multiple spaces
-	title = regexp.MustCompile(`\s+`).ReplaceAllString(title, " ")
+	title = spacesRegex.ReplaceAllString(title, " ")
 	title = strings.TrimSpace(title)
 	
 	return title
@@ -253,14 +258,13 @@ func generateCheatsheetID(path string) string {
 	// Replace slashes with hyphens
 	cleanPath = strings.Replace(cleanPath, "/", "-", -1)
 	// Replace any invalid characters with underscores
-	reg := regexp.MustCompile(`[^a-zA-Z0-9\-_]`)
-	cleanPath = reg.ReplaceAllString(cleanPath, "_")
+	cleanPath = invalidIDChars.ReplaceAllString(cleanPath, "_")
 	// Add prefix
 	return fmt.Sprintf("cheatsheets-%s", cleanPath)
 }
 
 func RunCheatsheetsOnly(ctx context.Context, start time.Time) {
-	fmt.Println("📖 Generating cheatsheets data only...")
+	slog.Info("📖 Generating cheatsheets data only...")
 
 	cheatsheets, err := generateCheatsheetsData(ctx)
 	if err != nil {
@@ -273,30 +277,30 @@ func RunCheatsheetsOnly(ctx context.Context, start time.Time) {
 	}
 
 	elapsed := time.Since(start)
-	fmt.Printf("\n🎉 Cheatsheets data generation completed in %v\n", elapsed)
-	fmt.Printf("📊 Generated %d cheatsheets\n", len(cheatsheets))
+	slog.Info(fmt.Sprintf("\n🎉 Cheatsheets data generation completed in %v", elapsed), "category", "cheatsheets", "elapsed", elapsed.String())
+	slog.Info(fmt.Sprintf("📊 Generated %d cheatsheets", len(cheatsheets)), "category", "cheatsheets", "count", len(cheatsheets))
 
 	// Show sample data
-	fmt.Println("\n📝 Sample cheatsheets:")
+	slog.Info("\n📝 Sample cheatsheets:")
 	for i, sheet := range cheatsheets {
 		if i >= 10 { // Show first 10
-			fmt.Printf("  ... and %d more cheatsheets\n", len(cheatsheets)-10)
+			slog.Info(fmt.Sprintf("  ... and %d more cheatsheets", len(cheatsheets)-10))
 			break
 		}
-		fmt.Printf("  %d. %s (ID: %s)\n", i+1, sheet.Name, sheet.ID)
+		slog.Info(fmt.Sprintf("  %d. %s (ID: %s)", i+1, sheet.Name, sheet.ID))
 		if sheet.Description != "" {
-			fmt.Printf("     Description: %s\n", truncateString(sheet.Description, 80))
+			slog.Info(fmt.Sprintf("     Description: %s", truncateString(sheet.Description, 80)))
 		}
-		fmt.Printf("     Path: %s\n", sheet.Path)
-		fmt.Println()
+		slog.Info(fmt.Sprintf("     Path: %s", sheet.Path))
+		slog.Info("")
 	}
 
-	fmt.Printf("💾 Data saved to output/cheatsheets.json\n")
+	slog.Info(fmt.Sprintf("💾 Data saved to %s", filepath.Join(outputDir, "cheatsheets.json")))
 	
 	// Automatically run stem processing
-	fmt.Println("\n🔍 Running stem processing...")
-	if err := jargon_stemmer.ProcessJSONFile("output/cheatsheets.json"); err != nil {
+	slog.Info("\n🔍 Running stem processing...")
+	if err := stemJSONFile(filepath.Join(outputDir, "cheatsheets.json")); err != nil {
 		log.Fatalf("❌ Stem processing failed: %v", err)
 	}
-	fmt.Println("✅ Stem processing completed!")
+	slog.Info("✅ Stem processing completed!")
 }
\ No newline at end of file
@@ -6,16 +6,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"log/slog"
 	"path/filepath"
 	"regexp"
-	jargon_stemmer "search-index/jargon-stemmer"
 	"sort"
 	"strings"
 	"time"
 )
 
 func generateEmojisData(ctx context.Context) ([]EmojiData, error) {
-	fmt.Println("😀 Generating emojis data...")
+	slog.Info("😀 Generating emojis data...")
 
 	// Path to emoji data files
 	basePath := "../frontend/public/emoji_data"
@@ -36,7 +36,7 @@ func generateEmojisData(ctx context.Context) ([]EmojiData, error) {
 
 		data, err := processEmojiFile(file)
 		if err != nil {
-			fmt.Printf("⚠️  Warning: Failed to process %s: %v\n", file, err)
+			slog.Warn(fmt.Sprintf("⚠️  Warning: Failed to process %s: %v", file, err))
 			continue
 		}
 
@@ -50,7 +50,7 @@ func generateEmojisData(ctx context.Context) ([]EmojiData, error) {
 		return emojisData[i].ID < emojisData[j].ID
 	})
 
-	fmt.Printf("😀 Processed %d emoji files\n", len(emojisData))
+	slog.Info(fmt.Sprintf("😀 Processed %d emoji files", len(emojisData)))
 	return emojisData, nil
 }
 
@@ -126,18 +126,22 @@ func processEmojiFile(filePath string) (*EmojiData, error) {
 	return emojiDataResult, nil
 }
 
+// HTML tags and entities removed from emoji descriptions, compiled once rather than for every emoji
+var (
+	htmlTagRegex    = regexp.MustCompile(`<[^>]*>`)
+	htmlEntityRegex = regexp.MustCompile(`&[a-zA-Z0-9#]+;`)
+)
+
 func cleanDescription(text string) string {
 	if text == "" {
 		return ""
 	}
 
 	// Remove HTML tags
-	htmlRegex := regexp.MustCompile(`<[^>]*>`)
-	text = htmlRegex.ReplaceAllString(text, "")
+	text = htmlTagRegex.ReplaceAllString(text, "")
 
 	// Remove HTML entities
-	entityRegex := regexp.MustCompile(`&[a-zA-Z0-9#]+;`)
-	text = entityRegex.ReplaceAllString(text, "")
+	text = htmlEntityRegex.ReplaceAllString(text, "")
 
 	// Clean up whitespace
 	text = strings.TrimSpace(text)
@@ -158,7 +162,7 @@ func findNestedDefinition(emojiData EmojiJSONData) string {
 
 
 func RunEmojisOnly(ctx context.Context, start time.Time) {
-	fmt.Println("😀 Generating emojis data only...")
+	slog.Info("😀 Generating emojis data only...")
 
 	emojis, err := generateEmojisData(ctx)
 	if err != nil {
@@ -171,30 +175,30 @@ func RunEmojisOnly(ctx context.Context, start time.Time) {
 	}
 
 	elapsed := time.Since(start)
-	fmt.Printf("\n🎉 Emojis data generation completed in %v\n", elapsed)
-	fmt.Printf("📊 Generated %d emojis\n", len(emojis))
+	slog.Info(fmt.Sprintf("\n🎉 Emojis data generation completed in %v", elapsed), "category", "emojis", "elapsed", elapsed.String())
+	slog.Info(fmt.Sprintf("📊 Generated %d emojis", len(emojis)), "category", "emojis", "count", len(emojis))
 
 	// Show sample data
-	fmt.Println("\n📝 Sample emojis:")
+	slog.Info("\n📝 Sample emojis:")
 	for i, emoji := range emojis {
 		if i >= 10 { // Show first 10
-			fmt.Printf("  ... and %d more emojis\n", len(emojis)-10)
+			slog.Info(fmt.Sprintf("  ... and %d more emojis", len(emojis)-10))
 			break
 		}
-		fmt.Printf("  %d. %s %s (ID: %s)\n", i+1, emoji.Name, emoji.Code, emoji.ID)
+		slog.Info(fmt.Sprintf("  %d. %s %s (ID: %s)", i+1, emoji.Name, emoji.Code, emoji.ID))
 		if emoji.Description != "" {
-			fmt.Printf("     Description: %s\n", truncateString(emoji.Description, 80))
+			slog.Info(fmt.Sprintf("     Description: %s", truncateString(emoji.Description, 80)))
 		}
-		fmt.Printf("     Path: %s\n", emoji.Path)
-		fmt.Println()
+		slog.Info(fmt.Sprintf("     Path: %s", emoji.Path))
+		slog.Info("")
 	}
 
-	fmt.Printf("💾 Data saved to output/emojis.json\n")
+	slog.Info(fmt.Sprintf("💾 Data saved to %s", filepath.Join(outputDir, "emojis.json")))
 	
 	// Automatically run stem processing
-	fmt.Println("\n🔍 Running stem processing...")
-	if err := jargon_stemmer.ProcessJSONFile("output/emojis.json"); err != nil {
+	slog.Info("\n🔍 Running stem processing...")
+	if err := stemJSONFile(filepath.Join(outputDir, "emojis.json")); err != nil {
 		log.Fatalf("❌ Stem processing failed: %v", err)
 	}
-	fmt.Println("✅ Stem processing completed!")
+	slog.Info("✅ Stem processing completed!")
 }
\ No newline at end of file
@@ -0,0 +1,40 @@
+package main
+
+// svgAlgoliaFile holds the SVG icons in Algolia's batch upload format
+const svgAlgoliaFile = "svg_icons_algolia.json"
+
+// AlgoliaObject is an icon record as uploaded to Algolia, keyed by objectID
+type AlgoliaObject struct {
+	ObjectID    string   `json:"objectID"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	Path        string   `json:"path"`
+	Image       string   `json:"image"`
+	Tags        []string `json:"tags,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+}
+
+// toAlgoliaObjects converts icons to Algolia records, using the icon ID as objectID
+// so re-uploads replace existing records instead of duplicating them
+func toAlgoliaObjects(icons []SVGIconData) []AlgoliaObject {
+	objects := make([]AlgoliaObject, 0, len(icons))
+	for _, icon := range icons {
+		objects = append(objects, AlgoliaObject{
+			ObjectID:    icon.ID,
+			Name:        icon.Name,
+			Description: icon.Description,
+			Category:    icon.Category,
+			Path:        icon.Path,
+			Image:       icon.Image,
+			Tags:        icon.Tags,
+			Keywords:    icon.Keywords,
+		})
+	}
+	return objects
+}
+
+// saveAlgoliaExport writes the icons to svg_icons_algolia.json, ready for Algolia's saveObjects
+func saveAlgoliaExport(icons []SVGIconData) error {
+	return saveToJSON(svgAlgoliaFile, toAlgoliaObjects(icons))
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// svgCSVFile holds the icon catalog as a spreadsheet for reviewing metadata
+const svgCSVFile = "svg_icons.csv"
+
+// svgCSVHeader lists the columns of svg_icons.csv
+var svgCSVHeader = []string{"ID", "Name", "Description", "Category", "Path", "Image"}
+
+// saveCSVExport writes the icons to svg_icons.csv, one row per icon in the order of the
+// JSON output. encoding/csv quotes fields containing commas, quotes or newlines.
+func saveCSVExport(icons []SVGIconData) error {
+	if err := ensureOutputDir(); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	return createFileAtomic(filepath.Join(outputDir, svgCSVFile), func(w io.Writer) error {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(svgCSVHeader); err != nil {
+			return err
+		}
+		for _, icon := range icons {
+			if err := writer.Write([]string{icon.ID, icon.Name, icon.Description, icon.Category, icon.Path, icon.Image}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	// svgMeiliFile holds the SVG icons as Meilisearch documents, with id as the primary key
+	svgMeiliFile = "svg_icons_meili.json"
+	// svgMeiliSettingsFile holds the index settings to apply before adding the documents
+	svgMeiliSettingsFile = "svg_icons_meili_settings.json"
+	// meiliMaxIDLength is the longest document ID Meilisearch accepts, in bytes
+	meiliMaxIDLength = 511
+)
+
+// MeiliDocument is an icon document as added to Meilisearch
+type MeiliDocument struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	Path        string   `json:"path"`
+	Image       string   `json:"image"`
+	Tags        []string `json:"tags,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	Colors      []string `json:"colors,omitempty"`
+}
+
+// MeiliSettings is the subset of Meilisearch index settings the icons need
+type MeiliSettings struct {
+	SearchableAttributes []string `json:"searchableAttributes"`
+	FilterableAttributes []string `json:"filterableAttributes"`
+	RankingRules         []string `json:"rankingRules"`
+}
+
+// svgMeiliSettings searches names and authored keywords first, then descriptions and tags, and lets clients filter
+// by category and color. The ranking rules are Meilisearch's defaults, spelled out so the
+// index does not change if the defaults do.
+var svgMeiliSettings = MeiliSettings{
+	SearchableAttributes: []string{"name", "keywords", "description", "tags"},
+	FilterableAttributes: []string{"category", "colors"},
+	RankingRules:         []string{"words", "typo", "proximity", "attribute", "sort", "exactness"},
+}
+
+// meiliDocumentID turns an icon ID into a valid Meilisearch primary key: only a-z, A-Z, 0-9,
+// hyphens and underscores, which sanitizeID already guarantees, and at most 511 bytes. Longer
+// IDs keep their start and end with a hash of the whole ID, so they stay unique.
+func meiliDocumentID(id string) string {
+	id = sanitizeID(id)
+	if len(id) <= meiliMaxIDLength {
+		return id
+	}
+	sum := sha256.Sum256([]byte(id))
+	suffix := "-" + hex.EncodeToString(sum[:8])
+	return id[:meiliMaxIDLength-len(suffix)] + suffix
+}
+
+// toMeiliDocuments converts icons to Meilisearch documents keyed by their sanitized ID
+func toMeiliDocuments(icons []SVGIconData) []MeiliDocument {
+	documents := make([]MeiliDocument, 0, len(icons))
+	for _, icon := range icons {
+		documents = append(documents, MeiliDocument{
+			ID:          meiliDocumentID(icon.ID),
+			Name:        icon.Name,
+			Description: icon.Description,
+			Category:    icon.Category,
+			Path:        icon.Path,
+			Image:       icon.Image,
+			Tags:        icon.Tags,
+			Keywords:    icon.Keywords,
+			Colors:      icon.Colors,
+		})
+	}
+	return documents
+}
+
+// saveMeiliExport writes svg_icons_meili.json and svg_icons_meili_settings.json, ready for
+// PATCH /indexes/svg_icons/settings and POST /indexes/svg_icons/documents?primaryKey=id
+func saveMeiliExport(icons []SVGIconData) error {
+	if err := saveToJSON(svgMeiliSettingsFile, svgMeiliSettings); err != nil {
+		return err
+	}
+	return saveToJSON(svgMeiliFile, toMeiliDocuments(icons))
+}
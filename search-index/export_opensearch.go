@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// svgOpenSearchFile holds the SVG icons as an OpenSearch/Elasticsearch _bulk request body
+const svgOpenSearchFile = "svg_icons_opensearch.ndjson"
+
+// defaultOpenSearchIndex is the index the bulk actions target unless --opensearch-index is set
+const defaultOpenSearchIndex = "svg_icons"
+
+// OpenSearchAction is the action line preceding each document of a _bulk request
+type OpenSearchAction struct {
+	Index OpenSearchActionMeta `json:"index"`
+}
+
+// OpenSearchActionMeta names the index and document ID of a bulk index action
+type OpenSearchActionMeta struct {
+	Index string `json:"_index"`
+	ID    string `json:"_id"`
+}
+
+// OpenSearchDocument is an icon document as indexed in OpenSearch
+type OpenSearchDocument struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	Path        string   `json:"path"`
+	Tags        []string `json:"tags,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+}
+
+// saveOpenSearchExport writes svg_icons_opensearch.ndjson with an index action and a document
+// for every icon, using the icon ID as _id so reloading replaces documents instead of
+// duplicating them. Load it with:
+//
+//	curl -H 'Content-Type: application/x-ndjson' -XPOST localhost:9200/_bulk --data-binary @svg_icons_opensearch.ndjson
+func saveOpenSearchExport(icons []SVGIconData, index string) error {
+	if err := ensureOutputDir(); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	return createFileAtomic(filepath.Join(outputDir, svgOpenSearchFile), func(w io.Writer) error {
+		// Encode ends every line with the newline the bulk API requires, the last one included
+		encoder := json.NewEncoder(w)
+		for _, icon := range icons {
+			if err := encoder.Encode(OpenSearchAction{Index: OpenSearchActionMeta{Index: index, ID: icon.ID}}); err != nil {
+				return err
+			}
+			document := OpenSearchDocument{
+				Name:        icon.Name,
+				Description: icon.Description,
+				Category:    icon.Category,
+				Path:        icon.Path,
+				Tags:        icon.Tags,
+				Keywords:    icon.Keywords,
+			}
+			if err := encoder.Encode(document); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
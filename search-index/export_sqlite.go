@@ -0,0 +1,101 @@
+//go:build sqlite
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema creates the tables of svg_icons.db:
+//
+//	icons      one row per icon: id (primary key), path, image and category
+//	icons_fts  FTS5 table over name, description and space separated tags, with id stored unindexed
+//	           so matches can be joined back to icons
+//
+// Example query:
+//
+//	SELECT icons.* FROM icons_fts JOIN icons USING (id)
+//	WHERE icons_fts MATCH 'arrow' ORDER BY rank;
+const sqliteSchema = `
+CREATE TABLE icons (
+	id       TEXT PRIMARY KEY,
+	path     TEXT NOT NULL,
+	image    TEXT NOT NULL,
+	category TEXT NOT NULL
+);
+CREATE VIRTUAL TABLE icons_fts USING fts5(
+	id UNINDEXED,
+	name,
+	description,
+	tags,
+	tokenize = 'porter unicode61'
+);
+`
+
+// sqliteSupported reports whether this binary was built with the sqlite tag
+const sqliteSupported = true
+
+// saveSQLiteExport writes the icons to svg_icons.db in the output directory.
+// The database is built under a temporary name and renamed into place when complete.
+func saveSQLiteExport(icons []SVGIconData) error {
+	if err := ensureOutputDir(); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	fullPath := filepath.Join(outputDir, svgSQLiteFile)
+	tmpPath := fullPath + ".tmp"
+	os.Remove(tmpPath)
+	defer os.Remove(tmpPath)
+
+	if err := writeSQLiteDB(tmpPath, icons); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, fullPath)
+}
+
+func writeSQLiteDB(filePath string, icons []SVGIconData) error {
+	db, err := sql.Open("sqlite", filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insertIcon, err := tx.Prepare(`INSERT INTO icons (id, path, image, category) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertIcon.Close()
+
+	insertFTS, err := tx.Prepare(`INSERT INTO icons_fts (id, name, description, tags) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertFTS.Close()
+
+	for _, icon := range icons {
+		if _, err := insertIcon.Exec(icon.ID, icon.Path, icon.Image, icon.Category); err != nil {
+			return fmt.Errorf("failed to insert icon %s: %w", icon.ID, err)
+		}
+		if _, err := insertFTS.Exec(icon.ID, icon.Name, icon.Description, strings.Join(icon.Tags, " ")); err != nil {
+			return fmt.Errorf("failed to index icon %s: %w", icon.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
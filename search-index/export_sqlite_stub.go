@@ -0,0 +1,13 @@
+//go:build !sqlite
+
+package main
+
+import "fmt"
+
+// sqliteSupported reports whether this binary was built with the sqlite tag
+const sqliteSupported = false
+
+// saveSQLiteExport is unavailable unless built with -tags sqlite, which pulls in the SQLite driver
+func saveSQLiteExport(icons []SVGIconData) error {
+	return fmt.Errorf("SQLite export requires building with -tags sqlite")
+}
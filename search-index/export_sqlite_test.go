@@ -0,0 +1,61 @@
+//go:build sqlite
+
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteSQLiteDB(t *testing.T) {
+	icons := []SVGIconData{
+		{ID: "svg-icons-feather-arrow-up", Name: "Arrow Up", Description: "Points upwards", Path: "/freedevtools/svg_icons/feather/arrow-up/", Image: "/svg_icons/feather/arrow-up.svg", Category: "Feather", Tags: []string{"arrow", "up"}},
+		{ID: "svg-icons-feather-home", Name: "Home", Description: "A house with an arrow on the door", Path: "/freedevtools/svg_icons/feather/home/", Image: "/svg_icons/feather/home.svg", Category: "Feather", Tags: []string{"home"}},
+		{ID: "svg-icons-feather-trash", Name: "Trash", Description: "Delete an item", Path: "/freedevtools/svg_icons/feather/trash/", Image: "/svg_icons/feather/trash.svg", Category: "Feather", Tags: []string{"bin"}},
+	}
+	dbPath := filepath.Join(t.TempDir(), svgSQLiteFile)
+	if err := writeSQLiteDB(dbPath, icons); err != nil {
+		t.Fatalf("writeSQLiteDB: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// The porter tokenizer stems "arrows"; the name match ranks above the description one
+	match := func(query string) []string {
+		t.Helper()
+		rows, err := db.Query(`SELECT icons.id FROM icons_fts JOIN icons USING (id) WHERE icons_fts MATCH ? ORDER BY bm25(icons_fts, 0, 10, 1, 1)`, query)
+		if err != nil {
+			t.Fatalf("MATCH %q: %v", query, err)
+		}
+		defer rows.Close()
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	if got, want := match("arrows"), []string{"svg-icons-feather-arrow-up", "svg-icons-feather-home"}; !reflect.DeepEqual(got, want) {
+		t.Errorf(`MATCH "arrows" = %v, want %v`, got, want)
+	}
+	if got, want := match("tags:bin"), []string{"svg-icons-feather-trash"}; !reflect.DeepEqual(got, want) {
+		t.Errorf(`MATCH "tags:bin" = %v, want %v`, got, want)
+	}
+
+	var image, category string
+	if err := db.QueryRow(`SELECT image, category FROM icons WHERE id = ?`, "svg-icons-feather-home").Scan(&image, &category); err != nil {
+		t.Fatal(err)
+	}
+	if image != icons[1].Image || category != "Feather" {
+		t.Errorf("icons row = %s, %s, want %s, Feather", image, category, icons[1].Image)
+	}
+}
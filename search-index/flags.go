@@ -0,0 +1,422 @@
+package main
+
+import (
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	jargon_stemmer "search-index/jargon-stemmer"
+	"search-index/svgicons"
+)
+
+// svgOptions holds the command line options for SVG icon generation. The options of the
+// generation itself are those of svgicons.Options, the rest select and shape the output.
+type svgOptions struct {
+	svgicons.Options
+	OutDir               string                 // Directory all generated files are written to, for every category
+	Compact              bool                   // Write the generated JSON files without indentation
+	Profile              string                 // cpu or mem to write a pprof profile of the run, empty for none
+	ProfileFile          string                 // File the profile is written to, <Profile>.pprof when empty
+	Formats              []string               // Output formats for svg_icons, e.g. json, ndjson, algolia
+	OpenSearchIndex      string                 // Index named in the bulk actions of --format opensearch
+	Gzip                 bool                   // Write svg_icons.json.gz instead of svg_icons.json
+	GzipLevel            int                    // compress/gzip level used with Gzip
+	Langs                listFlag               // Languages to also write svg_icons.<lang>.json for, from translations/<lang>.json
+	Strict               bool                   // Fail the run if any warning was recorded
+	Allow                listFlag               // Warning kinds that do not fail a --strict run
+	Stemmer              jargon_stemmer.Stemmer // Stemmer for the SVG stem step and search index, from --stemmer
+	StemmerName          string                 // Name of Stemmer, recorded in the manifest
+	Force                bool                   // Regenerate even if the manifest says nothing changed
+	NGrams               bool                   // Add character n-grams to the SVG search index
+	NGramSize            int                    // Length of the n-grams, 3 for trigrams
+	Phonetic             bool                   // Add Double Metaphone codes of icon names to the SVG search index
+	Watch                bool                   // Regenerate whenever the cluster file or SVG source folders change
+	ValidateOnly         bool                   // Only check the cluster files and the SVG files they list, writing nothing
+	IndexOnly            bool                   // Only re-stem the existing svg_icons.json and rebuild its index and autocomplete, skipping the clusters
+	EmitSchema           bool                   // Write svg_icons.schema.json describing svg_icons.json
+	EmitOffsets          bool                   // Write svg_icons.offsets.json with the byte range of every record of svg_icons.json
+	VerifyOutput         bool                   // Read svg_icons.json back before it replaces the previous one, failing unless it holds the icons written
+	MaxOutputBytes       int64                  // Fail once svg_icons.json (or .json.gz) is written larger than this, 0 for no budget
+	ReportSimilarNames   bool                   // List the icons with near-identical names, writing svg_icons_similar_names.json
+	ReportDiff           bool                   // Print the icons added, removed and changed since the previous svg_icons.json
+	ReportDiffJSON       bool                   // Also write the ReportDiff comparison to changes.json
+	ComparePython        string                 // svg_icons.json of the Python generator to compare IDs, names and paths with, writing python_parity.json
+	SimilarNamesDistance int                    // Largest Levenshtein distance of names reported by ReportSimilarNames
+	ReportVisualDupes    bool                   // List the groups of icons drawing the same paths, writing svg_icons_visual_dupes.json
+	ReportTokens         bool                   // List the document frequency of every stemmed token, writing token_frequency.json
+	Sitemap              bool                   // Write sitemap.xml listing the page of every icon
+	SitemapBaseURL       string                 // Site URL the icon paths are appended to in the sitemap
+	LogLevel             slog.Level             // Least severe level logged, from --log-level
+	LogFormat            string                 // text for the friendly output, json for structured records
+	ServePort            int                    // Port the serve subcommand listens on
+}
+
+// listFlag is a flag value that can be repeated and takes comma separated values,
+// e.g. --allow missing-file,no-viewbox --allow duplicate-id
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, svgicons.SplitList(value)...)
+	return nil
+}
+
+// svgOutputFormats lists the supported values of --format
+var svgOutputFormats = []string{"json", "ndjson", "algolia", "sqlite", "csv", "opensearch", "meilisearch"}
+
+// svgSQLiteFile is the offline full-text search database for SVG icons, written by --format sqlite
+const svgSQLiteFile = "svg_icons.db"
+
+// parseSVGOptions parses --flag style options from the command line.
+// Positional arguments such as category=svg_icons and stem=file.json may appear anywhere.
+func parseSVGOptions(args []string) (svgOptions, error) {
+	var opts svgOptions
+
+	fs := flag.NewFlagSet("search-index", flag.ContinueOnError)
+	fs.Var((*listFlag)(&opts.ClusterPaths), "cluster", "cluster file or glob to read, repeatable or comma separated, merged into one (default $CLUSTER_SVG_PATH or "+svgicons.DefaultClusterPath+")")
+	fs.IntVar(&opts.ClusterReadAttempts, "cluster-read-attempts", 3, "attempts at reading a cluster file that is busy or still being written before failing")
+	fs.DurationVar(&opts.ClusterReadBackoff, "cluster-read-backoff", 200*time.Millisecond, "wait before retrying a cluster file read, doubled after each further attempt")
+	fs.StringVar(&opts.OutDir, "out-dir", "output", "directory generated files are written to (created if missing)")
+	fs.StringVar(&opts.Profile, "profile", "", "write a pprof profile of the run for go tool pprof: cpu for CPU samples, mem for heap allocations")
+	fs.StringVar(&opts.ProfileFile, "profile-file", "", "file the --profile profile is written to (default cpu.pprof or mem.pprof)")
+	fs.BoolVar(&opts.Compact, "compact", false, "write the generated JSON files minified instead of indented, with the same content and key order")
+	format := fs.String("format", "json", "comma separated output formats for SVG icons: "+strings.Join(svgOutputFormats, ", "))
+	fs.StringVar(&opts.OpenSearchIndex, "opensearch-index", defaultOpenSearchIndex, "index named in the bulk actions written by --format opensearch")
+	fs.Var(&opts.Langs, "lang", "language to also write svg_icons.<lang>.json for, with names from "+svgTranslationsDir+"/<lang>.json, repeatable or comma separated")
+	fs.BoolVar(&opts.Gzip, "gzip", false, "write the SVG icons JSON gzip compressed as svg_icons.json.gz")
+	fs.IntVar(&opts.GzipLevel, "gzip-level", gzip.DefaultCompression, "gzip compression level (1-9, -1 for default)")
+	fs.IntVar(&opts.Workers, "workers", 0, "number of workers processing SVG icons (default GOMAXPROCS)")
+	fs.IntVar(&opts.MaxDescriptionLen, "max-description-length", 0, "cut SVG icon descriptions longer than this many characters at a word boundary with an ellipsis (0 for no limit)")
+	fs.BoolVar(&opts.KeepFullDescription, "keep-full-description", false, "keep the full text of descriptions cut by --max-description-length in descriptionFull")
+	fs.StringVar(&opts.ModifiedFrom, "modified-from", svgicons.ModifiedFromMtime, "where the modifiedAt of SVG icons comes from: mtime for the file modification time, git for the committer date of the file's last commit, or none")
+	fs.StringVar(&opts.Hidden, "hidden", svgicons.HiddenStrip, "what to do with SVG files whose name starts with an underscore: strip indexes them with the underscore dropped from the name and path, skip leaves them out, system indexes them with system set and the underscore kept in the path and ID")
+	fs.StringVar(&opts.Sort, "sort", svgicons.SortID, "order of the SVG icons in the output: id, name, category (source folder, then name) or none for cluster traversal order")
+	fs.BoolVar(&opts.Verify, "verify", false, "fail if any indexed SVG file changed, appeared or disappeared since "+svgicons.LockFile+" was written, listing them")
+	fs.BoolVar(&opts.UpdateLock, "update-lock", false, "rewrite "+svgicons.LockFile+" with the SHA-256 of every indexed SVG file, after vetting asset changes")
+	fs.BoolVar(&opts.ContinueOnError, "continue-on-error", false, "leave out the SVG cluster files and clusters that fail to load instead of stopping, then exit 1 with a report of every cluster and icon that failed")
+	fs.StringVar(&opts.Source, "source", "", "directory, HTTP base URL or .tar.gz/.tgz/.tar file or URL holding the SVG files laid out like "+svgicons.IconsDir+", for clusters without their own \"source\"; append #dir to use a subdirectory of a tarball")
+	fs.DurationVar(&opts.SourceTimeout, "source-timeout", svgicons.DefaultSourceTimeout, "longest download of a tarball or SVG file from a remote --source or cluster source")
+	fs.StringVar(&opts.BasePath, "base-path", "", "public path the SVG icon pages are served under, e.g. /preview/svg_icons/ (default $"+svgicons.BasePathEnv+" or /freedevtools/svg_icons/); IDs do not change")
+	fs.StringVar(&opts.IDStyle, "id-style", svgicons.IDStyleSlug, "SVG icon IDs: slug for readable ones like svg-icons-feather-home, hash for svg-icons- plus 10 base32 characters of the path, also writing "+svgHashIDsFile)
+	fs.BoolVar(&opts.Dedupe, "dedupe", false, "fold SVG icons with identical content into one icon listing the others as aliases")
+	fs.BoolVar(&opts.GroupVariants, "group-variants", false, "fold SVG icons differing only by a style suffix, e.g. home-filled and home-outline, into one icon listing them as variants")
+	fs.Var((*listFlag)(&opts.VariantSuffixes), "variant-suffixes", "style suffixes recognized by --group-variants, repeatable or comma separated (default "+strings.Join(svgicons.DefaultVariantSuffixes, ",")+")")
+	fs.BoolVar(&opts.ValidateOnly, "validate-only", false, "only check the cluster files and that the SVG files they list exist, exiting non-zero on any problem; writes nothing")
+	fs.BoolVar(&opts.IndexOnly, "index-only", false, "skip the clusters and SVG files: load the existing svg_icons.json, re-stem it and rebuild the search index, autocomplete and --emit-offsets offsets with the current stemmer, synonyms, stop words and field boosts; with --dry-run only print how the index would change")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "generate SVG icons in memory and print a summary without writing files")
+	stemmerName := fs.String("stemmer", "default", "stemmer for SVG icons: "+strings.Join(jargon_stemmer.StemmerNames(), ", "))
+	fs.BoolVar(&opts.NGrams, "ngrams", false, "add character n-grams of icon words to the SVG search index for substring matching")
+	fs.IntVar(&opts.NGramSize, "ngram-size", 3, "length of the n-grams added by --ngrams")
+	fs.BoolVar(&opts.Phonetic, "phonetic", false, "add Double Metaphone codes of icon names to the SVG search index for misspelled queries")
+	fs.BoolVar(&opts.Related, "related", false, "list the icons sharing the most tags on each SVG icon")
+	fs.IntVar(&opts.RelatedCount, "related-count", 8, "number of related icons listed per icon by --related")
+	fs.BoolVar(&opts.Optimize, "optimize", false, "strip comments, <metadata>, XML declarations and empty groups from SVG icons, writing them to svg_icons/ in the output directory")
+	fs.BoolVar(&opts.Rasterize, "rasterize", false, "render each SVG icon to a raster fallback written next to it, e.g. home.64.png, recorded in rasterImage")
+	fs.IntVar(&opts.RasterSize, "raster-size", svgicons.DefaultRasterSize, "width and height in pixels of the --rasterize fallbacks")
+	fs.StringVar(&opts.RasterFormat, "raster-format", svgicons.RasterPNG, "format of the --rasterize fallbacks: "+strings.Join(svgicons.RasterFormats, " or "))
+	fs.BoolVar(&opts.InlineSVG, "inline-svg", false, "embed each minified SVG icon as a base64 data URI in dataUri")
+	fs.IntVar(&opts.InlineSVGMaxBytes, "inline-svg-max-bytes", 4096, "largest minified SVG embedded by --inline-svg, 0 for no limit")
+	fs.IntVar(&opts.ComplexityThreshold, "complexity-threshold", 500, "flag SVG icons whose complexity (drawing elements plus path commands) is above this in the summary and stats.json")
+	fs.BoolVar(&opts.ReportDiff, "report-diff", false, "compare the SVG icons with the existing svg_icons.json by ID before overwriting it, printing the added, removed and changed icons and fields")
+	fs.BoolVar(&opts.ReportDiffJSON, "report-diff-json", false, "also write the --report-diff comparison to "+svgChangesFile+" (implies --report-diff)")
+	fs.StringVar(&opts.ComparePython, "compare-python", "", "svg_icons.json (or .json.gz) written by the legacy Python generator for the same clusters: pair its icons with the Go ones by image, report the IDs, names and paths that differ and the images only one side indexed in "+svgParityFile+", and exit 1 unless they all match")
+	fs.BoolVar(&opts.ReportSimilarNames, "report-similar-names", false, "list pairs of SVG icons in the same folder whose names differ only by case, punctuation or a few letters, also writing "+svgSimilarNamesFile)
+	fs.BoolVar(&opts.ReportVisualDupes, "report-visual-dupes", false, "list groups of SVG icons drawing the same path geometry under different names, also writing "+svgVisualDupesFile)
+	fs.BoolVar(&opts.ReportTokens, "report-tokens", false, "list the stemmed SVG icon tokens by the number of icons containing them, stop words included, also writing "+svgTokenFrequencyFile+" to help curate "+jargon_stemmer.StopWordsFile)
+	fs.IntVar(&opts.SimilarNamesDistance, "similar-names-distance", 2, "largest Levenshtein distance between names reported by --report-similar-names")
+	fs.BoolVar(&opts.VerifyOutput, "verify-output", false, "read svg_icons.json back after writing and after stemming, failing before it replaces the previous file unless it holds every icon ID in order")
+	fs.Int64Var(&opts.MaxOutputBytes, "max-output-bytes", 0, "fail the run once svg_icons.json is written larger than this many bytes, checking the compressed svg_icons.json.gz with --gzip (0 for no budget)")
+	fs.BoolVar(&opts.EmitOffsets, "emit-offsets", false, "also write "+svgOffsetsFile+", the byte offset and length of every icon record in svg_icons.json by ID, for fetching one icon with a range request")
+	fs.BoolVar(&opts.EmitSchema, "emit-schema", false, "also write svg_icons.schema.json, a JSON Schema (draft 2020-12) of svg_icons.json")
+	fs.BoolVar(&opts.Sitemap, "sitemap", false, "also write sitemap.xml with the page of every SVG icon, sharded with a sitemap index past 50000 icons")
+	fs.StringVar(&opts.SitemapBaseURL, "sitemap-base-url", defaultSitemapBaseURL, "site URL the icon paths are appended to in sitemap.xml")
+	fs.BoolVar(&opts.Force, "force", false, "regenerate SVG icons even if inputs are unchanged since the last run")
+	fs.BoolVar(&opts.NoCache, "no-cache", false, "read and parse every SVG file instead of reusing the metadata cached for files whose size and modification time are unchanged")
+	fs.BoolVar(&opts.Incremental, "incremental", false, "only reprocess SVG clusters that changed since the last run")
+	since := fs.String("since", "", "only reprocess SVG files modified after this RFC3339 time or this long ago, e.g. 2024-05-01T00:00:00Z or 24h, reusing the last run's data for the rest")
+	fs.IntVar(&opts.ServePort, "port", 8080, "port the serve subcommand answers /search and /autocomplete on, bound to localhost")
+	fs.BoolVar(&opts.Watch, "watch", false, "regenerate SVG icons whenever the cluster file or SVG files change, until Ctrl-C")
+	logLevel := fs.String("log-level", "info", "least severe messages logged: debug, info, warn, error")
+	fs.StringVar(&opts.LogFormat, "log-format", "text", "log output format: "+strings.Join(logFormats, ", "))
+	fs.StringVar(&opts.Folder, "folder", "", "only process SVG clusters whose source folder matches this glob, e.g. feather or material*")
+	fs.IntVar(&opts.Limit, "limit", 0, "only process the first N SVG cluster files, in cluster key order, for quick test runs (0 for all)")
+	fs.BoolVar(&opts.Strict, "strict", false, "exit with an error and a summary of the warnings if any SVG icon warning was recorded")
+	fs.Var(&opts.Allow, "allow", "warning kind that does not fail --strict, repeatable or comma separated: "+strings.Join(svgicons.WarningKinds, ", "))
+
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			return opts, err
+		}
+		args = fs.Args()
+		if len(args) > 0 {
+			// Skip the positional argument and keep parsing flags after it
+			args = args[1:]
+		}
+	}
+
+	for _, f := range strings.Split(*format, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if !svgicons.ContainsString(svgOutputFormats, f) {
+			return opts, fmt.Errorf("unknown format %q, expected one of: %s", f, strings.Join(svgOutputFormats, ", "))
+		}
+		if f == "sqlite" && !sqliteSupported {
+			return opts, fmt.Errorf("--format sqlite requires building with -tags sqlite")
+		}
+		opts.Formats = append(opts.Formats, f)
+	}
+	if len(opts.Formats) == 0 {
+		opts.Formats = []string{"json"}
+	}
+	if opts.ReportDiffJSON {
+		opts.ReportDiff = true
+	}
+	if opts.ReportDiff && !opts.hasFormat("json") {
+		return opts, fmt.Errorf("--report-diff compares with svg_icons.json, so it needs --format json")
+	}
+	if opts.VerifyOutput && !opts.hasFormat("json") {
+		return opts, fmt.Errorf("--verify-output checks svg_icons.json, so it needs --format json")
+	}
+	if opts.IndexOnly && !opts.hasFormat("json") {
+		return opts, fmt.Errorf("--index-only rebuilds the index from svg_icons.json, so it needs --format json")
+	}
+	if opts.IndexOnly && (opts.Watch || opts.ValidateOnly) {
+		return opts, fmt.Errorf("--index-only reads no cluster files, so it cannot be combined with --watch or --validate-only")
+	}
+	if opts.MaxOutputBytes < 0 {
+		return opts, fmt.Errorf("--max-output-bytes must not be negative, got %d", opts.MaxOutputBytes)
+	}
+	if opts.MaxOutputBytes > 0 && !opts.hasFormat("json") {
+		return opts, fmt.Errorf("--max-output-bytes checks svg_icons.json, so it needs --format json")
+	}
+	if opts.EmitOffsets && (!opts.hasFormat("json") || opts.Gzip) {
+		return opts, fmt.Errorf("--emit-offsets points into the uncompressed svg_icons.json, so it needs --format json without --gzip")
+	}
+
+	level, err := parseLogLevel(*logLevel)
+	if err != nil {
+		return opts, err
+	}
+	opts.LogLevel = level
+	opts.LogFormat = strings.ToLower(opts.LogFormat)
+	if !svgicons.ContainsString(logFormats, opts.LogFormat) {
+		return opts, fmt.Errorf("unknown log format %q, expected one of: %s", opts.LogFormat, strings.Join(logFormats, ", "))
+	}
+
+	stemmer, err := jargon_stemmer.StemmerByName(*stemmerName)
+	if err != nil {
+		return opts, err
+	}
+	opts.Stemmer = stemmer
+	opts.StemmerName = strings.ToLower(*stemmerName)
+
+	if strings.TrimSpace(opts.OutDir) == "" {
+		return opts, fmt.Errorf("--out-dir must not be empty")
+	}
+	opts.CacheDir = opts.OutDir
+
+	opts.Profile = strings.ToLower(opts.Profile)
+	if opts.Profile != "" && !svgicons.ContainsString(profileKinds, opts.Profile) {
+		return opts, fmt.Errorf("unknown --profile %q, expected one of: %s", opts.Profile, strings.Join(profileKinds, ", "))
+	}
+	if opts.ProfileFile != "" && opts.Profile == "" {
+		return opts, fmt.Errorf("--profile-file needs --profile cpu or mem")
+	}
+	if opts.Profile != "" && opts.ProfileFile == "" {
+		opts.ProfileFile = defaultProfileFile(opts.Profile)
+	}
+
+	if opts.ClusterReadAttempts < 1 {
+		return opts, fmt.Errorf("invalid --cluster-read-attempts %d, must be at least 1", opts.ClusterReadAttempts)
+	}
+	if opts.ClusterReadBackoff < 0 {
+		return opts, fmt.Errorf("invalid --cluster-read-backoff %v, must not be negative", opts.ClusterReadBackoff)
+	}
+
+	if opts.Workers < 0 {
+		return opts, fmt.Errorf("invalid --workers %d, must not be negative", opts.Workers)
+	}
+
+	if opts.Verify && opts.UpdateLock {
+		return opts, fmt.Errorf("--verify and --update-lock cannot be combined, verify first then update the lock once the changes are vetted")
+	}
+
+	if opts.ServePort < 1 || opts.ServePort > 65535 {
+		return opts, fmt.Errorf("invalid --port %d, expected 1-65535", opts.ServePort)
+	}
+
+	if baseURL, err := url.Parse(opts.SitemapBaseURL); err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return opts, fmt.Errorf("--sitemap-base-url must be an absolute URL such as %s, got %q", defaultSitemapBaseURL, opts.SitemapBaseURL)
+	}
+
+	if *since != "" {
+		cutoff, err := parseSince(*since, time.Now())
+		if err != nil {
+			return opts, err
+		}
+		opts.Since = cutoff
+		if opts.Incremental {
+			return opts, fmt.Errorf("--since and --incremental cannot be combined, pick one way of finding changed files")
+		}
+	}
+
+	if _, err := path.Match(opts.Folder, ""); err != nil {
+		return opts, fmt.Errorf("invalid --folder pattern %q: %w", opts.Folder, err)
+	}
+
+	if len(opts.VariantSuffixes) == 0 {
+		opts.VariantSuffixes = svgicons.DefaultVariantSuffixes
+	}
+	for _, suffix := range opts.VariantSuffixes {
+		if strings.ContainsAny(suffix, "/-_ ") {
+			return opts, fmt.Errorf("invalid --variant-suffixes entry %q, must be a single word like filled", suffix)
+		}
+	}
+
+	var langs listFlag
+	for _, lang := range opts.Langs {
+		if !svgLangPattern.MatchString(lang) {
+			return opts, fmt.Errorf("invalid --lang %q, expected a language code such as fr or pt-BR", lang)
+		}
+		if !svgicons.ContainsString(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	opts.Langs = langs
+
+	opts.Sort = strings.ToLower(opts.Sort)
+	if !svgicons.ContainsString(svgicons.SortOrders, opts.Sort) {
+		return opts, fmt.Errorf("unknown --sort %q, expected one of: %s", opts.Sort, strings.Join(svgicons.SortOrders, ", "))
+	}
+
+	opts.ModifiedFrom = strings.ToLower(opts.ModifiedFrom)
+	if !svgicons.ContainsString(svgicons.ModifiedFromSources, opts.ModifiedFrom) {
+		return opts, fmt.Errorf("unknown --modified-from %q, expected one of: %s", opts.ModifiedFrom, strings.Join(svgicons.ModifiedFromSources, ", "))
+	}
+
+	opts.Hidden = strings.ToLower(opts.Hidden)
+	if !svgicons.ContainsString(svgicons.HiddenPolicies, opts.Hidden) {
+		return opts, fmt.Errorf("unknown --hidden %q, expected one of: %s", opts.Hidden, strings.Join(svgicons.HiddenPolicies, ", "))
+	}
+
+	if basePath := opts.ResolveBasePath(); strings.ContainsAny(basePath, " \t\n?#") || strings.Contains(basePath, "://") {
+		return opts, fmt.Errorf("invalid base path %q (from --base-path or $%s), expected a URL path such as /preview/svg_icons/", basePath, svgicons.BasePathEnv)
+	}
+
+	opts.IDStyle = strings.ToLower(opts.IDStyle)
+	if !svgicons.ContainsString(svgicons.IDStyles, opts.IDStyle) {
+		return opts, fmt.Errorf("unknown --id-style %q, expected one of: %s", opts.IDStyle, strings.Join(svgicons.IDStyles, ", "))
+	}
+
+	for _, kind := range opts.Allow {
+		if !svgicons.ContainsString(svgicons.WarningKinds, kind) {
+			return opts, fmt.Errorf("unknown warning kind %q for --allow, expected one of: %s", kind, strings.Join(svgicons.WarningKinds, ", "))
+		}
+	}
+
+	if strings.TrimSpace(opts.OpenSearchIndex) == "" {
+		return opts, fmt.Errorf("--opensearch-index must not be empty")
+	}
+
+	if opts.Limit < 0 {
+		return opts, fmt.Errorf("invalid --limit %d, must not be negative", opts.Limit)
+	}
+
+	if opts.NGramSize < 2 {
+		return opts, fmt.Errorf("invalid --ngram-size %d, must be at least 2", opts.NGramSize)
+	}
+
+	if opts.RelatedCount < 1 {
+		return opts, fmt.Errorf("invalid --related-count %d, must be at least 1", opts.RelatedCount)
+	}
+
+	if opts.SimilarNamesDistance < 0 || opts.SimilarNamesDistance > 3 {
+		return opts, fmt.Errorf("invalid --similar-names-distance %d, expected 0 to 3", opts.SimilarNamesDistance)
+	}
+
+	if opts.ComplexityThreshold < 0 {
+		return opts, fmt.Errorf("invalid --complexity-threshold %d, must not be negative", opts.ComplexityThreshold)
+	}
+
+	if opts.MaxDescriptionLen < 0 {
+		return opts, fmt.Errorf("invalid --max-description-length %d, must not be negative", opts.MaxDescriptionLen)
+	}
+
+	if opts.RasterSize < 1 || opts.RasterSize > 4096 {
+		return opts, fmt.Errorf("invalid --raster-size %d, expected 1 to 4096", opts.RasterSize)
+	}
+
+	opts.RasterFormat = strings.ToLower(opts.RasterFormat)
+	if opts.RasterFormat == "jpg" {
+		opts.RasterFormat = svgicons.RasterJPEG
+	}
+	if !svgicons.ContainsString(svgicons.RasterFormats, opts.RasterFormat) {
+		return opts, fmt.Errorf("unknown --raster-format %q, expected one of: %s", opts.RasterFormat, strings.Join(svgicons.RasterFormats, ", "))
+	}
+
+	if opts.InlineSVGMaxBytes < 0 {
+		return opts, fmt.Errorf("invalid --inline-svg-max-bytes %d, must not be negative", opts.InlineSVGMaxBytes)
+	}
+
+	if opts.GzipLevel < gzip.HuffmanOnly || opts.GzipLevel > gzip.BestCompression {
+		return opts, fmt.Errorf("invalid --gzip-level %d, expected -2 to 9", opts.GzipLevel)
+	}
+
+	return opts, nil
+}
+
+// parseSince parses --since as an RFC3339 time or a duration before now such as 24h or 90m
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if cutoff, err := time.Parse(time.RFC3339, value); err == nil {
+		return cutoff, nil
+	}
+	ago, err := time.ParseDuration(value)
+	if err != nil || ago <= 0 {
+		return time.Time{}, fmt.Errorf("invalid --since %q, expected an RFC3339 time such as 2024-05-01T00:00:00Z or a positive duration such as 24h", value)
+	}
+	return now.Add(-ago), nil
+}
+
+// svgJSONFile returns the name of the SVG icons JSON output file
+func (o svgOptions) svgJSONFile() string {
+	if o.Gzip {
+		return "svg_icons.json.gz"
+	}
+	return "svg_icons.json"
+}
+
+// svgStopWords are in nearly every icon name or description, so they do not help search
+var svgStopWords = []string{"icon", "icons", "svg"}
+
+// stemOptions returns the stem step options for the SVG icon output files and search index
+func (o svgOptions) stemOptions() *jargon_stemmer.Options {
+	return &jargon_stemmer.Options{
+		Fields:    []string{"Name", "Description", "Tags", "Keywords"},
+		Stemmer:   o.Stemmer,
+		StopWords: svgStopWords,
+		Compact:   o.Compact,
+	}
+}
+
+// ngramSize returns the n-gram length for the search index, 0 when --ngrams is off
+func (o svgOptions) ngramSize() int {
+	if !o.NGrams {
+		return 0
+	}
+	return o.NGramSize
+}
+
+// hasFormat reports whether the given output format was selected
+func (o svgOptions) hasFormat(format string) bool {
+	return svgicons.ContainsString(o.Formats, format)
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-05-01T00:00:00Z", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"24h", time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+		{"90m", time.Date(2024, 5, 2, 10, 30, 0, 0, time.UTC)},
+	}
+	for _, tc := range tests {
+		got, err := parseSince(tc.value, now)
+		if err != nil || !got.Equal(tc.want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", tc.value, got, err, tc.want)
+		}
+	}
+	for _, value := range []string{"yesterday", "-24h", "0s", "2024-05-01"} {
+		if _, err := parseSince(value, now); err == nil {
+			t.Errorf("parseSince(%q) succeeded, want an error", value)
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package jargon_stemmer
 
 import (
+	"bufio"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
@@ -14,7 +19,6 @@ import (
 	"github.com/clipperhouse/jargon"
 	"github.com/clipperhouse/jargon/filters/ascii"
 	"github.com/clipperhouse/jargon/filters/contractions"
-	"github.com/clipperhouse/jargon/filters/stemmer"
 )
 
 type JSONObject struct {
@@ -23,6 +27,7 @@ type JSONObject struct {
 	AltName         string `json:"altName,omitempty"`         // Processed version of name
 	Description     string `json:"description,omitempty"`
 	AltDescription  string `json:"altDescription,omitempty"`  // Processed version of description
+	AltSynonyms     string `json:"altSynonyms,omitempty"`     // Synonyms of the processed tokens, from synonyms.json
 	Path            string `json:"path,omitempty"`
 	Image           string `json:"image,omitempty"`
 	Category        string `json:"category,omitempty"`
@@ -33,11 +38,29 @@ type JSONObject struct {
 }
 
 func ProcessText(text string) string {
-	// Apply all filters: Contractions, ASCII fold, and Stem
+	return ProcessTextWith(text, Default)
+}
+
+// ProcessTextWith is ProcessText using the given stemmer, Default if nil
+func ProcessTextWith(text string, s Stemmer) string {
+	return ProcessTextOptions(text, Options{Stemmer: s})
+}
+
+// ProcessTextOptions is ProcessText using the stemmer and extra stop words of opts
+func ProcessTextOptions(text string, opts Options) string {
+	s := opts.Stemmer
+	if s == nil {
+		s = Default
+	}
+
+	// Apply all filters: Contractions, ASCII fold, Stop words, irregular plurals, and Stem
 	stream := jargon.TokenizeString(text).
 		Filter(contractions.Expand).
-		Filter(ascii.Fold).
-		Filter(stemmer.English)
+		Filter(ascii.Fold)
+	if !opts.KeepStopWords {
+		stream = stream.Filter(stopWordFilter(opts.StopWords))
+	}
+	stream = stream.Filter(singularFilter).Filter(stemFilter(s))
 	
 	var results []string
 	for stream.Scan() {
@@ -56,97 +79,128 @@ func ProcessText(text string) string {
 	return strings.Join(results, " ")
 }
 
+// stemObject fills in the processed versions of the name and description
+func stemObject(obj *JSONObject) {
+	obj.AltName = ProcessText(obj.Name)
+
+	// Process description field if it exists
+	if obj.Description != "" {
+		obj.AltDescription = ProcessText(obj.Description)
+	}
+
+	// Synonyms are kept apart from the processed text so exact matches still rank highest
+	tokens := strings.Fields(obj.AltName + " " + obj.AltDescription)
+	obj.AltSynonyms = strings.Join(ExpandSynonyms(tokens), " ")
+}
+
+// ProcessJSONFile stems a JSON array file in place.
+// Files ending in .gz are decompressed transparently and written back compressed.
 func ProcessJSONFile(filePath string) error {
-	fmt.Printf("🔍 Processing JSON file: %s\n", filePath)
+	return ProcessJSONFileLevel(filePath, gzip.DefaultCompression)
+}
+
+// ProcessJSONFileLevel is ProcessJSONFile with the gzip level used to rewrite .gz files
+func ProcessJSONFileLevel(filePath string, gzipLevel int) error {
+	return ProcessJSONFileWithOptions(filePath, gzipLevel, nil)
+}
+
+// ProcessJSONFileWithOptions stems the fields listed in opts. With nil options it writes
+// altName and altDescription like ProcessJSONFile.
+func ProcessJSONFileWithOptions(filePath string, gzipLevel int, opts *Options) error {
+	return ProcessJSONFileContext(context.Background(), filePath, gzipLevel, opts)
+}
+
+func printStatistics(processedCount int64, numWorkers int, elapsed time.Duration) {
+	slog.Info("✅ Processing completed!")
+	slog.Info("📈 Statistics:")
+	slog.Info(fmt.Sprintf("   • Entries processed: %d", processedCount))
+	slog.Info(fmt.Sprintf("   • Workers used: %d", numWorkers))
+	slog.Info(fmt.Sprintf("   • Time taken: %v", elapsed))
+	if processedCount > 0 {
+		slog.Info(fmt.Sprintf("   • Average time per entry: %v", elapsed/time.Duration(processedCount)))
+	}
+}
+
+// ProcessNDJSONFile stems a newline delimited JSON file one record at a time,
+// so the whole file never has to be held in memory
+func ProcessNDJSONFile(filePath string) error {
+	return ProcessNDJSONFileWithOptions(filePath, nil)
+}
+
+// ProcessNDJSONFileWithOptions is ProcessNDJSONFile stemming the fields listed in opts
+func ProcessNDJSONFileWithOptions(filePath string, opts *Options) error {
+	slog.Info(fmt.Sprintf("🔍 Processing NDJSON file: %s", filePath))
 	start := time.Now()
-	
-	// Read JSON file
-	data, err := ioutil.ReadFile(filePath)
+
+	in, err := os.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("error reading file %s: %v", filePath, err)
 	}
-	
-	// Parse JSON
-	var objects []JSONObject
-	if err := json.Unmarshal(data, &objects); err != nil {
-		return fmt.Errorf("error parsing JSON: %v", err)
-	}
-	
-	fmt.Printf("📊 Found %d entries to process\n", len(objects))
-	
-	// Get number of workers (CPU count - 1)
-	numWorkers := runtime.NumCPU() - 1
-	if numWorkers < 1 {
-		numWorkers = 1
+	defer in.Close()
+
+	out, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating temp file for %s: %v", filePath, err)
 	}
-	
-	fmt.Printf("🚀 Using %d workers for parallel processing\n", numWorkers)
-	
-	// Process objects in parallel using goroutines
-	var wg sync.WaitGroup
-	processedCount := int64(0)
-	var mu sync.Mutex
-	
-	// Channel to distribute work
-	workChan := make(chan int, numWorkers*2)
-	
-	// Start workers
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for i := range workChan {
-				// Process name field
-				originalName := objects[i].Name
-				processedName := ProcessText(originalName)
-				objects[i].AltName = processedName
-				
-				// Process description field if it exists
-				if objects[i].Description != "" {
-					originalDescription := objects[i].Description
-					processedDescription := ProcessText(originalDescription)
-					objects[i].AltDescription = processedDescription
-				}
-				
-				// Update counter safely
-				mu.Lock()
-				processedCount++
-				mu.Unlock()
-				
-				// Show first few examples
-				
-				// Progress update removed - no more spam
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	writer := bufio.NewWriter(out)
+	encoder := json.NewEncoder(writer)
+
+	processedCount := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var obj interface{}
+		if opts == nil {
+			var jsonObject JSONObject
+			if err := json.Unmarshal(line, &jsonObject); err != nil {
+				return fmt.Errorf("error parsing JSON on line %d: %v", processedCount+1, err)
 			}
-		}()
+			stemObject(&jsonObject)
+			obj = jsonObject
+		} else {
+			var r record
+			if err := json.Unmarshal(line, &r); err != nil {
+				return fmt.Errorf("error parsing JSON on line %d: %v", processedCount+1, err)
+			}
+			stemRecord(&r, *opts)
+			obj = r
+		}
+
+		if err := encoder.Encode(obj); err != nil {
+			return fmt.Errorf("error marshaling JSON: %v", err)
+		}
+		processedCount++
 	}
-	
-	// Send work to workers
-	for i := range objects {
-		workChan <- i
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading file %s: %v", filePath, err)
 	}
-	close(workChan)
-	
-	// Wait for all workers to complete
-	wg.Wait()
-	
-	// Write back to file
-	outputData, err := json.MarshalIndent(objects, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error marshaling JSON: %v", err)
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
 	}
-	
-	if err := ioutil.WriteFile(filePath, outputData, 0644); err != nil {
+	if err := out.Close(); err != nil {
 		return fmt.Errorf("error writing file %s: %v", filePath, err)
 	}
-	
-	elapsed := time.Since(start)
-	fmt.Printf("✅ Processing completed!\n")
-	fmt.Printf("📈 Statistics:\n")
-	fmt.Printf("   • Entries processed: %d\n", processedCount)
-	fmt.Printf("   • Workers used: %d\n", numWorkers)
-	fmt.Printf("   • Time taken: %v\n", elapsed)
-	fmt.Printf("   • Average time per entry: %v\n", elapsed/time.Duration(processedCount))
-	
+	if err := os.Chmod(out.Name(), 0644); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
+	}
+	if err := os.Rename(out.Name(), filePath); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
+	}
+
+	slog.Info("✅ Processing completed!")
+	slog.Info("📈 Statistics:")
+	slog.Info(fmt.Sprintf("   • Entries processed: %d", processedCount))
+	slog.Info(fmt.Sprintf("   • Time taken: %v", time.Since(start)))
+
 	return nil
 }
 
@@ -158,7 +212,7 @@ func main() {
 	
 	jsonFile := os.Args[1]
 	
-	fmt.Printf("🔍 Processing JSON file: %s\n", jsonFile)
+	slog.Info(fmt.Sprintf("🔍 Processing JSON file: %s", jsonFile))
 	start := time.Now()
 	
 	// Read JSON file
@@ -173,7 +227,7 @@ func main() {
 		log.Fatalf("Error parsing JSON: %v", err)
 	}
 	
-	fmt.Printf("📊 Found %d entries to process\n", len(objects))
+	slog.Info(fmt.Sprintf("📊 Found %d entries to process", len(objects)))
 	
 	// Get number of workers (CPU count - 1)
 	numWorkers := runtime.NumCPU() - 1
@@ -181,7 +235,7 @@ func main() {
 		numWorkers = 1
 	}
 	
-	fmt.Printf("🚀 Using %d workers for parallel processing\n", numWorkers)
+	slog.Info(fmt.Sprintf("🚀 Using %d workers for parallel processing", numWorkers))
 	
 	// Process objects in parallel using goroutines
 	var wg sync.WaitGroup
@@ -241,10 +295,10 @@ func main() {
 	}
 	
 	elapsed := time.Since(start)
-	fmt.Printf("✅ Processing completed!\n")
-	fmt.Printf("📈 Statistics:\n")
-	fmt.Printf("   • Entries processed: %d\n", processedCount)
-	fmt.Printf("   • Workers used: %d\n", numWorkers)
-	fmt.Printf("   • Time taken: %v\n", elapsed)
-	fmt.Printf("   • Average time per entry: %v\n", elapsed/time.Duration(processedCount))
+	slog.Info("✅ Processing completed!")
+	slog.Info("📈 Statistics:")
+	slog.Info(fmt.Sprintf("   • Entries processed: %d", processedCount))
+	slog.Info(fmt.Sprintf("   • Workers used: %d", numWorkers))
+	slog.Info(fmt.Sprintf("   • Time taken: %v", elapsed))
+	slog.Info(fmt.Sprintf("   • Average time per entry: %v", elapsed/time.Duration(processedCount)))
 }
@@ -0,0 +1,201 @@
+package jargon_stemmer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options selects which fields of each record are stemmed and where the result goes,
+// so the stemmer works on any array of JSON objects
+type Options struct {
+	// Fields are the string fields to stem, matched case-insensitively against the
+	// JSON keys, e.g. ["Name", "Description"]. Arrays of strings are stemmed as their
+	// elements joined by spaces. Missing or empty fields are skipped. When there are
+	// none, records are stemmed like ProcessJSONFile does with nil options.
+	Fields []string
+
+	// TargetField receives the stemmed tokens of all Fields joined by spaces, e.g.
+	// "SearchTerms" is written as "searchTerms". When empty, each field gets its own
+	// alt field instead: Name is written to altName, Description to altDescription.
+	TargetField string
+
+	// Stemmer stems each word, Default if nil
+	Stemmer Stemmer
+
+	// StopWords are dropped before stemming on top of the English list and stopwords.txt,
+	// e.g. words present in nearly every record of a category
+	StopWords []string
+
+	// KeepStopWords stems stop words instead of dropping them, e.g. to count how often
+	// they occur when curating stopwords.txt
+	KeepStopWords bool
+
+	// Compact writes JSON array files without indentation or newlines. The records hold
+	// the same keys in the same order either way.
+	Compact bool
+
+	// Verify, if set, checks the rewritten file at tmpPath before it replaces the original,
+	// which is left untouched when Verify fails. tmpPath is compressed like the original.
+	Verify func(tmpPath string) error
+}
+
+// recordField is a single key of a record, kept in file order
+type recordField struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// record is a JSON object whose keys are preserved in order and whose values are left
+// untouched, so fields the stemmer does not know about survive the rewrite
+type record []recordField
+
+func (r *record) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected a JSON object")
+	}
+
+	*r = (*r)[:0]
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		*r = append(*r, recordField{Key: token.(string), Value: value})
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+func (r record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(field.Value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// lookup returns the index of the key matching name case-insensitively, or -1
+func (r record) lookup(name string) int {
+	for i, field := range r {
+		if strings.EqualFold(field.Key, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// stringField returns the index and string value of a field, joining arrays of strings
+// with spaces, or "" if it is missing or neither
+func (r record) stringField(name string) (int, string) {
+	i := r.lookup(name)
+	if i < 0 {
+		return -1, ""
+	}
+	var value string
+	if err := json.Unmarshal(r[i].Value, &value); err == nil {
+		return i, value
+	}
+	var values []string
+	if err := json.Unmarshal(r[i].Value, &values); err == nil {
+		return i, strings.Join(values, " ")
+	}
+	return -1, ""
+}
+
+// set replaces the value of an existing key matching name, or inserts key after the
+// field at index after (appending when after is -1). Returns the index of the field.
+func (r *record) set(name, key, value string, after int) int {
+	encoded, _ := json.Marshal(value)
+	if i := r.lookup(name); i >= 0 {
+		(*r)[i].Value = encoded
+		return i
+	}
+	if after < 0 || after >= len(*r) {
+		*r = append(*r, recordField{Key: key, Value: encoded})
+		return len(*r) - 1
+	}
+	*r = append(*r, recordField{})
+	copy((*r)[after+2:], (*r)[after+1:])
+	(*r)[after+1] = recordField{Key: key, Value: encoded}
+	return after + 1
+}
+
+// remove deletes the field matching name case-insensitively, returning its index or -1
+func (r *record) remove(name string) int {
+	i := r.lookup(name)
+	if i >= 0 {
+		*r = append((*r)[:i], (*r)[i+1:]...)
+	}
+	return i
+}
+
+// stemRecord stems the fields selected by opts and writes the processed text.
+// Alt fields are placed right after their source field, matching ProcessJSONFile's output.
+// Re-stemming a record drops the alt fields and synonyms the current settings no longer produce.
+func stemRecord(r *record, opts Options) {
+	var stemmed []string
+	last := -1 // Index of the last field written
+	for _, name := range opts.Fields {
+		i, value := r.stringField(name)
+		if value == "" {
+			// A source field emptied since the last run leaves no stale alt field behind
+			if opts.TargetField == "" {
+				if removed := r.remove("alt" + upperFirst(name)); removed >= 0 && removed < last {
+					last--
+				}
+			}
+			continue
+		}
+		processed := ProcessTextOptions(value, opts)
+		stemmed = append(stemmed, processed)
+		if opts.TargetField == "" {
+			altKey := "alt" + upperFirst((*r)[i].Key)
+			last = r.set(altKey, altKey, processed, i)
+		}
+	}
+
+	if opts.TargetField != "" {
+		last = r.set(opts.TargetField, lowerFirst(opts.TargetField), strings.Join(stemmed, " "), -1)
+	}
+
+	// Synonyms are kept apart from the processed text so exact matches still rank highest
+	if synonymTokens := ExpandSynonyms(strings.Fields(strings.Join(stemmed, " "))); len(synonymTokens) > 0 {
+		r.set("altSynonyms", "altSynonyms", strings.Join(synonymTokens, " "), last)
+	} else {
+		r.remove("altSynonyms")
+	}
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
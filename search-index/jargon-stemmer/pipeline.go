@@ -0,0 +1,262 @@
+package jargon_stemmer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stemJob is a record read from the input file, waiting to be stemmed
+type stemJob struct {
+	Index int
+	Raw   json.RawMessage
+}
+
+// stemResult is a stemmed record, indented and ready to be written at Index
+type stemResult struct {
+	Index int
+	Data  []byte
+	Err   error
+}
+
+// ProcessJSONFileContext stems a JSON array file in place. Records are read, stemmed by a
+// pool of workers and written back in their original order as a pipeline, so the file is
+// never fully held in memory. Files ending in .gz are decompressed and written back
+// compressed at gzipLevel. With nil options it writes altName and altDescription like
+// ProcessJSONFile. The original file is left untouched if ctx is cancelled.
+func ProcessJSONFileContext(ctx context.Context, filePath string, gzipLevel int, opts *Options) error {
+	if opts != nil {
+		slog.Info(fmt.Sprintf("🔍 Processing JSON file: %s (fields: %s)", filePath, strings.Join(opts.Fields, ", ")))
+	} else {
+		slog.Info(fmt.Sprintf("🔍 Processing JSON file: %s", filePath))
+	}
+	start := time.Now()
+
+	in, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("error reading file %s: %v", filePath, err)
+	}
+	defer in.Close()
+
+	var reader io.Reader = bufio.NewReader(in)
+	if strings.HasSuffix(filePath, ".gz") {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return fmt.Errorf("error reading file %s: %v", filePath, err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	out, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating temp file for %s: %v", filePath, err)
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	buffered := bufio.NewWriter(out)
+	var writer io.Writer = buffered
+	var gzWriter *gzip.Writer
+	if strings.HasSuffix(filePath, ".gz") {
+		gzWriter, err = gzip.NewWriterLevel(buffered, gzipLevel)
+		if err != nil {
+			return fmt.Errorf("error writing file %s: %v", filePath, err)
+		}
+		writer = gzWriter
+	}
+
+	// Get number of workers (CPU count - 1)
+	numWorkers := runtime.NumCPU() - 1
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	slog.Info(fmt.Sprintf("🚀 Using %d workers for parallel processing", numWorkers))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan stemJob, numWorkers*2)
+	results := make(chan stemResult, numWorkers*2)
+
+	// Read records one at a time
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(jobs)
+		readErr <- readJSONArray(ctx, reader, jobs)
+	}()
+
+	// Stem records in parallel
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				data, err := stemJSON(job.Raw, opts)
+				select {
+				case results <- stemResult{Index: job.Index, Data: data, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Write records back in input order
+	processedCount, err := writeJSONArray(writer, results, opts != nil && opts.Compact)
+	if err != nil {
+		return fmt.Errorf("error processing file %s: %v", filePath, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := <-readErr; err != nil {
+		return fmt.Errorf("error parsing JSON: %v", err)
+	}
+
+	if gzWriter != nil {
+		if err := gzWriter.Close(); err != nil {
+			return fmt.Errorf("error writing file %s: %v", filePath, err)
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
+	}
+	if err := os.Chmod(out.Name(), 0644); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
+	}
+	if opts != nil && opts.Verify != nil {
+		if err := opts.Verify(out.Name()); err != nil {
+			return fmt.Errorf("stemmed %s failed verification, keeping the original: %w", filePath, err)
+		}
+	}
+	if err := os.Rename(out.Name(), filePath); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
+	}
+
+	printStatistics(processedCount, numWorkers, time.Since(start))
+	return nil
+}
+
+// readJSONArray decodes the elements of a JSON array and sends them to jobs in order
+func readJSONArray(ctx context.Context, r io.Reader, jobs chan<- stemJob) error {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected a JSON array")
+	}
+
+	for index := 0; decoder.More(); index++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return err
+		}
+		select {
+		case jobs <- stemJob{Index: index, Raw: raw}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+// stemJSON stems a single record and returns it as an element of the output array,
+// indented unless opts is Compact
+func stemJSON(raw json.RawMessage, opts *Options) ([]byte, error) {
+	var obj interface{}
+	if opts == nil || len(opts.Fields) == 0 {
+		var jsonObject JSONObject
+		if err := json.Unmarshal(raw, &jsonObject); err != nil {
+			return nil, err
+		}
+		stemObject(&jsonObject)
+		obj = jsonObject
+	} else {
+		var r record
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, err
+		}
+		stemRecord(&r, *opts)
+		obj = r
+	}
+	if opts != nil && opts.Compact {
+		return json.Marshal(obj)
+	}
+	return json.MarshalIndent(obj, "  ", "  ")
+}
+
+// writeJSONArray writes the results as a JSON array, indented unless compact, holding back
+// results that arrive ahead of their turn. Returns the number of records written.
+func writeJSONArray(w io.Writer, results <-chan stemResult, compact bool) (int64, error) {
+	pending := make(map[int][]byte)
+	next := 0
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+	for result := range results {
+		if result.Err != nil {
+			return int64(next), fmt.Errorf("record %d: %v", result.Index+1, result.Err)
+		}
+		pending[result.Index] = result.Data
+
+		for {
+			data, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+
+			separator := ",\n  "
+			switch {
+			case compact && next == 0:
+				separator = ""
+			case compact:
+				separator = ","
+			case next == 0:
+				separator = "\n  "
+			}
+			if _, err := io.WriteString(w, separator); err != nil {
+				return int64(next), err
+			}
+			if _, err := w.Write(data); err != nil {
+				return int64(next), err
+			}
+			next++
+		}
+	}
+
+	closing := "\n]"
+	if next == 0 || compact {
+		closing = "]"
+	}
+	if _, err := io.WriteString(w, closing); err != nil {
+		return int64(next), err
+	}
+	return int64(next), nil
+}
@@ -0,0 +1,175 @@
+package jargon_stemmer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testRecord is a synthetic record shaped like those of svg_icons.json
+type testRecord struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	AltName        string   `json:"altName,omitempty"`
+	Description    string   `json:"description"`
+	AltDescription string   `json:"altDescription,omitempty"`
+	Tags           []string `json:"tags"`
+}
+
+// writeTestRecords writes a JSON array of count synthetic records to filePath, compressed
+// when it ends in .gz, and returns its size in bytes
+func writeTestRecords(tb testing.TB, filePath string, count int) int64 {
+	tb.Helper()
+	records := make([]testRecord, count)
+	for i := range records {
+		records[i] = testRecord{
+			ID:          fmt.Sprintf("svg-icons-set-%d-running-arrows", i),
+			Name:        fmt.Sprintf("Running Arrows %d", i),
+			Description: "Arrows pointing to the connected settings of the running applications",
+			Tags:        []string{"arrows", "running", "settings"},
+		}
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if filepath.Ext(filePath) == ".gz" {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(data)
+		gz.Close()
+		data = buf.Bytes()
+	}
+	if err := ioutil.WriteFile(filePath, data, 0644); err != nil {
+		tb.Fatal(err)
+	}
+	return int64(len(data))
+}
+
+// readTestRecords reads the records of a JSON array file, decompressing .gz files
+func readTestRecords(t *testing.T, filePath string) []testRecord {
+	t.Helper()
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Ext(filePath) == ".gz" {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data, err = ioutil.ReadAll(gz); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var records []testRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatalf("stemmed file is not a JSON array of records: %v", err)
+	}
+	return records
+}
+
+func TestProcessJSONFileContextKeepsOrder(t *testing.T) {
+	// Enough records for every worker to get many, so out of order results are likely
+	for _, name := range []string{"records.json", "records.json.gz"} {
+		filePath := filepath.Join(t.TempDir(), name)
+		const count = 2000
+		writeTestRecords(t, filePath, count)
+
+		if err := ProcessJSONFileContext(context.Background(), filePath, gzip.BestSpeed, &Options{Fields: []string{"Name", "Description"}}); err != nil {
+			t.Fatalf("%s: ProcessJSONFileContext: %v", name, err)
+		}
+
+		records := readTestRecords(t, filePath)
+		if len(records) != count {
+			t.Fatalf("%s: got %d records, want %d", name, len(records), count)
+		}
+		for i, r := range records {
+			if want := fmt.Sprintf("svg-icons-set-%d-running-arrows", i); r.ID != want {
+				t.Fatalf("%s: record %d is %s, want %s", name, i, r.ID, want)
+			}
+			if want := fmt.Sprintf("run arrow %d", i); r.AltName != want {
+				t.Errorf("%s: record %d altName = %q, want %q", name, i, r.AltName, want)
+			}
+		}
+	}
+}
+
+func TestProcessJSONFileContextEmptyArray(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "empty.json")
+	if err := ioutil.WriteFile(filePath, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ProcessJSONFileContext(context.Background(), filePath, gzip.DefaultCompression, nil); err != nil {
+		t.Fatalf("ProcessJSONFileContext: %v", err)
+	}
+	if records := readTestRecords(t, filePath); len(records) != 0 {
+		t.Errorf("got %d records, want none", len(records))
+	}
+}
+
+func TestProcessJSONFileContextCancelled(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "records.json")
+	writeTestRecords(t, filePath, 500)
+	original, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := ProcessJSONFileContext(ctx, filePath, gzip.DefaultCompression, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ProcessJSONFileContext after cancel = %v, want context.Canceled", err)
+	}
+
+	// The original is left as it was and the temporary file is removed
+	if got, _ := ioutil.ReadFile(filePath); !bytes.Equal(got, original) {
+		t.Error("the cancelled run changed the original file")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("%d files left in the directory, want only the original", len(entries))
+	}
+}
+
+func TestProcessJSONFileContextMalformed(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "broken.json")
+	content := []byte(`[{"id": "a", "name": "Home"}, {"id": "b", "name": `)
+	if err := ioutil.WriteFile(filePath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ProcessJSONFileContext(context.Background(), filePath, gzip.DefaultCompression, nil); err == nil {
+		t.Fatal("ProcessJSONFileContext of a truncated file succeeded")
+	}
+	if got, _ := ioutil.ReadFile(filePath); !bytes.Equal(got, content) {
+		t.Error("the failed run changed the original file")
+	}
+}
+
+// BenchmarkProcessJSONFile measures the stem step on a synthetic 50k record file, the size
+// of a large icon catalog. Each iteration re-stems the file written by the last one.
+func BenchmarkProcessJSONFile(b *testing.B) {
+	const records = 50000
+	filePath := filepath.Join(b.TempDir(), "records.json")
+	b.SetBytes(writeTestRecords(b, filePath, records))
+	opts := &Options{Fields: []string{"Name", "Description", "Tags"}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ProcessJSONFileContext(context.Background(), filePath, gzip.DefaultCompression, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(records)*float64(b.N)/b.Elapsed().Seconds(), "records/s")
+}
@@ -0,0 +1,70 @@
+package jargon_stemmer
+
+import (
+	"strings"
+
+	"github.com/clipperhouse/jargon"
+	"github.com/clipperhouse/jargon/filters/mapper"
+)
+
+// irregularPlurals maps plurals the Snowball stemmers do not reduce to the stem of their
+// singular, e.g. "mice" stays "mice" while "mouse" gives "mous", to that singular. Regular
+// -s, -es and -ies plurals already stem like their singular and are not listed, except the
+// -ses, -zzes and -es ones Snowball gets wrong ("buses" → "buse"). Ambiguous plurals such as
+// "media" and "data", also used as singulars, are left alone.
+var irregularPlurals = map[string]string{
+	"analyses":   "analysis",
+	"appendices": "appendix",
+	"axes":       "axis",
+	"buses":      "bus",
+	"cacti":      "cactus",
+	"calves":     "calf",
+	"children":   "child",
+	"crises":     "crisis",
+	"criteria":   "criterion",
+	"diagnoses":  "diagnosis",
+	"dice":       "die",
+	"elves":      "elf",
+	"feet":       "foot",
+	"fungi":      "fungus",
+	"geese":      "goose",
+	"halves":     "half",
+	"hooves":     "hoof",
+	"indices":    "index",
+	"knives":     "knife",
+	"leaves":     "leaf",
+	"lice":       "louse",
+	"lives":      "life",
+	"loaves":     "loaf",
+	"matrices":   "matrix",
+	"men":        "man",
+	"mice":       "mouse",
+	"nuclei":     "nucleus",
+	"oxen":       "ox",
+	"people":     "person",
+	"phenomena":  "phenomenon",
+	"quizzes":    "quiz",
+	"radii":      "radius",
+	"scarves":    "scarf",
+	"shelves":    "shelf",
+	"stimuli":    "stimulus",
+	"teeth":      "tooth",
+	"theses":     "thesis",
+	"thieves":    "thief",
+	"vertices":   "vertex",
+	"wives":      "wife",
+	"wolves":     "wolf",
+	"women":      "woman",
+}
+
+// singularFilter replaces the irregular plurals with their singular ahead of the stemmer, so
+// "mice" and "mouse" give the same token whichever Stemmer is used
+var singularFilter = mapper.NewFilter(func(token *jargon.Token) *jargon.Token {
+	if token.IsPunct() || token.IsSpace() {
+		return token
+	}
+	if singular, ok := irregularPlurals[strings.ToLower(token.String())]; ok {
+		return jargon.NewToken(singular, true)
+	}
+	return token
+})
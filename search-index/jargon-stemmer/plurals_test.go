@@ -0,0 +1,43 @@
+package jargon_stemmer
+
+import "testing"
+
+func TestPluralsStemLikeSingulars(t *testing.T) {
+	pairs := [][2]string{
+		// Irregular plurals
+		{"mice", "mouse"},
+		{"leaves", "leaf"},
+		{"knives", "knife"},
+		{"children", "child"},
+		{"people", "person"},
+		{"teeth", "tooth"},
+		{"indices", "index"},
+		{"buses", "bus"},
+		{"quizzes", "quiz"},
+		// Regular -s, -es and -ies plurals
+		{"arrows", "arrow"},
+		{"icons", "icon"},
+		{"boxes", "box"},
+		{"batteries", "battery"},
+		{"Categories", "category"},
+	}
+	for _, name := range StemmerNames() {
+		stemmer, _ := StemmerByName(name)
+		for _, pair := range pairs {
+			plural := ProcessTextOptions(pair[0], Options{Stemmer: stemmer})
+			singular := ProcessTextOptions(pair[1], Options{Stemmer: stemmer})
+			if plural == "" || plural != singular {
+				t.Errorf("%s: %q stems to %q and %q to %q, want the same", name, pair[0], plural, pair[1], singular)
+			}
+		}
+	}
+}
+
+func TestAmbiguousPluralsLeftAlone(t *testing.T) {
+	// Also used as singulars, so they are not rewritten
+	for _, word := range []string{"media", "data"} {
+		if _, ok := irregularPlurals[word]; ok {
+			t.Errorf("%q is mapped to a singular", word)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package jargon_stemmer
+
+import "strings"
+
+// porterStem stems a word with the original Porter algorithm, as published in
+// "An algorithm for suffix stripping" (1980). Words that are not all ASCII letters or
+// that have at most two letters are only lowercased.
+func porterStem(token string) string {
+	word := strings.ToLower(token)
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	p := porterWord(word)
+	p = p.step1a().step1b().step1c().step2().step3().step4().step5a().step5b()
+	return string(p)
+}
+
+// porterWord is a lowercase ASCII word being stemmed
+type porterWord []byte
+
+// consonant reports whether the letter at i is a consonant: not a vowel, and for y only
+// when it starts the word or follows a vowel
+func (w porterWord) consonant(i int) bool {
+	switch w[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !w.consonant(i-1)
+	}
+	return true
+}
+
+// measure counts the vowel-consonant sequences of w, the m of [C](VC){m}[V]
+func (w porterWord) measure() int {
+	m := 0
+	i := 0
+	for i < len(w) && w.consonant(i) {
+		i++
+	}
+	for i < len(w) {
+		for i < len(w) && !w.consonant(i) {
+			i++
+		}
+		if i == len(w) {
+			break
+		}
+		for i < len(w) && w.consonant(i) {
+			i++
+		}
+		m++
+	}
+	return m
+}
+
+// hasVowel reports whether w contains a vowel, *v*
+func (w porterWord) hasVowel() bool {
+	for i := range w {
+		if !w.consonant(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsDoubleConsonant reports whether w ends with the same consonant twice, *d
+func (w porterWord) endsDoubleConsonant() bool {
+	n := len(w)
+	return n >= 2 && w[n-1] == w[n-2] && w.consonant(n-1)
+}
+
+// endsCVC reports whether w ends consonant-vowel-consonant with the last one not w, x or y, *o
+func (w porterWord) endsCVC() bool {
+	n := len(w)
+	if n < 3 || !w.consonant(n-1) || w.consonant(n-2) || !w.consonant(n-3) {
+		return false
+	}
+	last := w[n-1]
+	return last != 'w' && last != 'x' && last != 'y'
+}
+
+func (w porterWord) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(w), suffix)
+}
+
+// stem returns w without suffix, which it must end with
+func (w porterWord) stem(suffix string) porterWord {
+	return w[:len(w)-len(suffix)]
+}
+
+// replace returns a copy of w with suffix, which it must end with, replaced by replacement
+func (w porterWord) replace(suffix, replacement string) porterWord {
+	stem := w.stem(suffix)
+	replaced := make(porterWord, 0, len(stem)+len(replacement))
+	return append(append(replaced, stem...), replacement...)
+}
+
+// porterRule replaces Suffix with Replacement when the stem left passes the condition of its step
+type porterRule struct {
+	Suffix      string
+	Replacement string
+}
+
+// applyLongest applies the rule with the longest suffix w ends with, if its stem passes
+// condition. Rules with shorter suffixes are not tried, even when the condition fails.
+func (w porterWord) applyLongest(rules []porterRule, condition func(stem porterWord, suffix string) bool) porterWord {
+	var match *porterRule
+	for i := range rules {
+		if w.hasSuffix(rules[i].Suffix) && (match == nil || len(rules[i].Suffix) > len(match.Suffix)) {
+			match = &rules[i]
+		}
+	}
+	if match == nil || !condition(w.stem(match.Suffix), match.Suffix) {
+		return w
+	}
+	return w.replace(match.Suffix, match.Replacement)
+}
+
+func (w porterWord) step1a() porterWord {
+	switch {
+	case w.hasSuffix("sses"):
+		return w.replace("sses", "ss")
+	case w.hasSuffix("ies"):
+		return w.replace("ies", "i")
+	case w.hasSuffix("ss"):
+		return w
+	case w.hasSuffix("s"):
+		return w.stem("s")
+	}
+	return w
+}
+
+func (w porterWord) step1b() porterWord {
+	if w.hasSuffix("eed") {
+		if w.stem("eed").measure() > 0 {
+			return w.replace("eed", "ee")
+		}
+		return w
+	}
+
+	var stem porterWord
+	switch {
+	case w.hasSuffix("ed") && w.stem("ed").hasVowel():
+		stem = w.stem("ed")
+	case w.hasSuffix("ing") && w.stem("ing").hasVowel():
+		stem = w.stem("ing")
+	default:
+		return w
+	}
+
+	// Tidy up the stem left by removing -ed or -ing
+	switch {
+	case stem.hasSuffix("at"), stem.hasSuffix("bl"), stem.hasSuffix("iz"):
+		return stem.replace("", "e")
+	case stem.endsDoubleConsonant():
+		last := stem[len(stem)-1]
+		if last != 'l' && last != 's' && last != 'z' {
+			return stem[:len(stem)-1]
+		}
+	case stem.measure() == 1 && stem.endsCVC():
+		return stem.replace("", "e")
+	}
+	return stem
+}
+
+func (w porterWord) step1c() porterWord {
+	if w.hasSuffix("y") && w.stem("y").hasVowel() {
+		return w.replace("y", "i")
+	}
+	return w
+}
+
+var porterStep2 = []porterRule{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"}, {"izer", "ize"},
+	{"abli", "able"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"}, {"ousli", "ous"},
+	{"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"},
+	{"fulness", "ful"}, {"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+}
+
+var porterStep3 = []porterRule{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"}, {"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+var porterStep4 = []porterRule{
+	{"al", ""}, {"ance", ""}, {"ence", ""}, {"er", ""}, {"ic", ""}, {"able", ""}, {"ible", ""},
+	{"ant", ""}, {"ement", ""}, {"ment", ""}, {"ent", ""}, {"ion", ""}, {"ou", ""}, {"ism", ""},
+	{"ate", ""}, {"iti", ""}, {"ous", ""}, {"ive", ""}, {"ize", ""},
+}
+
+func positiveMeasure(stem porterWord, suffix string) bool {
+	return stem.measure() > 0
+}
+
+func (w porterWord) step2() porterWord {
+	return w.applyLongest(porterStep2, positiveMeasure)
+}
+
+func (w porterWord) step3() porterWord {
+	return w.applyLongest(porterStep3, positiveMeasure)
+}
+
+func (w porterWord) step4() porterWord {
+	return w.applyLongest(porterStep4, func(stem porterWord, suffix string) bool {
+		if stem.measure() <= 1 {
+			return false
+		}
+		// -ion is only removed after s or t
+		return suffix != "ion" || stem.hasSuffix("s") || stem.hasSuffix("t")
+	})
+}
+
+func (w porterWord) step5a() porterWord {
+	if !w.hasSuffix("e") {
+		return w
+	}
+	stem := w.stem("e")
+	if m := stem.measure(); m > 1 || (m == 1 && !stem.endsCVC()) {
+		return stem
+	}
+	return w
+}
+
+func (w porterWord) step5b() porterWord {
+	if w.measure() > 1 && w.endsDoubleConsonant() && w.hasSuffix("l") {
+		return w[:len(w)-1]
+	}
+	return w
+}
@@ -0,0 +1,78 @@
+package jargon_stemmer
+
+import "testing"
+
+// The examples given for each step in the Porter paper
+func TestPorterSteps(t *testing.T) {
+	steps := []struct {
+		name  string
+		step  func(porterWord) porterWord
+		cases map[string]string
+	}{
+		{"1a", porterWord.step1a, map[string]string{
+			"caresses": "caress", "ponies": "poni", "ties": "ti", "caress": "caress", "cats": "cat",
+		}},
+		{"1b", porterWord.step1b, map[string]string{
+			"feed": "feed", "agreed": "agree", "plastered": "plaster", "bled": "bled", "motoring": "motor",
+			"sing": "sing", "conflated": "conflate", "troubled": "trouble", "sized": "size", "hopping": "hop",
+			"tanned": "tan", "falling": "fall", "hissing": "hiss", "fizzed": "fizz", "failing": "fail",
+			"filing": "file",
+		}},
+		{"1c", porterWord.step1c, map[string]string{
+			"happy": "happi", "sky": "sky",
+		}},
+		{"2", porterWord.step2, map[string]string{
+			"relational": "relate", "conditional": "condition", "rational": "rational", "valenci": "valence",
+			"hesitanci": "hesitance", "digitizer": "digitize", "conformabli": "conformable", "radicalli": "radical",
+			"differentli": "different", "vileli": "vile", "analogousli": "analogous", "vietnamization": "vietnamize",
+			"predication": "predicate", "operator": "operate", "feudalism": "feudal", "decisiveness": "decisive",
+			"hopefulness": "hopeful", "callousness": "callous", "formaliti": "formal", "sensitiviti": "sensitive",
+			"sensibiliti": "sensible",
+		}},
+		{"3", porterWord.step3, map[string]string{
+			"triplicate": "triplic", "formative": "form", "formalize": "formal", "electriciti": "electric",
+			"electrical": "electric", "hopeful": "hope", "goodness": "good",
+		}},
+		{"4", porterWord.step4, map[string]string{
+			"revival": "reviv", "allowance": "allow", "inference": "infer", "airliner": "airlin",
+			"gyroscopic": "gyroscop", "adjustable": "adjust", "defensible": "defens", "irritant": "irrit",
+			"replacement": "replac", "adjustment": "adjust", "dependent": "depend", "adoption": "adopt",
+			"homologou": "homolog", "communism": "commun", "activate": "activ", "angulariti": "angular",
+			"homologous": "homolog", "effective": "effect", "bowdlerize": "bowdler",
+		}},
+		{"5a", porterWord.step5a, map[string]string{
+			"probate": "probat", "rate": "rate", "cease": "ceas",
+		}},
+		{"5b", porterWord.step5b, map[string]string{
+			"controll": "control", "roll": "roll",
+		}},
+	}
+
+	for _, step := range steps {
+		for word, want := range step.cases {
+			if got := string(step.step(porterWord(word))); got != want {
+				t.Errorf("step %s of %q = %q, want %q", step.name, word, got, want)
+			}
+		}
+	}
+}
+
+func TestPorterStem(t *testing.T) {
+	cases := map[string]string{
+		"generalizations": "gener",
+		"oscillators":     "oscil",
+		"connections":     "connect",
+		"relational":      "relat",
+		"Running":         "run",
+		// Short and non-letter tokens are only lowercased
+		"is":    "is",
+		"SVG2":  "svg2",
+		"café":  "café",
+		"x-ray": "x-ray",
+	}
+	for token, want := range cases {
+		if got := porterStem(token); got != want {
+			t.Errorf("porterStem(%q) = %q, want %q", token, got, want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package jargon_stemmer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/clipperhouse/jargon"
+	"github.com/clipperhouse/jargon/filters/mapper"
+	"github.com/kljensen/snowball/english"
+)
+
+// Stemmer reduces a single word token to its stem
+type Stemmer interface {
+	Stem(token string) string
+}
+
+// StemmerFunc adapts a function to the Stemmer interface
+type StemmerFunc func(token string) string
+
+func (f StemmerFunc) Stem(token string) string {
+	return f(token)
+}
+
+// Default is the stemmer used so far, matching jargon's English filter. It stems stop
+// words too, e.g. "during" → "dure" and "only" → "onli".
+var Default Stemmer = StemmerFunc(func(token string) string {
+	return english.Stem(token, true)
+})
+
+// Porter2 is the Snowball English (Porter2) stemmer leaving stop words as they are,
+// for tokens where Default is too aggressive: "only during" stays "only during" where
+// Default gives "onli dure"
+var Porter2 Stemmer = StemmerFunc(func(token string) string {
+	return english.Stem(token, false)
+})
+
+// Porter is the original Porter algorithm that Default, the Snowball English (Porter2)
+// stemmer, revised. It keeps fewer exceptions and strips more, e.g. "generously" → "gener"
+// where Default gives "generous", but leaves "dying" as "dy" rather than "die".
+var Porter Stemmer = StemmerFunc(porterStem)
+
+// stemmers lists the stemmers selectable by name, e.g. with --stemmer
+var stemmers = map[string]Stemmer{
+	"default":  Default,
+	"porter2":  Porter2,
+	"snowball": Porter2,
+	"porter":   Porter,
+}
+
+// StemmerNames returns the names accepted by StemmerByName
+func StemmerNames() []string {
+	names := make([]string, 0, len(stemmers))
+	for name := range stemmers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StemmerByName returns the stemmer registered under name
+func StemmerByName(name string) (Stemmer, error) {
+	s, ok := stemmers[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown stemmer %q, expected one of: %s", name, strings.Join(StemmerNames(), ", "))
+	}
+	return s, nil
+}
+
+// stemFilter runs each word token through s, leaving punctuation and whitespace alone
+func stemFilter(s Stemmer) jargon.Filter {
+	return mapper.NewFilter(func(token *jargon.Token) *jargon.Token {
+		if token.IsPunct() || token.IsSpace() {
+			return token
+		}
+
+		stemmed := s.Stem(token.String())
+		if stemmed == token.String() {
+			return token
+		}
+		return jargon.NewToken(stemmed, true)
+	})
+}
@@ -0,0 +1,79 @@
+package jargon_stemmer
+
+import "testing"
+
+// stemmerTokens are stemmed by Default and Porter, which agree on the first ones
+var stemmerTokens = []struct {
+	token  string
+	dflt   string
+	porter string
+}{
+	{"connections", "connect", "connect"},
+	{"running", "run", "run"},
+	{"happiness", "happi", "happi"},
+	{"oscillators", "oscil", "oscil"},
+	{"settings", "set", "set"},
+	{"only", "onli", "onli"},
+	{"generalizations", "general", "gener"},
+	{"generously", "generous", "gener"},
+	{"skies", "sky", "ski"},
+	{"dying", "die", "dy"},
+	{"news", "news", "new"},
+}
+
+func TestStemmersCompared(t *testing.T) {
+	differ := 0
+	for _, tt := range stemmerTokens {
+		if got := Default.Stem(tt.token); got != tt.dflt {
+			t.Errorf("Default.Stem(%q) = %q, want %q", tt.token, got, tt.dflt)
+		}
+		if got := Porter.Stem(tt.token); got != tt.porter {
+			t.Errorf("Porter.Stem(%q) = %q, want %q", tt.token, got, tt.porter)
+		}
+		if tt.dflt != tt.porter {
+			differ++
+		}
+	}
+	if differ == 0 {
+		t.Error("Default and Porter stem every token the same")
+	}
+}
+
+func TestProcessTextWithStemmer(t *testing.T) {
+	text := "Generalizations of dying skies"
+	if got, want := ProcessTextWith(text, Default), "general die sky"; got != want {
+		t.Errorf("ProcessTextWith(%q, Default) = %q, want %q", text, got, want)
+	}
+	if got, want := ProcessTextWith(text, Porter), "gener dy ski"; got != want {
+		t.Errorf("ProcessTextWith(%q, Porter) = %q, want %q", text, got, want)
+	}
+	if got, want := ProcessTextWith(text, nil), ProcessText(text); got != want {
+		t.Errorf("ProcessTextWith(%q, nil) = %q, want the Default %q", text, got, want)
+	}
+}
+
+func TestStemmerByName(t *testing.T) {
+	for name, want := range map[string]string{"default": "generous", "PORTER": "gener", "porter2": "generous", "Snowball": "generous"} {
+		s, err := StemmerByName(name)
+		if err != nil {
+			t.Fatalf("StemmerByName(%q): %v", name, err)
+		}
+		if got := s.Stem("generously"); got != want {
+			t.Errorf("StemmerByName(%q).Stem(\"generously\") = %q, want %q", name, got, want)
+		}
+	}
+	if _, err := StemmerByName("lancaster"); err == nil {
+		t.Error("StemmerByName(\"lancaster\") succeeded, want an unknown stemmer error")
+	}
+}
+
+func TestPorter2KeepsStopWords(t *testing.T) {
+	// The Porter2 stemmer differs from Default only on stop words, which it leaves alone
+	text := "only during connections"
+	if got, want := ProcessTextWith(text, Porter2), "only during connect"; got != want {
+		t.Errorf("ProcessTextWith(%q, Porter2) = %q, want %q", text, got, want)
+	}
+	if got, want := ProcessTextWith(text, Default), "onli dure connect"; got != want {
+		t.Errorf("ProcessTextWith(%q, Default) = %q, want %q", text, got, want)
+	}
+}
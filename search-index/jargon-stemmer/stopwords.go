@@ -0,0 +1,108 @@
+package jargon_stemmer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/clipperhouse/jargon"
+)
+
+// StopWordsFile optionally extends the built-in stop words, one word per line. Lines starting
+// with # are comments and a word prefixed with ! is never stopped, e.g. "!set".
+const StopWordsFile = "stopwords.txt"
+
+// englishStopWords are common words that carry no meaning for search. Words that are also
+// icon names or directions, such as "up", "down", "off", "more" and "no", are left out.
+var englishStopWords = []string{
+	"a", "about", "an", "and", "are", "as", "at", "be", "been", "being", "but", "by",
+	"can", "could", "did", "do", "does", "doing", "for", "from", "had", "has", "have",
+	"having", "he", "her", "here", "hers", "him", "his", "how", "i", "if", "into", "is",
+	"it", "its", "itself", "just", "me", "my", "of", "or", "our", "ours", "she", "should",
+	"so", "such", "than", "that", "the", "their", "theirs", "them", "then", "there",
+	"these", "they", "this", "those", "through", "to", "too", "very", "was", "we", "were",
+	"what", "when", "where", "which", "while", "who", "whom", "why", "will", "with",
+	"would", "you", "your", "yours",
+}
+
+// stopWords holds the lowercased words dropped before stemming
+var stopWords = toWordSet(englishStopWords)
+
+// allowedWords are never stopped, even when listed in Options.StopWords
+var allowedWords = map[string]bool{}
+
+func toWordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[strings.ToLower(word)] = true
+	}
+	return set
+}
+
+// LoadStopWords adds the words of a stop word file to the built-in list and applies its
+// !word exceptions. A missing file is not an error.
+func LoadStopWords(filePath string) error {
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		if strings.HasPrefix(word, "!") {
+			word = strings.TrimPrefix(word, "!")
+			delete(stopWords, word)
+			allowedWords[word] = true
+			continue
+		}
+		stopWords[word] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %v", filePath, err)
+	}
+	return nil
+}
+
+// isStopWord reports whether a word is dropped, given the extra stop words of a caller
+func isStopWord(word string, extra []string) bool {
+	word = strings.ToLower(word)
+	if allowedWords[word] {
+		return false
+	}
+	if stopWords[word] {
+		return true
+	}
+	for _, e := range extra {
+		if strings.EqualFold(e, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// stopWordFilter drops stop words from a token stream
+func stopWordFilter(extra []string) jargon.Filter {
+	return func(incoming *jargon.TokenStream) *jargon.TokenStream {
+		return jargon.NewTokenStream(func() (*jargon.Token, error) {
+			for {
+				token, err := incoming.Next()
+				if err != nil || token == nil {
+					return token, err
+				}
+				if !token.IsPunct() && !token.IsSpace() && isStopWord(token.String(), extra) {
+					continue
+				}
+				return token, nil
+			}
+		})
+	}
+}
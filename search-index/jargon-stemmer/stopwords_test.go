@@ -0,0 +1,59 @@
+package jargon_stemmer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// useStopWords loads a stopwords.txt holding content until the test ends
+func useStopWords(t *testing.T, content string) {
+	t.Helper()
+	previousStop, previousAllowed := stopWords, allowedWords
+	t.Cleanup(func() { stopWords, allowedWords = previousStop, previousAllowed })
+	stopWords, allowedWords = toWordSet(englishStopWords), map[string]bool{}
+
+	filePath := filepath.Join(t.TempDir(), StopWordsFile)
+	if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadStopWords(filePath); err != nil {
+		t.Fatalf("LoadStopWords: %v", err)
+	}
+}
+
+func TestProcessTextStopWords(t *testing.T) {
+	useStopWords(t, "# Words of nearly every icon\nglyph\n\n!such\n")
+
+	tests := []struct {
+		text string
+		opts Options
+		want string
+	}{
+		// Built-in English stop words are dropped before stemming
+		{"The arrows of the settings", Options{}, "arrow set"},
+		// Words of the file are added, its ! exceptions kept
+		{"A Glyph of such arrows", Options{}, "such arrow"},
+		// Extra stop words of the caller, matching the word as written
+		{"SVG icons for arrows", Options{StopWords: []string{"svg", "icons"}}, "arrow"},
+		{"icons", Options{StopWords: []string{"icon"}}, "icon"},
+		// An exception wins over the extra stop words too
+		{"such arrows", Options{StopWords: []string{"such"}}, "such arrow"},
+		{"The arrows of the settings", Options{KeepStopWords: true}, "the arrow of the set"},
+	}
+	for _, tc := range tests {
+		if got := ProcessTextOptions(tc.text, tc.opts); got != tc.want {
+			t.Errorf("ProcessTextOptions(%q, %+v) = %q, want %q", tc.text, tc.opts, got, tc.want)
+		}
+	}
+}
+
+func TestLoadStopWordsMissingFile(t *testing.T) {
+	previous := len(stopWords)
+	if err := LoadStopWords(filepath.Join(t.TempDir(), StopWordsFile)); err != nil {
+		t.Errorf("LoadStopWords of a missing file: %v", err)
+	}
+	if len(stopWords) != previous {
+		t.Errorf("stop words changed from %d to %d", previous, len(stopWords))
+	}
+}
@@ -0,0 +1,131 @@
+package jargon_stemmer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// SynonymsFile is the optional dictionary of search synonyms. It is either a JSON array of
+// symmetric groups, e.g. [["trash", "bin", "delete"]], or a JSON object mapping a term to
+// the terms it should also match, e.g. {"bin": ["trash"]}.
+const SynonymsFile = "synonyms.json"
+
+// synonyms maps a stemmed token to the stemmed tokens it expands to
+var synonyms = map[string][]string{}
+
+// LoadSynonyms loads the synonym dictionary used by ExpandSynonyms and the stem step.
+// A missing file is not an error and leaves expansion disabled.
+func LoadSynonyms(filePath string) error {
+	return LoadSynonymsOptions(filePath, Options{})
+}
+
+// LoadSynonymsOptions is LoadSynonyms stemming the terms with the stemmer and stop words of
+// opts, which must be those of the text the synonyms are expanded for
+func LoadSynonymsOptions(filePath string, opts Options) error {
+	data, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		// A dictionary deleted since the last load expands nothing anymore
+		synonyms = map[string][]string{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", filePath, err)
+	}
+
+	loaded, err := parseSynonyms(data, opts)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %v", filePath, err)
+	}
+	synonyms = loaded
+	return nil
+}
+
+// parseSynonyms builds the stemmed expansion table from either dictionary format
+func parseSynonyms(data []byte, opts Options) (map[string][]string, error) {
+	table := make(map[string][]string)
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var groups [][]string
+		if err := json.Unmarshal(data, &groups); err != nil {
+			return nil, err
+		}
+		// Every term of a group expands to all the others
+		for _, group := range groups {
+			for _, term := range group {
+				addSynonyms(table, term, group, opts)
+			}
+		}
+		return table, nil
+	}
+
+	var mapping map[string][]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, err
+	}
+	for term, equivalents := range mapping {
+		addSynonyms(table, term, equivalents, opts)
+	}
+	return table, nil
+}
+
+// addSynonyms records the equivalents of term, stemmed the same way as indexed text
+func addSynonyms(table map[string][]string, term string, equivalents []string, opts Options) {
+	key := stemTerm(term, opts)
+	if key == "" {
+		return
+	}
+	for _, equivalent := range equivalents {
+		stemmed := stemTerm(equivalent, opts)
+		if stemmed == "" || stemmed == key || containsToken(table[key], stemmed) {
+			continue
+		}
+		table[key] = append(table[key], stemmed)
+	}
+}
+
+func stemTerm(term string, opts Options) string {
+	return strings.ToLower(ProcessTextOptions(strings.TrimSpace(term), opts))
+}
+
+func containsToken(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandSynonyms returns the sorted synonyms of the given stemmed tokens, leaving out
+// tokens that are already present so original terms are never counted twice
+func ExpandSynonyms(tokens []string) []string {
+	if len(synonyms) == 0 {
+		return nil
+	}
+
+	present := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		present[strings.ToLower(token)] = true
+	}
+
+	added := make(map[string]bool)
+	for token := range present {
+		for _, synonym := range synonyms[token] {
+			if !present[synonym] {
+				added[synonym] = true
+			}
+		}
+	}
+
+	expanded := make([]string, 0, len(added))
+	for synonym := range added {
+		expanded = append(expanded, synonym)
+	}
+	sort.Strings(expanded)
+	return expanded
+}
@@ -0,0 +1,117 @@
+package jargon_stemmer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// useSynonyms loads a synonyms.json holding content until the test ends
+func useSynonyms(t *testing.T, content string, opts Options) {
+	t.Helper()
+	previous := synonyms
+	t.Cleanup(func() { synonyms = previous })
+
+	filePath := filepath.Join(t.TempDir(), SynonymsFile)
+	if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadSynonymsOptions(filePath, opts); err != nil {
+		t.Fatalf("LoadSynonymsOptions: %v", err)
+	}
+}
+
+func TestExpandSynonymsGroup(t *testing.T) {
+	useSynonyms(t, `[["trash", "bin", "delete"]]`, Options{})
+
+	tests := []struct {
+		tokens []string
+		want   []string
+	}{
+		{[]string{"trash"}, []string{"bin", "delet"}},
+		{[]string{"bin"}, []string{"delet", "trash"}},
+		{[]string{"delet"}, []string{"bin", "trash"}},
+		// Tokens already present are not added again
+		{[]string{"trash", "bin"}, []string{"delet"}},
+		{[]string{"home"}, []string{}},
+	}
+	for _, tt := range tests {
+		if got := ExpandSynonyms(tt.tokens); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExpandSynonyms(%v) = %v, want %v", tt.tokens, got, tt.want)
+		}
+	}
+}
+
+func TestExpandSynonymsMapping(t *testing.T) {
+	// A mapping only expands one way
+	useSynonyms(t, `{"bin": ["trash"]}`, Options{})
+
+	if got := ExpandSynonyms([]string{"bin"}); !reflect.DeepEqual(got, []string{"trash"}) {
+		t.Errorf("ExpandSynonyms([bin]) = %v, want [trash]", got)
+	}
+	if got := ExpandSynonyms([]string{"trash"}); len(got) != 0 {
+		t.Errorf("ExpandSynonyms([trash]) = %v, want none", got)
+	}
+}
+
+func TestLoadSynonymsOptions(t *testing.T) {
+	// Terms are stemmed with the stemmer and stop words of the text they expand
+	useSynonyms(t, `[["generously", "freely", "icon"]]`, Options{Stemmer: Porter, StopWords: []string{"icon"}})
+
+	if got := ExpandSynonyms([]string{"gener"}); !reflect.DeepEqual(got, []string{"freeli"}) {
+		t.Errorf("ExpandSynonyms([gener]) = %v, want [freeli]", got)
+	}
+}
+
+func TestLoadSynonymsMissingFile(t *testing.T) {
+	previous := synonyms
+	defer func() { synonyms = previous }()
+	synonyms = map[string][]string{}
+
+	if err := LoadSynonyms(filepath.Join(t.TempDir(), SynonymsFile)); err != nil {
+		t.Errorf("LoadSynonyms of a missing file: %v", err)
+	}
+	if got := ExpandSynonyms([]string{"trash"}); got != nil {
+		t.Errorf("ExpandSynonyms without synonyms = %v, want nil", got)
+	}
+}
+
+func TestStemRecordSynonyms(t *testing.T) {
+	useSynonyms(t, `[["trash", "bin", "delete"]]`, Options{})
+
+	var r record
+	if err := json.Unmarshal([]byte(`{"id":"svg-icons-a-trash","name":"Trash","description":"Throw away"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	stemRecord(&r, Options{Fields: []string{"Name", "Description"}})
+
+	// The original tokens stay in altName, the synonyms go to altSynonyms after them
+	got, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"svg-icons-a-trash","name":"Trash","altName":"trash","description":"Throw away","altDescription":"throw away","altSynonyms":"bin delet"}`
+	if string(got) != want {
+		t.Errorf("stemmed record = %s, want %s", got, want)
+	}
+}
+
+func TestStemRecordDropsStaleFields(t *testing.T) {
+	// The record was stemmed before its description was cleared and synonyms.json deleted
+	var r record
+	if err := json.Unmarshal([]byte(`{"id":"svg-icons-a-home","name":"Home","altName":"home","description":"","altDescription":"main page","altSynonyms":"hous"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	stemRecord(&r, Options{Fields: []string{"Name", "Description"}})
+
+	got, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"svg-icons-a-home","name":"Home","altName":"home","description":""}`
+	if string(got) != want {
+		t.Errorf("re-stemmed record = %s, want %s", got, want)
+	}
+}
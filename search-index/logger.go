@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// logFormats lists the supported values of --log-format
+var logFormats = []string{"text", "json"}
+
+// setupLogger routes slog, and through it the log package, to w in the given format.
+// The text format prints each message as is, like the generator always did, and leaves the
+// structured fields to the json format for CI log aggregation.
+func setupLogger(w io.Writer, format string, level slog.Level) {
+	var handler slog.Handler
+	if format == "json" {
+		handler = &jsonLogHandler{Handler: slog.NewJSONHandler(w, &slog.HandlerOptions{
+			Level: level,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				// Messages keep their blank line padding for the text format only
+				if len(groups) == 0 && a.Key == slog.MessageKey {
+					a.Value = slog.StringValue(strings.TrimSpace(a.Value.String()))
+				}
+				return a
+			},
+		})}
+	} else {
+		handler = &textLogHandler{w: w, level: level, mu: &sync.Mutex{}}
+	}
+
+	slog.SetDefault(slog.New(handler))
+	// Fatal errors from the log package are reported at error level
+	log.SetOutput(logErrorWriter{})
+	log.SetFlags(0)
+}
+
+// logErrorWriter is the output of the log package, logging each line with slog.Error
+type logErrorWriter struct{}
+
+func (logErrorWriter) Write(p []byte) (int, error) {
+	slog.Error(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// parseLogLevel parses a --log-level value: debug, info, warn or error
+func parseLogLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return level, fmt.Errorf("unknown log level %q, expected one of: debug, info, warn, error", value)
+	}
+	return level, nil
+}
+
+// textLogHandler writes the message of each record on its own line, without level,
+// time or fields, so the interactive output reads the same as plain prints
+type textLogHandler struct {
+	w     io.Writer
+	level slog.Level
+	mu    *sync.Mutex
+}
+
+func (h *textLogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *textLogHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, r.Message+"\n")
+	return err
+}
+
+func (h *textLogHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *textLogHandler) WithGroup(string) slog.Handler { return h }
+
+// jsonLogHandler drops the blank lines used to space out the text output
+type jsonLogHandler struct {
+	slog.Handler
+}
+
+func (h *jsonLogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if strings.TrimSpace(r.Message) == "" && r.NumAttrs() == 0 {
+		return nil
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *jsonLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &jsonLogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *jsonLogHandler) WithGroup(name string) slog.Handler {
+	return &jsonLogHandler{Handler: h.Handler.WithGroup(name)}
+}
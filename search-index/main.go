@@ -1,310 +1,158 @@
 package main
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
-	"sync"
+	"syscall"
 	"time"
 
 	jargon_stemmer "search-index/jargon-stemmer"
+	"search-index/svgicons"
 )
 
+// exitCancelled is the exit code of a run stopped by Ctrl-C or SIGTERM, following the
+// shell convention of 128 + SIGINT, so scripts can tell it apart from a failure
+const exitCancelled = 130
+
 func main() {
 	start := time.Now()
 
-	// Create output directory if it doesn't exist
-	if err := ensureOutputDir(); err != nil {
-		log.Fatalf("Failed to create output directory: %v", err)
-	}
+	// Ctrl-C and SIGTERM cancel the context, stopping generation at the next checkpoint
+	interrupted, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Parse command line arguments for category and stem
+	// Parse command line arguments for category, stem and the serve subcommand
 	category := parseCategory()
 	stemArgs := parseStem()
+	serve := parseServe()
 
-	if stemArgs != "" {
-		fmt.Printf("🚀 Starting stem processing...\n")
-		runStemProcessing(stemArgs)
-		return
+	svgOpts, err := parseSVGOptions(os.Args[1:])
+	if err != nil {
+		log.Fatalf("❌ Invalid arguments: %v", err)
 	}
-
-	if category != "" {
-		fmt.Printf("🚀 Starting %s data generation...\n", category)
-		runSingleCategory(category)
-		return
+	setupLogger(os.Stdout, svgOpts.LogFormat, svgOpts.LogLevel)
+	outputDir = svgOpts.OutDir
+	compactJSON = svgOpts.Compact
+	if err := startProfiling(svgOpts.Profile, svgOpts.ProfileFile); err != nil {
+		log.Fatalf("❌ %v", err)
 	}
-
-	fmt.Println("🚀 Starting search index generation...")
-
-	// Create context for cancellation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
-
-	// Use WaitGroup to wait for all goroutines
-	var wg sync.WaitGroup
-
-	// Channels to collect results
-	toolsChan := make(chan []ToolData, 1)
-	tldrChan := make(chan []TLDRData, 1)
-	emojisChan := make(chan []EmojiData, 1)
-	svgIconsChan := make(chan []SVGIconData, 1)
-	pngIconsChan := make(chan []SVGIconData, 1)
-	cheatsheetsChan := make(chan []CheatsheetData, 1)
-	mcpChan := make(chan []MCPData, 1)
-	errorsChan := make(chan error, 6)
-
-	// Start all collection goroutines
-	wg.Add(7)
-
-	go func() {
-		defer wg.Done()
-		tools, err := generateToolsData(ctx)
-		if err != nil {
-			errorsChan <- fmt.Errorf("tools data generation failed: %w", err)
-			return
-		}
-		toolsChan <- tools
-	}()
-
-	go func() {
-		defer wg.Done()
-		tldr, err := generateTLDRData(ctx)
-		if err != nil {
-			errorsChan <- fmt.Errorf("TLDR data generation failed: %w", err)
-			return
-		}
-		tldrChan <- tldr
-	}()
-
-	go func() {
-		defer wg.Done()
-		emojis, err := generateEmojisData(ctx)
-		if err != nil {
-			errorsChan <- fmt.Errorf("emojis data generation failed: %w", err)
-			return
-		}
-		emojisChan <- emojis
-	}()
-
-	go func() {
-		defer wg.Done()
-		svgIcons, err := generateSVGIconsData(ctx)
-		if err != nil {
-			errorsChan <- fmt.Errorf("SVG icons data generation failed: %w", err)
-			return
+	defer stopProfiling()
+	if svgOpts.DryRun && category != "svg_icons" {
+		log.Fatalf("❌ --dry-run is only supported with category=svg_icons")
+	}
+	if svgOpts.Watch && category != "svg_icons" {
+		log.Fatalf("❌ --watch is only supported with category=svg_icons")
+	}
+	if svgOpts.IndexOnly && category != "svg_icons" {
+		log.Fatalf("❌ --index-only is only supported with category=svg_icons")
+	}
+	if svgOpts.ValidateOnly {
+		if category != "" && category != "svg_icons" {
+			log.Fatalf("❌ --validate-only is only supported with category=svg_icons")
 		}
-		svgIconsChan <- svgIcons
-	}()
-
-	go func() {
-		defer wg.Done()
-		pngIcons, err := generatePNGIconsData(ctx)
-		if err != nil {
-			errorsChan <- fmt.Errorf("PNG icons data generation failed: %w", err)
-			return
+		if err := runSVGValidateOnly(interrupted, svgOpts, start); err != nil {
+			log.Fatalf("❌ Cluster validation failed: %v", err)
 		}
-		pngIconsChan <- pngIcons
-	}()
-	
+		return
+	}
 
-	go func() {
-		defer wg.Done()
-		cheatsheets, err := generateCheatsheetsData(ctx)
-		if err != nil {
-			errorsChan <- fmt.Errorf("cheatsheets data generation failed: %w", err)
-			return
-		}
-		cheatsheetsChan <- cheatsheets
-	}()
+	if serve && (category != "" || stemArgs != "") {
+		log.Fatalf("❌ serve cannot be combined with category= or stem=")
+	}
 
-	go func() {
-		defer wg.Done()
-		mcp, err := generateMCPData(ctx)
-		if err != nil {
-			errorsChan <- fmt.Errorf("MCP data generation failed: %w", err)
-			return
-		}
-		mcpChan <- mcp
-	}()
-
-	// Wait for all goroutines to complete
-	go func() {
-		wg.Wait()
-		close(toolsChan)
-		close(tldrChan)
-		close(emojisChan)
-		close(svgIconsChan)
-		close(pngIconsChan)
-		close(cheatsheetsChan)
-		close(mcpChan)
-		close(errorsChan)
-	}()
-
-	// Collect results and check for errors
-	var tools []ToolData
-	var tldr []TLDRData
-	var emojis []EmojiData
-	var svgIcons []SVGIconData
-	var pngIcons []SVGIconData
-	var cheatsheets []CheatsheetData
-	var mcp []MCPData
-	var errors []error
-
-	// Track which channels we've received data from
-	receivedChannels := 0
-	totalChannels := 7
-
-	for receivedChannels < totalChannels {
-		select {
-		case t, ok := <-toolsChan:
-			if ok {
-				tools = t
-				fmt.Printf("✅ Tools data collected: %d items\n", len(t))
-			}
-			receivedChannels++
-		case tl, ok := <-tldrChan:
-			if ok {
-				tldr = tl
-				fmt.Printf("✅ TLDR data collected: %d items\n", len(tl))
-			}
-			receivedChannels++
-		case e, ok := <-emojisChan:
-			if ok {
-				emojis = e
-				fmt.Printf("✅ Emojis data collected: %d items\n", len(e))
-			}
-			receivedChannels++
-		case s, ok := <-svgIconsChan:
-			if ok {
-				svgIcons = s
-				fmt.Printf("✅ SVG icons data collected: %d items\n", len(s))
-			}
-			receivedChannels++
-		case p, ok := <-pngIconsChan:
-			if ok {
-				pngIcons = p
-				fmt.Printf("✅ PNG icons data collected: %d items\n", len(p))
-			}
-			receivedChannels++		
-		case c, ok := <-cheatsheetsChan:
-			if ok {
-				cheatsheets = c
-				fmt.Printf("✅ Cheatsheets data collected: %d items\n", len(c))
-			}
-			receivedChannels++
-		case m, ok := <-mcpChan:
-			if ok {
-				mcp = m
-				fmt.Printf("✅ MCP data collected: %d items\n", len(m))
-			}
-			receivedChannels++
-		case err, ok := <-errorsChan:
-			if ok {
-				errors = append(errors, err)
-				fmt.Printf("❌ Error: %v\n", err)
-			}
-			// Don't increment receivedChannels for errors
-		case <-ctx.Done():
-			fmt.Println("❌ Operation timed out")
-			os.Exit(1)
+	// Create output directory if it doesn't exist and make sure we can write to it.
+	// A dry run writes nothing and serve only reads, so they do not need one.
+	if !svgOpts.DryRun && !serve {
+		if err := checkOutputDirWritable(); err != nil {
+			log.Fatalf("Output directory %s is not usable: %v", outputDir, err)
 		}
 	}
 
-	// Drain any remaining errors
-	for {
-		select {
-		case err, ok := <-errorsChan:
-			if ok {
-				errors = append(errors, err)
-				fmt.Printf("❌ Error: %v\n", err)
-			} else {
-				goto doneWithErrors
-			}
-		default:
-			goto doneWithErrors
-		}
+	// Load extra acronym/brand spellings used when formatting icon names
+	if err := svgicons.LoadNameCasing(svgicons.NameCasingFile); err != nil {
+		log.Fatalf("Failed to load name casing: %v", err)
 	}
 
-doneWithErrors:
-	// Check if any errors occurred
-	if len(errors) > 0 {
-		fmt.Printf("❌ %d errors occurred during generation:\n", len(errors))
-		for _, err := range errors {
-			fmt.Printf("  - %v\n", err)
-		}
-		os.Exit(1)
+	// Load the optional usage scores biasing the SVG icon ranking
+	if err := svgicons.LoadPopularity(svgicons.PopularityFile); err != nil {
+		log.Fatalf("Failed to load popularity: %v", err)
 	}
 
-	// Save all data to JSON files in output directory
-	if err := saveToJSON("tools.json", tools); err != nil {
-		log.Fatalf("Failed to save tools data: %v", err)
+	// Load the optional globs of deprecated SVG icons and folders to leave out
+	if err := svgicons.LoadIgnore(svgicons.IgnoreFile); err != nil {
+		log.Fatalf("Failed to load ignore patterns: %v", err)
 	}
 
-	if err := saveToJSON("tldr_pages.json", tldr); err != nil {
-		log.Fatalf("Failed to save TLDR data: %v", err)
+	// Load the optional stop word additions and exceptions used by the stem step
+	if err := jargon_stemmer.LoadStopWords(jargon_stemmer.StopWordsFile); err != nil {
+		log.Fatalf("Failed to load stop words: %v", err)
 	}
 
-	if err := saveToJSON("emojis.json", emojis); err != nil {
-		log.Fatalf("Failed to save emojis data: %v", err)
+	// Load the optional search synonyms used by the stem step and the SVG search index,
+	// stemmed like the SVG icons they are matched against
+	if err := jargon_stemmer.LoadSynonymsOptions(jargon_stemmer.SynonymsFile, *svgOpts.stemOptions()); err != nil {
+		log.Fatalf("Failed to load synonyms: %v", err)
 	}
 
-	if err := saveToJSON("svg_icons.json", svgIcons); err != nil {
-		log.Fatalf("Failed to save SVG icons data: %v", err)
+	// Load the optional field boosts of the SVG search index
+	if err := loadFieldBoosts(fieldBoostsFile); err != nil {
+		log.Fatalf("Failed to load field boosts: %v", err)
 	}
 
-	if err := saveToJSON("png_icons.json", pngIcons); err != nil {
-		log.Fatalf("Failed to save PNG icons data: %v", err)
+	if serve {
+		if err := runSVGServe(interrupted, svgOpts); err != nil {
+			log.Fatalf("❌ Serve failed: %v", err)
+		}
+		return
 	}
-	
 
-	if err := saveToJSON("cheatsheets.json", cheatsheets); err != nil {
-		log.Fatalf("Failed to save cheatsheets data: %v", err)
+	if stemArgs != "" {
+		slog.Info("🚀 Starting stem processing...")
+		runStemProcessing(stemArgs)
+		return
 	}
 
-	if err := saveToJSON("mcp.json", mcp); err != nil {
-		log.Fatalf("Failed to save MCP data: %v", err)
+	if svgOpts.Watch {
+		watchSVGIcons(interrupted, svgOpts)
+		return
 	}
 
-	elapsed := time.Since(start)
-	fmt.Printf("\n🎉 Search index generation completed successfully in %v\n", elapsed)
-	fmt.Printf("📊 Generated data:\n")
-	fmt.Printf("  - Tools: %d items\n", len(tools))
-	fmt.Printf("  - TLDR Pages: %d items\n", len(tldr))
-	fmt.Printf("  - Emojis: %d items\n", len(emojis))
-	fmt.Printf("  - SVG Icons: %d items\n", len(svgIcons))
-	fmt.Printf("  - PNG Icons: %d items\n", len(pngIcons))
-	fmt.Printf("  - Cheatsheets: %d items\n", len(cheatsheets))
-	fmt.Printf("  - MCP: %d items\n", len(mcp))
-	fmt.Printf("\n💾 All files saved to ./output/ directory\n")
-	
-	// Automatically run stem processing on all generated files
-	fmt.Println("\n🔍 Running stem processing on all files...")
-	
-	// Get all JSON files from output directory
-	outputDir := "output"
-	files, err := os.ReadDir(outputDir)
-	if err != nil {
-		log.Printf("❌ Failed to read output directory: %v", err)
+	if category != "" {
+		slog.Info(fmt.Sprintf("🚀 Starting %s data generation...", category))
+		runSingleCategory(interrupted, category, svgOpts)
 		return
 	}
-	
-	for _, file := range files {
-		if strings.HasSuffix(file.Name(), ".json") {
-			filePath := filepath.Join(outputDir, file.Name())
-			fmt.Printf("Processing %s...\n", filePath)
-			if err := jargon_stemmer.ProcessJSONFile(filePath); err != nil {
-				log.Printf("❌ Stem processing failed for %s: %v", filePath, err)
-			} else {
-				fmt.Printf("✅ Completed %s\n", filePath)
-			}
-		}
+
+	slog.Info("🚀 Starting search index generation...")
+
+	// Create context for cancellation
+	ctx, cancel := context.WithTimeout(interrupted, 5*time.Minute)
+	defer cancel()
+
+	if err := RunAll(ctx, start, svgOpts); err != nil {
+		exitWithError(err)
 	}
-	
-	fmt.Println("🎉 All stem processing completed!")
+}
+
+// exitWithError reports a failed run and exits, with exitCancelled if it was interrupted
+func exitWithError(err error) {
+	stopProfiling()
+	if errors.Is(err, context.Canceled) {
+		slog.Error("🛑 Cancelled, stopped before writing the remaining output")
+		os.Exit(exitCancelled)
+	}
+	log.Fatalf("❌ %v", err)
 }
 
 func parseCategory() string {
@@ -325,6 +173,17 @@ func parseStem() string {
 	return ""
 }
 
+// parseServe reports whether the serve subcommand was given, answering search queries over
+// the generated SVG icons instead of generating anything
+func parseServe() bool {
+	for _, arg := range os.Args[1:] {
+		if arg == "serve" {
+			return true
+		}
+	}
+	return false
+}
+
 func runStemProcessing(stemArgs string) {
 	start := time.Now()
 	
@@ -333,25 +192,25 @@ func runStemProcessing(stemArgs string) {
 	
 	// Check if the file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		fmt.Printf("❌ File not found: %s\n", filePath)
-		fmt.Println("Make sure the file exists.")
+		slog.Error(fmt.Sprintf("❌ File not found: %s", filePath))
+		slog.Info("Make sure the file exists.")
 		os.Exit(1)
 	}
 	
-	fmt.Printf("🔍 Processing file: %s\n", filePath)
+	slog.Info(fmt.Sprintf("🔍 Processing file: %s", filePath))
 	
 	// Use the reusable function from jargon-stemmer package
-	if err := jargon_stemmer.ProcessJSONFile(filePath); err != nil {
+	if err := stemJSONFile(filePath); err != nil {
 		log.Fatalf("❌ Stem processing failed: %v", err)
 	}
 	
 	elapsed := time.Since(start)
-	fmt.Printf("\n🎉 Stem processing completed in %v\n", elapsed)
-	fmt.Printf("💾 Processed file: %s\n", filePath)
+	slog.Info(fmt.Sprintf("\n🎉 Stem processing completed in %v", elapsed))
+	slog.Info(fmt.Sprintf("💾 Processed file: %s", filePath))
 }
 
-func runSingleCategory(category string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+func runSingleCategory(parent context.Context, category string, svgOpts svgOptions) {
+	ctx, cancel := context.WithTimeout(parent, 2*time.Minute)
 	defer cancel()
 
 	start := time.Now()
@@ -364,7 +223,9 @@ func runSingleCategory(category string) {
 	case "emojis":
 		RunEmojisOnly(ctx, start)
 	case "svg_icons", "svg-icons":
-		RunSVGIconsOnly(ctx, start)
+		if err := RunSVGIconsOnly(ctx, start, svgOpts); err != nil {
+			exitWithError(err)
+		}
 	case "png_icons", "png-icons":
 		RunPNGIconsOnly(ctx, start)	
 	case "cheatsheets":
@@ -372,10 +233,15 @@ func runSingleCategory(category string) {
 	case "mcp":
 		RunMCPOnly(ctx, start)
 	default:
-		fmt.Printf("❌ Unknown category: %s\n", category)
-		fmt.Println("Available categories: tools, tldr, emojis, svg_icons, png_icons, cheatsheets, mcp")
-		fmt.Println("Usage: go run main.go category=tools")
-		fmt.Println("Or for stem processing: go run main.go stem=output/emojis.json")
+		slog.Error(fmt.Sprintf("❌ Unknown category: %s", category))
+		slog.Info("Available categories: tools, tldr, emojis, svg_icons, png_icons, cheatsheets, mcp")
+		slog.Info("Usage: go run main.go category=tools")
+		slog.Info("Or for stem processing: go run main.go stem=output/emojis.json")
+		slog.Info("Or to query the generated SVG icons over HTTP: go run main.go serve --port 8080")
+		slog.Info("Write files somewhere other than ./output: --out-dir dist/search-index")
+		slog.Info("Write minified JSON for production: --compact")
+		slog.Info("Profile a run for go tool pprof: --profile cpu|mem --profile-file cpu.pprof")
+		slog.Info("SVG icon options: --cluster path/to/cluster_svg.json --cluster-read-attempts 3 --cluster-read-backoff 200ms --format json,ndjson,algolia,sqlite,csv,opensearch,meilisearch --opensearch-index svg_icons --lang fr,de --gzip --gzip-level 9 --workers 8 --stemmer porter2 --ngrams --ngram-size 3 --phonetic --related --related-count 8 --optimize --inline-svg --inline-svg-max-bytes 4096 --rasterize --raster-size 64 --raster-format png --source https://example.com/icons.tgz#icons --source-timeout 2m --id-style hash --base-path /preview/svg_icons/ --sort name --modified-from git --hidden skip --max-description-length 160 --keep-full-description --dedupe --group-variants --variant-suffixes filled,outline --incremental --since 24h --limit 50 --folder feather* --sitemap --emit-schema --emit-offsets --verify-output --max-output-bytes 5000000 --complexity-threshold 500 --report-diff --report-diff-json --compare-python legacy/svg_icons.json --report-similar-names --similar-names-distance 2 --report-visual-dupes --report-tokens --force --no-cache --watch --validate-only --index-only --dry-run --strict --allow empty-description --continue-on-error --verify --update-lock")
 		os.Exit(1)
 	}
 }
@@ -395,32 +261,126 @@ func min(a, b int) int {
 	return b
 }
 
+// outputDir is where all generated files are written, set with --out-dir
+var outputDir = "output"
+
+// compactJSON writes the generated JSON files without indentation, set with --compact
+var compactJSON = false
+
+// defaultStemOptions returns the options of the stem step for the categories without their
+// own: nil, which stems name and description, or the same in compact form with --compact
+func defaultStemOptions() *jargon_stemmer.Options {
+	if !compactJSON {
+		return nil
+	}
+	return &jargon_stemmer.Options{Compact: true}
+}
+
+// stemJSONFile runs the default stem step on a JSON array file of the output directory
+func stemJSONFile(filePath string) error {
+	return jargon_stemmer.ProcessJSONFileWithOptions(filePath, gzip.DefaultCompression, defaultStemOptions())
+}
+
 // ensureOutputDir creates the output directory if it doesn't exist
 func ensureOutputDir() error {
-	outputDir := "output"
 	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
 		return os.MkdirAll(outputDir, 0755)
 	}
 	return nil
 }
 
+// checkOutputDirWritable creates the output directory and verifies files can be created in it,
+// so a bad --out-dir fails before any generation work is done
+func checkOutputDirWritable() error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	probe, err := ioutil.TempFile(outputDir, ".write-check-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
 // saveToJSON saves data to a JSON file in the output directory
 func saveToJSON(filename string, data interface{}) error {
+	return saveToJSONVerified(filename, data, nil)
+}
+
+// saveToJSONVerified is saveToJSON checking the written file with verify before it replaces
+// the previous one, see createFileAtomicVerified
+func saveToJSONVerified(filename string, data interface{}, verify func(tmpPath string) error) error {
 	// Ensure output directory exists
 	if err := ensureOutputDir(); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	// Create full path to output directory
-	fullPath := filepath.Join("output", filename)
+	fullPath := filepath.Join(outputDir, filename)
 
-	file, err := os.Create(fullPath)
-	if err != nil {
-		return err
+	// Write to a temp file and rename it into place, so a killed run never leaves a truncated file
+	return createFileAtomicVerified(fullPath, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		if !compactJSON {
+			encoder.SetIndent("", "  ")
+		}
+		return encoder.Encode(data)
+	}, verify)
+}
+
+// saveToNDJSON saves icons as newline delimited JSON (one object per line) in the output directory.
+// Records are streamed through a buffered writer instead of being marshaled as a single array.
+func saveToNDJSON(filename string, icons []SVGIconData) error {
+	// Ensure output directory exists
+	if err := ensureOutputDir(); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	fullPath := filepath.Join(outputDir, filename)
+
+	return createFileAtomic(fullPath, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		for _, icon := range icons {
+			if err := encoder.Encode(icon); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// saveToJSONGz saves data as gzip compressed JSON in the output directory.
+// The decompressed content is identical to what saveToJSON writes.
+func saveToJSONGz(filename string, data interface{}, level int) error {
+	return saveToJSONGzVerified(filename, data, level, nil)
+}
+
+// saveToJSONGzVerified is saveToJSONGz checking the written file with verify before it
+// replaces the previous one, see createFileAtomicVerified
+func saveToJSONGzVerified(filename string, data interface{}, level int, verify func(tmpPath string) error) error {
+	// Ensure output directory exists
+	if err := ensureOutputDir(); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
 	}
-	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(data)
+	fullPath := filepath.Join(outputDir, filename)
+
+	return createFileAtomicVerified(fullPath, func(w io.Writer) error {
+		gz, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(gz)
+		if !compactJSON {
+			encoder.SetIndent("", "  ")
+		}
+		if err := encoder.Encode(data); err != nil {
+			gz.Close()
+			return err
+		}
+		return gz.Close()
+	}, verify)
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// The --watch test runs this binary again as each regeneration
+	if recordFile := os.Getenv(svgWatchRecordEnv); recordFile != "" {
+		recordSVGWatchRun(recordFile)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// useOutputDir points outputDir at a temporary directory until the test ends, with
+// compactJSON as given
+func useOutputDir(t *testing.T, compact bool) string {
+	t.Helper()
+	previousDir, previousCompact := outputDir, compactJSON
+	t.Cleanup(func() { outputDir, compactJSON = previousDir, previousCompact })
+	outputDir, compactJSON = t.TempDir(), compact
+	return outputDir
+}
+
+// readOutput reads a file of the output directory, decompressing .gz files
+func readOutput(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join(outputDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Ext(name) == ".gz" {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data, err = ioutil.ReadAll(gz); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return data
+}
+
+func TestSaveToJSONCompactRoundTrips(t *testing.T) {
+	icons := []SVGIconData{
+		{ID: "svg-icons-feather-home", Name: "Home", Description: "A <house> & garden", Tags: []string{"home"}, Variants: map[string]string{"outline": "b", "filled": "a"}},
+		{ID: "svg-icons-feather-bell", Name: "Bell", Width: 24, Monochrome: true},
+	}
+
+	for _, name := range []string{"svg_icons.json", "svg_icons.json.gz"} {
+		save := func() []byte {
+			if name == "svg_icons.json" {
+				if err := saveToJSON(name, icons); err != nil {
+					t.Fatalf("saveToJSON: %v", err)
+				}
+			} else if err := saveToJSONGz(name, icons, gzip.DefaultCompression); err != nil {
+				t.Fatalf("saveToJSONGz: %v", err)
+			}
+			return readOutput(t, name)
+		}
+
+		useOutputDir(t, false)
+		pretty := save()
+		useOutputDir(t, true)
+		compact := save()
+
+		if !bytes.Contains(pretty, []byte("\n  {\n    \"id\"")) {
+			t.Errorf("%s: pretty output is not indented:\n%s", name, pretty)
+		}
+		if bytes.Count(compact, []byte("\n")) != 1 || bytes.Contains(compact, []byte(": ")) {
+			t.Errorf("%s: compact output is not on one line:\n%s", name, compact)
+		}
+		// Same content, and the same key order: the compact form is the pretty one without its whitespace
+		var fromPretty, fromCompact []SVGIconData
+		if err := json.Unmarshal(pretty, &fromPretty); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(compact, &fromCompact); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(fromPretty, icons) || !reflect.DeepEqual(fromCompact, icons) {
+			t.Errorf("%s: outputs decode to %+v and %+v, want %+v", name, fromPretty, fromCompact, icons)
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, compact, "", "  "); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(indented.Bytes(), pretty) {
+			t.Errorf("%s: indented compact output differs from the pretty one:\n%s\nwant:\n%s", name, indented.Bytes(), pretty)
+		}
+	}
+}
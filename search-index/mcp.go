@@ -5,13 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
-	jargon_stemmer "search-index/jargon-stemmer"
 )
 
 // MCPMetadata represents the structure of the metadata JSON file
@@ -112,21 +112,21 @@ func generateMCPData(ctx context.Context) ([]MCPData, error) {
 		
 		// Check if category file exists
 		if _, err := os.Stat(categoryPath); os.IsNotExist(err) {
-			fmt.Printf("Warning: Category file not found: %s\n", categoryPath)
+			slog.Warn(fmt.Sprintf("Warning: Category file not found: %s", categoryPath))
 			continue
 		}
 
 		// Read the category JSON file
 		categoryData, err := os.ReadFile(categoryPath)
 		if err != nil {
-			fmt.Printf("Warning: Failed to read category file %s: %v\n", categoryPath, err)
+			slog.Warn(fmt.Sprintf("Warning: Failed to read category file %s: %v", categoryPath, err))
 			continue
 		}
 
 		// Parse the category JSON
 		var category MCPCategory
 		if err := json.Unmarshal(categoryData, &category); err != nil {
-			fmt.Printf("Warning: Failed to parse category JSON %s: %v\n", categoryPath, err)
+			slog.Warn(fmt.Sprintf("Warning: Failed to parse category JSON %s: %v", categoryPath, err))
 			continue
 		}
 
@@ -189,7 +189,7 @@ func formatRepositoryName(name string) string {
 
 // RunMCPOnly runs only the MCP data generation
 func RunMCPOnly(ctx context.Context, start time.Time) {
-	fmt.Println("🔧 Generating MCP data only...")
+	slog.Info("🔧 Generating MCP data only...")
 
 	mcpData, err := generateMCPData(ctx)
 	if err != nil {
@@ -202,32 +202,32 @@ func RunMCPOnly(ctx context.Context, start time.Time) {
 	}
 
 	elapsed := time.Since(start)
-	fmt.Printf("\n🎉 MCP data generation completed in %v\n", elapsed)
-	fmt.Printf("📊 Generated %d MCP repositories\n", len(mcpData))
+	slog.Info(fmt.Sprintf("\n🎉 MCP data generation completed in %v", elapsed), "category", "mcp", "elapsed", elapsed.String())
+	slog.Info(fmt.Sprintf("📊 Generated %d MCP repositories", len(mcpData)), "category", "mcp", "count", len(mcpData))
 
 	// Show sample data
-	fmt.Println("\n📝 Sample MCP repositories:")
+	slog.Info("\n📝 Sample MCP repositories:")
 	for i, repo := range mcpData {
 		if i >= 10 { // Show first 10
-			fmt.Printf("  ... and %d more repositories\n", len(mcpData)-10)
+			slog.Info(fmt.Sprintf("  ... and %d more repositories", len(mcpData)-10))
 			break
 		}
-		fmt.Printf("  %d. %s (ID: %s)\n", i+1, repo.Name, repo.ID)
+		slog.Info(fmt.Sprintf("  %d. %s (ID: %s)", i+1, repo.Name, repo.ID))
 		if repo.Description != "" {
-			fmt.Printf("     Description: %s\n", truncateString(repo.Description, 80))
+			slog.Info(fmt.Sprintf("     Description: %s", truncateString(repo.Description, 80)))
 		}
-		fmt.Printf("     Owner: %s | Stars: %d | Language: %s\n", repo.Owner, repo.Stars, repo.Language)
-		fmt.Printf("     Path: %s\n", repo.Path)
-		fmt.Println()
+		slog.Info(fmt.Sprintf("     Owner: %s | Stars: %d | Language: %s", repo.Owner, repo.Stars, repo.Language))
+		slog.Info(fmt.Sprintf("     Path: %s", repo.Path))
+		slog.Info("")
 	}
 
-	fmt.Printf("💾 Data saved to output/mcp.json\n")
+	slog.Info(fmt.Sprintf("💾 Data saved to %s", filepath.Join(outputDir, "mcp.json")))
 	
 	// Automatically run stem processing
-	fmt.Println("\n🔍 Running stem processing...")
-	if err := jargon_stemmer.ProcessJSONFile("output/mcp.json"); err != nil {
+	slog.Info("\n🔍 Running stem processing...")
+	if err := stemJSONFile(filepath.Join(outputDir, "mcp.json")); err != nil {
 		log.Fatalf("❌ Stem processing failed: %v", err)
 	}
-	fmt.Println("✅ Stem processing completed!")
+	slog.Info("✅ Stem processing completed!")
 }
 
@@ -6,15 +6,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
-	"regexp"
-	jargon_stemmer "search-index/jargon-stemmer"
+	"log/slog"
+	"path/filepath"
+	"search-index/svgicons"
 	"sort"
 	"strings"
 	"time"
 )
 
 func generatePNGIconsData(ctx context.Context) ([]SVGIconData, error) {
-	fmt.Println("🖼️ Generating PNG icons data...")
+	slog.Info("🖼️ Generating PNG icons data...")
 
 	// Path to PNG cluster.json file
 	clusterPath := "../frontend/data/cluster_png.json"
@@ -33,7 +34,7 @@ func generatePNGIconsData(ctx context.Context) ([]SVGIconData, error) {
 	categoryCount := 0
 	iconCount := 0
 
-	fmt.Println("Processing categories:")
+	slog.Info("Processing categories:")
 
 	for _, clusterEntry := range cluster.Clusters {
 		select {
@@ -50,7 +51,7 @@ func generatePNGIconsData(ctx context.Context) ([]SVGIconData, error) {
 			iconName := strings.TrimPrefix(fileName.FileName, "_")
 			iconName = strings.TrimSuffix(iconName, ".svg")
 
-			displayName := formatIconName(iconName)
+			displayName := svgicons.FormatIconName(iconName)
 			iconPath := fmt.Sprintf("/freedevtools/png_icons/%s/%s/", clusterEntry.SourceFolder, iconName)
 			iconID := generatePNGIconIDFromPath(iconPath)
 
@@ -77,7 +78,7 @@ func generatePNGIconsData(ctx context.Context) ([]SVGIconData, error) {
 		return pngIconsData[i].ID < pngIconsData[j].ID
 	})
 
-	fmt.Printf("🖼️ Processed %d categories with %d PNG icons total\n", categoryCount, iconCount)
+	slog.Info(fmt.Sprintf("🖼️ Processed %d categories with %d PNG icons total", categoryCount, iconCount))
 	return pngIconsData, nil
 }
 
@@ -90,14 +91,13 @@ func generatePNGIconIDFromPath(path string) string {
 	// Replace remaining slashes with dashes
 	cleanPath = strings.Replace(cleanPath, "/", "-", -1)
 	
-	reg := regexp.MustCompile(`[^a-zA-Z0-9\-_]`)
-	cleanPath = reg.ReplaceAllString(cleanPath, "_")
+	cleanPath = invalidIDChars.ReplaceAllString(cleanPath, "_")
 	return fmt.Sprintf("png-icons-%s", sanitizeID(cleanPath))
 }
 
 
 func RunPNGIconsOnly(ctx context.Context, start time.Time) {
-	fmt.Println("🖼️ Generating PNG icons data only...")
+	slog.Info("🖼️ Generating PNG icons data only...")
 
 	icons, err := generatePNGIconsData(ctx)
 	if err != nil {
@@ -109,31 +109,31 @@ func RunPNGIconsOnly(ctx context.Context, start time.Time) {
 	}
 
 	elapsed := time.Since(start)
-	fmt.Printf("\n🎉 PNG icons data generation completed in %v\n", elapsed)
-	fmt.Printf("📊 Generated %d PNG icons\n", len(icons))
+	slog.Info(fmt.Sprintf("\n🎉 PNG icons data generation completed in %v", elapsed), "category", "png_icons", "elapsed", elapsed.String())
+	slog.Info(fmt.Sprintf("📊 Generated %d PNG icons", len(icons)), "category", "png_icons", "iconCount", len(icons))
 
 	// Show sample
-	fmt.Println("\n📝 Sample PNG icons:")
+	slog.Info("\n📝 Sample PNG icons:")
 	for i, icon := range icons {
 		if i >= 10 {
-			fmt.Printf("  ... and %d more icons\n", len(icons)-10)
+			slog.Info(fmt.Sprintf("  ... and %d more icons", len(icons)-10))
 			break
 		}
-		fmt.Printf("  %d. %s (ID: %s)\n", i+1, icon.Name, icon.ID)
+		slog.Info(fmt.Sprintf("  %d. %s (ID: %s)", i+1, icon.Name, icon.ID))
 		if icon.Description != "" {
-			fmt.Printf("     Description: %s\n", truncateString(icon.Description, 80))
+			slog.Info(fmt.Sprintf("     Description: %s", truncateString(icon.Description, 80)))
 		}
-		fmt.Printf("     Image: %s\n", icon.Image)
-		fmt.Printf("     Path: %s\n", icon.Path)
-		fmt.Println()
+		slog.Info(fmt.Sprintf("     Image: %s", icon.Image))
+		slog.Info(fmt.Sprintf("     Path: %s", icon.Path))
+		slog.Info("")
 	}
 
-	fmt.Printf("💾 Data saved to output/png_icons.json\n")
+	slog.Info(fmt.Sprintf("💾 Data saved to %s", filepath.Join(outputDir, "png_icons.json")))
 	
 	// Automatically run stem processing
-	fmt.Println("\n🔍 Running stem processing...")
-	if err := jargon_stemmer.ProcessJSONFile("output/png_icons.json"); err != nil {
+	slog.Info("\n🔍 Running stem processing...")
+	if err := stemJSONFile(filepath.Join(outputDir, "png_icons.json")); err != nil {
 		log.Fatalf("❌ Stem processing failed: %v", err)
 	}
-	fmt.Println("✅ Stem processing completed!")
+	slog.Info("✅ Stem processing completed!")
 }
\ No newline at end of file
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// Profiles written by --profile, for go tool pprof
+const (
+	profileCPU = "cpu" // CPU samples of the whole run
+	profileMem = "mem" // Heap allocations, written once the run is done
+)
+
+// profileKinds lists the values accepted by --profile
+var profileKinds = []string{profileCPU, profileMem}
+
+// stopProfiling finishes the profile started by startProfiling, a no-op without --profile.
+// exitWithError calls it too, since os.Exit skips the deferred call of main.
+var stopProfiling = func() {}
+
+// defaultProfileFile is where a profile is written without --profile-file, e.g. cpu.pprof
+func defaultProfileFile(kind string) string {
+	return kind + ".pprof"
+}
+
+// startProfiling starts the --profile profile, written to file by stopProfiling. The CPU
+// profile samples from now on; the memory profile is only taken at the end, after a garbage
+// collection so it shows the live heap next to every allocation made during the run.
+func startProfiling(kind, file string) error {
+	if kind == "" {
+		return nil
+	}
+	out, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("failed to create profile %s: %w", file, err)
+	}
+
+	if kind == profileCPU {
+		if err := pprof.StartCPUProfile(out); err != nil {
+			out.Close()
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+	}
+
+	done := false
+	stopProfiling = func() {
+		if done {
+			return
+		}
+		done = true
+		if kind == profileCPU {
+			pprof.StopCPUProfile()
+		} else {
+			runtime.GC()
+			if err := pprof.WriteHeapProfile(out); err != nil {
+				slog.Warn(fmt.Sprintf("⚠️  Warning: Failed to write memory profile %s: %v", file, err))
+			}
+		}
+		if err := out.Close(); err != nil {
+			slog.Warn(fmt.Sprintf("⚠️  Warning: Failed to write profile %s: %v", file, err))
+			return
+		}
+		slog.Info(fmt.Sprintf("📈 %s profile saved to %s, open it with: go tool pprof %s", kind, file, file), "profile", kind, "file", file)
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	jargon_stemmer "search-index/jargon-stemmer"
+	"search-index/svgicons"
+)
+
+// searchIndexFile holds the records of every category in a single array
+const searchIndexFile = "search_index.json"
+
+// categoryRun generates and saves the data of one category as part of RunAll
+type categoryRun struct {
+	Label string                  // Name used in messages, e.g. "SVG Icons"
+	Files []string                // Files written by save, stemmed afterwards
+	Stem  *jargon_stemmer.Options // Stem options for Files, nil for the default ones
+
+	// run generates the records and returns them with their count and a function saving them,
+	// which is skipped once the run is cancelled
+	run func(ctx context.Context) (interface{}, int, func() error, error)
+	// check runs once Files are stemmed and fails the category with its error, nil for none
+	check func() error
+	// close releases what run opened once RunAll is done, nil when there is nothing to release
+	close func()
+}
+
+// categoryResult is the outcome of a categoryRun
+type categoryResult struct {
+	Records []json.RawMessage
+	Err     error
+}
+
+// categoryRuns lists the generators run by RunAll, in the order their records appear in
+// search_index.json. A new category only needs an entry here.
+func categoryRuns(svgOpts svgOptions) []categoryRun {
+	svgFiles := []string{}
+	if svgOpts.hasFormat("json") {
+		svgFiles = append(svgFiles, svgOpts.svgJSONFile())
+	}
+	if svgOpts.hasFormat("ndjson") {
+		svgFiles = append(svgFiles, "svg_icons.ndjson")
+	}
+
+	// The SVG sources stay open until RunAll is done, --optimize reads the SVGs while saving.
+	// The report, parity and --verify-output verifier of the SVG run are used once it is stemmed.
+	var svgSources *svgicons.Sources
+	var svgReport *svgicons.Report
+	var svgIconsParity *svgParity
+	svgStem := svgOpts.stemOptions()
+
+	return []categoryRun{
+		{Label: "Tools", Files: []string{"tools.json"}, run: func(ctx context.Context) (interface{}, int, func() error, error) {
+			tools, err := generateToolsData(ctx)
+			return tools, len(tools), func() error { return saveToJSON("tools.json", tools) }, err
+		}},
+		{Label: "TLDR Pages", Files: []string{"tldr_pages.json"}, run: func(ctx context.Context) (interface{}, int, func() error, error) {
+			tldr, err := generateTLDRData(ctx)
+			return tldr, len(tldr), func() error { return saveToJSON("tldr_pages.json", tldr) }, err
+		}},
+		{Label: "Emojis", Files: []string{"emojis.json"}, run: func(ctx context.Context) (interface{}, int, func() error, error) {
+			emojis, err := generateEmojisData(ctx)
+			return emojis, len(emojis), func() error { return saveToJSON("emojis.json", emojis) }, err
+		}},
+		{Label: "SVG Icons", Files: svgFiles, Stem: svgStem, run: func(ctx context.Context) (interface{}, int, func() error, error) {
+			sources, err := svgicons.OpenSources(ctx, svgOpts.Options)
+			if err != nil {
+				return nil, 0, nil, fmt.Errorf("failed to open SVG sources: %w", err)
+			}
+			svgSources = sources
+			svgIcons, report, err := svgicons.Generate(ctx, svgOpts.Options)
+			if err == nil {
+				err = checkSVGStrict(report, svgOpts)
+			}
+			if err == nil {
+				svgIconsParity, err = compareSVGIcons(svgIcons, svgOpts)
+			}
+			svgReport = report
+			svgStem.Verify = svgStemOptions(svgIcons, svgOpts).Verify
+			return svgIcons, len(svgIcons), func() error { return saveSVGIcons(svgIcons, svgOpts) }, err
+		}, check: func() error {
+			if err := saveSVGOffsets(svgOpts); err != nil {
+				return err
+			}
+			return checkSVGOutput(svgReport, svgIconsParity, svgOpts)
+		}, close: func() {
+			if svgSources != nil {
+				svgSources.Close()
+			}
+		}},
+		{Label: "PNG Icons", Files: []string{"png_icons.json"}, run: func(ctx context.Context) (interface{}, int, func() error, error) {
+			pngIcons, err := generatePNGIconsData(ctx)
+			return pngIcons, len(pngIcons), func() error { return saveToJSON("png_icons.json", pngIcons) }, err
+		}},
+		{Label: "Cheatsheets", Files: []string{"cheatsheets.json"}, run: func(ctx context.Context) (interface{}, int, func() error, error) {
+			cheatsheets, err := generateCheatsheetsData(ctx)
+			return cheatsheets, len(cheatsheets), func() error { return saveToJSON("cheatsheets.json", cheatsheets) }, err
+		}},
+		{Label: "MCP", Files: []string{"mcp.json"}, run: func(ctx context.Context) (interface{}, int, func() error, error) {
+			mcp, err := generateMCPData(ctx)
+			return mcp, len(mcp), func() error { return saveToJSON("mcp.json", mcp) }, err
+		}},
+	}
+}
+
+// RunAll runs every category generator concurrently, saves and stems the per-category files
+// and merges the records of all categories into search_index.json. A failing category is
+// reported and left out without stopping the others; any failure is returned as an error.
+func RunAll(ctx context.Context, start time.Time, svgOpts svgOptions) error {
+	runs := categoryRuns(svgOpts)
+	results := make([]categoryResult, len(runs))
+	for _, run := range runs {
+		if run.close != nil {
+			defer run.close()
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range runs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = runCategory(ctx, runs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Stem the files of the categories that succeeded, empty ones have nothing to stem
+	slog.Info("\n🔍 Running stem processing on all files...")
+	for i, run := range runs {
+		if results[i].Err != nil || len(results[i].Records) == 0 {
+			continue
+		}
+		for _, fileName := range run.Files {
+			filePath := filepath.Join(outputDir, fileName)
+			slog.Info(fmt.Sprintf("Processing %s...", filePath))
+
+			var err error
+			if strings.HasSuffix(fileName, ".ndjson") {
+				err = jargon_stemmer.ProcessNDJSONFileWithOptions(filePath, run.Stem)
+			} else {
+				err = jargon_stemmer.ProcessJSONFileWithOptions(filePath, svgOpts.GzipLevel, stemOptionsOrDefault(run.Stem))
+			}
+			if err != nil {
+				results[i].Err = fmt.Errorf("%s stem processing failed for %s: %w", run.Label, filePath, err)
+				slog.Error(fmt.Sprintf("❌ Error: %v", results[i].Err))
+				break
+			}
+			slog.Info(fmt.Sprintf("✅ Completed %s", filePath))
+		}
+	}
+
+	// Check the stemmed files, an empty category can still have failed checks
+	for i, run := range runs {
+		if results[i].Err != nil || run.check == nil {
+			continue
+		}
+		if err := run.check(); err != nil {
+			results[i].Err = fmt.Errorf("%s checks failed: %w", run.Label, err)
+			slog.Error(fmt.Sprintf("❌ Error: %v", results[i].Err))
+		}
+	}
+
+	// Merge the records of every category that succeeded
+	var records []json.RawMessage
+	var failed []error
+	total := 0
+	for i := range runs {
+		if results[i].Err != nil {
+			failed = append(failed, results[i].Err)
+			continue
+		}
+		records = append(records, results[i].Records...)
+		total += len(results[i].Records)
+	}
+	if records == nil {
+		records = []json.RawMessage{}
+	}
+	if err := saveToJSON(searchIndexFile, records); err != nil {
+		failed = append(failed, fmt.Errorf("failed to save %s: %w", searchIndexFile, err))
+	} else if err := jargon_stemmer.ProcessJSONFileWithOptions(filepath.Join(outputDir, searchIndexFile), svgOpts.GzipLevel, searchIndexStemOptions(svgOpts)); err != nil {
+		failed = append(failed, fmt.Errorf("stem processing failed for %s: %w", searchIndexFile, err))
+	}
+
+	elapsed := time.Since(start)
+	slog.Info(fmt.Sprintf("\n🎉 Search index generation completed in %v", elapsed), "elapsed", elapsed.String(), "count", total, "failed", len(failed))
+	slog.Info("📊 Generated data:")
+	for i, run := range runs {
+		if results[i].Err != nil {
+			slog.Info(fmt.Sprintf("  - %s: failed", run.Label))
+			continue
+		}
+		slog.Info(fmt.Sprintf("  - %s: %d items", run.Label, len(results[i].Records)), "category", run.Label, "count", len(results[i].Records))
+	}
+	slog.Info(fmt.Sprintf("  - Total: %d items in %s", total, filepath.Join(outputDir, searchIndexFile)))
+	slog.Info(fmt.Sprintf("\n💾 All files saved to %s directory", outputDir))
+
+	if len(failed) > 0 {
+		slog.Error(fmt.Sprintf("❌ %d errors occurred during generation:", len(failed)))
+		for _, err := range failed {
+			slog.Error(fmt.Sprintf("  - %v", err))
+		}
+		return fmt.Errorf("search index generation failed with %d errors", len(failed))
+	}
+	return nil
+}
+
+// runCategory runs a single category and converts its records for search_index.json
+func runCategory(ctx context.Context, run categoryRun) categoryResult {
+	data, count, save, err := run.run(ctx)
+	if err == nil {
+		// Generation finished, but nothing is written after an interrupt
+		err = ctx.Err()
+	}
+	if err == nil {
+		err = save()
+	}
+	if err != nil {
+		err = fmt.Errorf("%s data generation failed: %w", run.Label, err)
+		slog.Error(fmt.Sprintf("❌ Error: %v", err))
+		return categoryResult{Err: err}
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return categoryResult{Err: fmt.Errorf("failed to encode %s records: %w", run.Label, err)}
+	}
+	records := make([]json.RawMessage, 0, count)
+	if err := json.Unmarshal(encoded, &records); err != nil {
+		return categoryResult{Err: fmt.Errorf("failed to encode %s records: %w", run.Label, err)}
+	}
+
+	slog.Info(fmt.Sprintf("✅ %s data collected: %d items", run.Label, count), "category", run.Label, "count", count)
+	return categoryResult{Records: records}
+}
+
+// searchIndexStemOptions stems the fields shared by the records of every category,
+// keeping the fields only some of them have, such as the tags and keywords of SVG icons
+func searchIndexStemOptions(svgOpts svgOptions) *jargon_stemmer.Options {
+	return &jargon_stemmer.Options{
+		Fields:  []string{"Name", "Description", "Tags", "Keywords"},
+		Stemmer: svgOpts.Stemmer,
+		Compact: svgOpts.Compact,
+	}
+}
+
+// stemOptionsOrDefault returns the stem options of a category, defaultStemOptions when it has none
+func stemOptionsOrDefault(opts *jargon_stemmer.Options) *jargon_stemmer.Options {
+	if opts == nil {
+		return defaultStemOptions()
+	}
+	return opts
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// testCategoryRun saves count records to <label>.json, failing with err when not nil
+func testCategoryRun(label string, count int, err error) categoryRun {
+	return categoryRun{Label: label, Files: []string{label + ".json"}, run: func(ctx context.Context) (interface{}, int, func() error, error) {
+		records := make([]map[string]string, count)
+		for i := range records {
+			records[i] = map[string]string{"id": fmt.Sprintf("%s-%d", label, i)}
+		}
+		return records, count, func() error { return saveToJSON(label+".json", records) }, err
+	}}
+}
+
+func TestRunCategoryConcurrently(t *testing.T) {
+	useOutputDir(t, true)
+	runs := []categoryRun{testCategoryRun("tools", 3, nil), testCategoryRun("emojis", 0, nil), testCategoryRun("broken", 2, errors.New("no data")), testCategoryRun("mcp", 5, nil)}
+
+	// Like RunAll, every category runs in its own goroutine and writes to its own result
+	results := make([]categoryResult, len(runs))
+	var wg sync.WaitGroup
+	for i := range runs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = runCategory(context.Background(), runs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, want := range []int{3, 0, -1, 5} {
+		if want < 0 {
+			if results[i].Err == nil {
+				t.Errorf("%s succeeded, want its error", runs[i].Label)
+			}
+			if _, err := os.Stat(filepath.Join(outputDir, "broken.json")); !os.IsNotExist(err) {
+				t.Error("the failed category was saved")
+			}
+			continue
+		}
+		if results[i].Err != nil || len(results[i].Records) != want {
+			t.Errorf("%s = %d records, %v; want %d", runs[i].Label, len(results[i].Records), results[i].Err, want)
+			continue
+		}
+		var saved []map[string]string
+		if err := json.Unmarshal(readOutput(t, runs[i].Label+".json"), &saved); err != nil || len(saved) != want {
+			t.Errorf("%s.json has %d records (%v), want %d", runs[i].Label, len(saved), err, want)
+		}
+	}
+}
+
+func TestRunCategoryCancelled(t *testing.T) {
+	useOutputDir(t, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// A generator finishing after the interrupt is not saved
+	if result := runCategory(ctx, testCategoryRun("tools", 3, nil)); !errors.Is(result.Err, context.Canceled) {
+		t.Errorf("runCategory after cancel = %v, want context.Canceled", result.Err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "tools.json")); !os.IsNotExist(err) {
+		t.Error("tools.json was saved after cancel")
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// svgAutocompleteFile maps name prefixes to suggested icon IDs
+const svgAutocompleteFile = "svg_icons_autocomplete.json"
+
+const (
+	autocompleteMaxResults = 20 // IDs kept per prefix
+	autocompleteMaxPrefix  = 12 // Longest prefix indexed, in runes
+)
+
+// autocompleteRanker reports whether icon a should be suggested before icon b
+type autocompleteRanker func(a, b *SVGIconData) bool
+
+// rankAlphabetical suggests icons in name order, falling back to ID for equal names
+func rankAlphabetical(a, b *SVGIconData) bool {
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	return a.ID < b.ID
+}
+
+// buildAutocomplete maps every prefix of each lowercased name token to the best ranked
+// icon IDs with a token starting with it, capped at autocompleteMaxResults per prefix
+func buildAutocomplete(icons []SVGIconData, rank autocompleteRanker) map[string][]string {
+	ranked := make([]*SVGIconData, len(icons))
+	for i := range icons {
+		ranked[i] = &icons[i]
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return rank(ranked[i], ranked[j])
+	})
+
+	prefixes := make(map[string][]string)
+	for _, icon := range ranked {
+		seen := make(map[string]bool)
+		for _, token := range nameTokens(icon.Name) {
+			runes := []rune(token)
+			for length := 1; length <= len(runes) && length <= autocompleteMaxPrefix; length++ {
+				prefix := string(runes[:length])
+				if seen[prefix] || len(prefixes[prefix]) >= autocompleteMaxResults {
+					continue
+				}
+				seen[prefix] = true
+				prefixes[prefix] = append(prefixes[prefix], icon.ID)
+			}
+		}
+	}
+
+	return prefixes
+}
+
+// nameTokens splits a display name into lowercased words
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestBuildAutocomplete(t *testing.T) {
+	icons := []SVGIconData{
+		{ID: "svg-icons-feather-arrow-up", Name: "Arrow Up"},
+		{ID: "svg-icons-feather-home", Name: "Home"},
+		{ID: "svg-icons-feather-arrow-down", Name: "Arrow Down"},
+		{ID: "svg-icons-feather-narrow", Name: "Narrow"},
+		{ID: "svg-icons-feather-arrow-right-arrow", Name: "Arrow Right Arrow"},
+	}
+	prefixes := buildAutocomplete(icons, rankAlphabetical)
+
+	// Alphabetical by name, an icon listed once even with the prefix in two of its words,
+	// and only at the start of words so "narrow" is left out
+	want := []string{"svg-icons-feather-arrow-down", "svg-icons-feather-arrow-right-arrow", "svg-icons-feather-arrow-up"}
+	if got := prefixes["arr"]; !reflect.DeepEqual(got, want) {
+		t.Errorf(`prefixes["arr"] = %v, want %v`, got, want)
+	}
+	if got := prefixes["h"]; !reflect.DeepEqual(got, []string{"svg-icons-feather-home"}) {
+		t.Errorf(`prefixes["h"] = %v, want the home icon`, got)
+	}
+	if _, ok := prefixes["rrow"]; ok {
+		t.Error(`"rrow" is indexed, want only word prefixes`)
+	}
+}
+
+func TestBuildAutocompleteRanker(t *testing.T) {
+	icons := []SVGIconData{
+		{ID: "svg-icons-a-arrow-down", Name: "Arrow Down"},
+		{ID: "svg-icons-a-arrow-up", Name: "Arrow Up", Popularity: 10},
+	}
+	if got := buildAutocomplete(icons, rankPopular)["arrow"]; !reflect.DeepEqual(got, []string{"svg-icons-a-arrow-up", "svg-icons-a-arrow-down"}) {
+		t.Errorf(`rankPopular prefixes["arrow"] = %v, want the popular icon first`, got)
+	}
+}
+
+func TestBuildAutocompleteLimits(t *testing.T) {
+	var icons []SVGIconData
+	for i := 0; i < autocompleteMaxResults+5; i++ {
+		icons = append(icons, SVGIconData{ID: fmt.Sprintf("svg-icons-a-arrow-%02d", i), Name: fmt.Sprintf("Arrow %02d", i)})
+	}
+	icons = append(icons, SVGIconData{ID: "svg-icons-a-long", Name: "Supercalifragilistic"})
+	prefixes := buildAutocomplete(icons, rankAlphabetical)
+
+	if got := len(prefixes["a"]); got != autocompleteMaxResults {
+		t.Errorf(`prefixes["a"] has %d IDs, want %d`, got, autocompleteMaxResults)
+	}
+	if _, ok := prefixes["supercalifra"]; !ok {
+		t.Errorf("the %d rune prefix is not indexed", autocompleteMaxPrefix)
+	}
+	if _, ok := prefixes["supercalifrag"]; ok {
+		t.Errorf("a prefix longer than %d runes is indexed", autocompleteMaxPrefix)
+	}
+}
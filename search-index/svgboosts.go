@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// fieldBoostsFile optionally overrides the field boosts of a category's search index, keyed by
+// category then field, e.g. {"svg_icons": {"name": 4, "tags": 2, "description": 0.5}}
+const fieldBoostsFile = "field_boosts.json"
+
+// fieldBoosts multiply the term frequency of a token by the field it occurs in, so a match in
+// the name outranks one only in the description. All 1 gives plain TF-IDF.
+type fieldBoosts struct {
+	Name        float64
+	Tags        float64
+	Description float64
+}
+
+// defaultFieldBoosts are the SVG icon boosts without fieldBoostsFile
+var defaultFieldBoosts = fieldBoosts{Name: 3, Tags: 2, Description: 1}
+
+// svgFieldBoosts holds the boosts of the SVG icons index, from fieldBoostsFile or the defaults
+var svgFieldBoosts = defaultFieldBoosts
+
+// loadFieldBoosts reads field boosts from a JSON file into svgFieldBoosts. Fields it does not
+// list keep their default. Only svg_icons builds a search index, so other categories, unknown
+// fields and negative boosts are errors rather than silently ignored.
+func loadFieldBoosts(filePath string) error {
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var categories map[string]map[string]float64
+	if err := json.Unmarshal(content, &categories); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	names := make([]string, 0, len(categories))
+	for category := range categories {
+		names = append(names, category)
+	}
+	sort.Strings(names)
+
+	boosts := defaultFieldBoosts
+	for _, category := range names {
+		if category != "svg_icons" {
+			return fmt.Errorf("%s: unknown category %q, only svg_icons builds a search index", filePath, category)
+		}
+		for field, boost := range categories[category] {
+			if boost < 0 {
+				return fmt.Errorf("%s: %s boost of %s is %v, must not be negative", filePath, category, field, boost)
+			}
+			switch strings.ToLower(field) {
+			case "name":
+				boosts.Name = boost
+			case "tags":
+				boosts.Tags = boost
+			case "description":
+				boosts.Description = boost
+			default:
+				return fmt.Errorf("%s: unknown %s field %q, expected name, tags or description", filePath, category, field)
+			}
+		}
+	}
+	svgFieldBoosts = boosts
+	return nil
+}
+
+// boostedTermFrequencies returns how often each token occurs across the fields of an icon,
+// each occurrence counted at the boost of its field, relative to the number of tokens. With
+// every boost at 1 it is the plain term frequency of the tokens of all three fields.
+func boostedTermFrequencies(name, tags, description []string, boosts fieldBoosts) map[string]float64 {
+	frequencies := make(map[string]float64)
+	total := len(name) + len(tags) + len(description)
+	if total == 0 {
+		return frequencies
+	}
+	for _, field := range []struct {
+		tokens []string
+		boost  float64
+	}{{name, boosts.Name}, {tags, boosts.Tags}, {description, boosts.Description}} {
+		for _, token := range field.tokens {
+			frequencies[token] += field.boost
+		}
+	}
+	for token := range frequencies {
+		frequencies[token] /= float64(total)
+	}
+	return frequencies
+}
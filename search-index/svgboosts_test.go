@@ -0,0 +1,91 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// indexWeight returns the weight of a token for an icon in a search index, 0 when absent
+func indexWeight(index *SearchIndex, token, id string) float64 {
+	entry, ok := index.Tokens[token]
+	if !ok {
+		return 0
+	}
+	for i, entryID := range entry.IDs {
+		if entryID == id {
+			return entry.Weights[i]
+		}
+	}
+	return 0
+}
+
+func TestNameMatchOutranksDescription(t *testing.T) {
+	icons := []SVGIconData{
+		{ID: "svg-icons-a-bell", Name: "Bell", Description: "Rings for alerts"},
+		{ID: "svg-icons-a-alarm", Name: "Alarm", Description: "A bell that rings"},
+		{ID: "svg-icons-a-home", Name: "Home", Description: "A house"},
+	}
+	index := buildSearchIndex(icons, svgOptions{}.stemOptions(), 0, defaultFieldBoosts)
+
+	name, description := indexWeight(index, "bell", "svg-icons-a-bell"), indexWeight(index, "bell", "svg-icons-a-alarm")
+	if description == 0 || name <= description {
+		t.Errorf(`"bell" weighs %v in the name and %v in a description, want the name match first`, name, description)
+	}
+
+	// Without boosts the description match, in a text of as many tokens, weighs the same
+	flat := buildSearchIndex(icons, svgOptions{}.stemOptions(), 0, fieldBoosts{Name: 1, Tags: 1, Description: 1})
+	if name, description := indexWeight(flat, "bell", "svg-icons-a-bell"), indexWeight(flat, "bell", "svg-icons-a-alarm"); name != description {
+		t.Errorf(`unboosted "bell" weighs %v and %v, want the same`, name, description)
+	}
+}
+
+func TestBoostedTermFrequencies(t *testing.T) {
+	got := boostedTermFrequencies([]string{"bell"}, []string{"bell", "alert"}, []string{"ring"}, defaultFieldBoosts)
+	want := map[string]float64{"bell": 5.0 / 4, "alert": 2.0 / 4, "ring": 1.0 / 4}
+	for token, frequency := range want {
+		if got[token] != frequency {
+			t.Errorf("frequency of %s = %v, want %v", token, got[token], frequency)
+		}
+	}
+	if got := boostedTermFrequencies(nil, nil, nil, defaultFieldBoosts); len(got) != 0 {
+		t.Errorf("frequencies of no tokens = %v, want none", got)
+	}
+}
+
+func TestLoadFieldBoosts(t *testing.T) {
+	previous := svgFieldBoosts
+	defer func() { svgFieldBoosts = previous }()
+	write := func(content string) string {
+		filePath := filepath.Join(t.TempDir(), "field_boosts.json")
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return filePath
+	}
+
+	// Fields left out keep their default
+	if err := loadFieldBoosts(write(`{"svg_icons": {"Name": 5, "description": 0.5}}`)); err != nil {
+		t.Fatalf("loadFieldBoosts: %v", err)
+	}
+	if want := (fieldBoosts{Name: 5, Tags: 2, Description: 0.5}); svgFieldBoosts != want {
+		t.Errorf("boosts = %+v, want %+v", svgFieldBoosts, want)
+	}
+
+	svgFieldBoosts = defaultFieldBoosts
+	if err := loadFieldBoosts(filepath.Join(t.TempDir(), "missing.json")); err != nil || svgFieldBoosts != defaultFieldBoosts {
+		t.Errorf("loadFieldBoosts of a missing file = %v with %+v, want the defaults", err, svgFieldBoosts)
+	}
+
+	for content, want := range map[string]string{
+		`{"png_icons": {"name": 2}}`:  `unknown category "png_icons"`,
+		`{"svg_icons": {"usage": 2}}`: `unknown svg_icons field "usage"`,
+		`{"svg_icons": {"name": -1}}`: "must not be negative",
+		`{"svg_icons": []}`:           "failed to parse",
+	} {
+		if err := loadFieldBoosts(write(content)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("loadFieldBoosts(%s) = %v, want an error saying %q", content, err, want)
+		}
+	}
+}
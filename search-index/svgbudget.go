@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// formatByteSize prints a size in bytes with its binary multiple, e.g. 1572864 bytes (1.5 MiB)
+func formatByteSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d bytes", size)
+	}
+	value, prefix := float64(size)/unit, "KMGTPE"
+	i := 0
+	for value >= unit && i < len(prefix)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%d bytes (%.1f %ciB)", size, value, prefix[i])
+}
+
+// checkSVGOutputBudget fails a --max-output-bytes run whose svg_icons.json, as shipped after
+// the stem step and compressed with --gzip, is larger than the budget. Without a budget it
+// does nothing.
+func checkSVGOutputBudget(opts svgOptions) error {
+	if opts.MaxOutputBytes <= 0 {
+		return nil
+	}
+	filePath := filepath.Join(outputDir, opts.svgJSONFile())
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to check the size of %s: %w", filePath, err)
+	}
+
+	size := info.Size()
+	if size > opts.MaxOutputBytes {
+		slog.Error(fmt.Sprintf("❌ %s is %s, over the --max-output-bytes budget of %s by %s", filePath, formatByteSize(size), formatByteSize(opts.MaxOutputBytes), formatByteSize(size-opts.MaxOutputBytes)), "file", filePath, "bytes", size, "maxBytes", opts.MaxOutputBytes)
+		return fmt.Errorf("%s is %d bytes, over the budget of %d bytes", opts.svgJSONFile(), size, opts.MaxOutputBytes)
+	}
+	slog.Info(fmt.Sprintf("📦 %s is %s, within the budget of %s (%.0f%%)", filePath, formatByteSize(size), formatByteSize(opts.MaxOutputBytes), float64(size)*100/float64(opts.MaxOutputBytes)), "file", filePath, "bytes", size, "maxBytes", opts.MaxOutputBytes)
+	return nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// svgChangesFile is the diff against the previous svg_icons.json, written by --report-diff-json
+const svgChangesFile = "changes.json"
+
+// svgDiffListed is the most added, removed or changed icon IDs listed in the console summary
+const svgDiffListed = 20
+
+// svgIconChange is an icon present in both outputs with different data
+type svgIconChange struct {
+	ID     string   `json:"id"`
+	Fields []string `json:"fields"` // JSON names of the fields that differ, sorted
+}
+
+// svgDiff compares the icons about to be written with the previous output, by icon ID
+type svgDiff struct {
+	Previous    bool            `json:"previous"` // Whether there was a previous output to compare with
+	Added       []string        `json:"added"`
+	Removed     []string        `json:"removed"`
+	Changed     []svgIconChange `json:"changed"`
+	FieldCounts map[string]int  `json:"fieldCounts"` // Changed icons per field
+}
+
+// loadPreviousSVGIcons reads the icons of the existing SVG icons JSON output, nil if there is
+// none. The fields added by the stem step are dropped, so only generated fields are compared.
+func loadPreviousSVGIcons(opts svgOptions) ([]SVGIconData, error) {
+	filePath := filepath.Join(outputDir, opts.svgJSONFile())
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var r io.Reader = bytes.NewReader(content)
+	if strings.HasSuffix(filePath, ".gz") {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	icons := []SVGIconData{}
+	if err := json.NewDecoder(r).Decode(&icons); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return icons, nil
+}
+
+// iconFields returns the JSON encoding of each field of an icon, keyed by JSON name
+func iconFields(icon SVGIconData) (map[string]string, error) {
+	data, err := json.Marshal(icon)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string, len(raw))
+	for name, value := range raw {
+		fields[name] = string(value)
+	}
+	return fields, nil
+}
+
+// diffSVGIcons compares two sets of icons by ID, whatever their order. Icons sharing an ID
+// are compared field by field, a field missing on one side counting as changed.
+func diffSVGIcons(previous, icons []SVGIconData) (*svgDiff, error) {
+	diff := &svgDiff{Previous: previous != nil, Added: []string{}, Removed: []string{}, Changed: []svgIconChange{}, FieldCounts: map[string]int{}}
+
+	byID := make(map[string]SVGIconData, len(previous))
+	for _, icon := range previous {
+		byID[icon.ID] = icon
+	}
+
+	current := make(map[string]bool, len(icons))
+	for _, icon := range icons {
+		current[icon.ID] = true
+		old, ok := byID[icon.ID]
+		if !ok {
+			diff.Added = append(diff.Added, icon.ID)
+			continue
+		}
+
+		oldFields, err := iconFields(old)
+		if err != nil {
+			return nil, err
+		}
+		newFields, err := iconFields(icon)
+		if err != nil {
+			return nil, err
+		}
+		var changed []string
+		for name, value := range newFields {
+			if oldFields[name] != value {
+				changed = append(changed, name)
+			}
+		}
+		for name := range oldFields {
+			if _, ok := newFields[name]; !ok {
+				changed = append(changed, name)
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		sort.Strings(changed)
+		diff.Changed = append(diff.Changed, svgIconChange{ID: icon.ID, Fields: changed})
+		for _, name := range changed {
+			diff.FieldCounts[name]++
+		}
+	}
+
+	for _, icon := range previous {
+		if !current[icon.ID] {
+			diff.Removed = append(diff.Removed, icon.ID)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].ID < diff.Changed[j].ID })
+	return diff, nil
+}
+
+// printSVGDiff prints the counts of added, removed and changed icons with the changed fields,
+// listing the first IDs of each
+func printSVGDiff(diff *svgDiff, opts svgOptions) {
+	if !diff.Previous {
+		slog.Info(fmt.Sprintf("\n📝 No previous %s to compare with, all %d icons are new", opts.svgJSONFile(), len(diff.Added)), "added", len(diff.Added))
+		return
+	}
+
+	slog.Info(fmt.Sprintf("\n📝 Changes against the previous %s: %d added, %d removed, %d changed", opts.svgJSONFile(), len(diff.Added), len(diff.Removed), len(diff.Changed)), "added", len(diff.Added), "removed", len(diff.Removed), "changed", len(diff.Changed))
+
+	fields := make([]string, 0, len(diff.FieldCounts))
+	for name := range diff.FieldCounts {
+		fields = append(fields, name)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if diff.FieldCounts[fields[i]] != diff.FieldCounts[fields[j]] {
+			return diff.FieldCounts[fields[i]] > diff.FieldCounts[fields[j]]
+		}
+		return fields[i] < fields[j]
+	})
+	for _, name := range fields {
+		slog.Info(fmt.Sprintf("   • %s changed on %d icons", name, diff.FieldCounts[name]), "field", name, "icons", diff.FieldCounts[name])
+	}
+
+	printSVGDiffIDs("Added", diff.Added)
+	printSVGDiffIDs("Removed", diff.Removed)
+	changed := make([]string, len(diff.Changed))
+	for i, change := range diff.Changed {
+		changed[i] = fmt.Sprintf("%s (%s)", change.ID, strings.Join(change.Fields, ", "))
+	}
+	printSVGDiffIDs("Changed", changed)
+}
+
+// printSVGDiffIDs lists up to svgDiffListed entries under a heading
+func printSVGDiffIDs(heading string, entries []string) {
+	if len(entries) == 0 {
+		return
+	}
+	slog.Info(fmt.Sprintf("   %s:", heading))
+	for i, entry := range entries {
+		if i >= svgDiffListed {
+			slog.Info(fmt.Sprintf("     ... and %d more", len(entries)-svgDiffListed))
+			break
+		}
+		slog.Info(fmt.Sprintf("     - %s", entry))
+	}
+}
+
+// reportSVGDiff compares the icons with the previous output for --report-diff, before it is
+// overwritten, printing the summary and writing changes.json with --report-diff-json
+func reportSVGDiff(icons []SVGIconData, opts svgOptions) error {
+	previous, err := loadPreviousSVGIcons(opts)
+	if err != nil {
+		return err
+	}
+	diff, err := diffSVGIcons(previous, icons)
+	if err != nil {
+		return err
+	}
+	printSVGDiff(diff, opts)
+
+	if opts.ReportDiffJSON && !opts.DryRun {
+		if err := saveToJSON(svgChangesFile, diff); err != nil {
+			return fmt.Errorf("failed to save %s: %w", svgChangesFile, err)
+		}
+		slog.Info(fmt.Sprintf("💾 Changes saved to %s", filepath.Join(outputDir, svgChangesFile)))
+	}
+	return nil
+}
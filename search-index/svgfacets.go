@@ -0,0 +1,78 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// svgFacetsFile holds the icon counts the frontend filter chips show, facet name to value to count
+const svgFacetsFile = "facets.json"
+
+// svgFacetTop is the most values kept for the colors and tags facets, the most used ones
+const svgFacetTop = 50
+
+// svgFacets counts the icons per category, collection, color and tag. The category of an
+// icon is its cluster's source folder, the /svg_icons/{cluster}/ page it is listed on; the
+// Category field itself is svg_icons for every icon. Icons without a collection, colors or
+// tags are not counted in those facets.
+func svgFacets(icons []SVGIconData) map[string]map[string]int {
+	categories := map[string]int{}
+	collections := map[string]int{}
+	colors := map[string]int{}
+	tags := map[string]int{}
+	for _, icon := range icons {
+		if category := iconCategory(icon); category != "" {
+			categories[category]++
+		}
+		if icon.Collection != "" {
+			collections[icon.Collection]++
+		}
+		for _, color := range icon.Colors {
+			colors[color]++
+		}
+		for _, tag := range icon.Tags {
+			tags[tag]++
+		}
+	}
+
+	return map[string]map[string]int{
+		"category":   categories,
+		"collection": collections,
+		"colors":     topFacetValues(colors, svgFacetTop),
+		"tags":       topFacetValues(tags, svgFacetTop),
+	}
+}
+
+// iconCategory returns the source folder of an icon from its image, e.g. feather for
+// /svg_icons/feather/home.svg
+func iconCategory(icon SVGIconData) string {
+	relPath := strings.TrimPrefix(icon.Image, "/svg_icons/")
+	i := strings.Index(relPath, "/")
+	if relPath == icon.Image || i < 0 {
+		return ""
+	}
+	return relPath[:i]
+}
+
+// topFacetValues keeps the n values with the highest counts, ties broken alphabetically
+func topFacetValues(counts map[string]int, n int) map[string]int {
+	if len(counts) <= n {
+		return counts
+	}
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+
+	top := make(map[string]int, n)
+	for _, value := range values[:n] {
+		top[value] = counts[value]
+	}
+	return top
+}
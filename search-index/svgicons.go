@@ -2,168 +2,445 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"regexp"
-	jargon_stemmer "search-index/jargon-stemmer"
-	"sort"
-	"strings"
+	"log/slog"
+	"path/filepath"
 	"time"
+
+	jargon_stemmer "search-index/jargon-stemmer"
+	"search-index/svgicons"
 )
 
-func generateSVGIconsData(ctx context.Context) ([]SVGIconData, error) {
-	fmt.Println("🎨 Generating SVG icons data...")
+// svgHashIDsFile maps the page path of every icon to its ID, written with --id-style hash
+// so the short IDs can be resolved to readable names
+const svgHashIDsFile = "svg_icons_ids.json"
+
+// svgHashIDs maps the page path of every icon to its ID
+func svgHashIDs(icons []SVGIconData) map[string]string {
+	ids := make(map[string]string, len(icons))
+	for _, icon := range icons {
+		ids[icon.Path] = icon.ID
+	}
+	return ids
+}
+
+// saveSVGIcons writes the icons in every output format selected with --format
+func saveSVGIcons(icons []SVGIconData, opts svgOptions) error {
+	// First, so a missing translations file fails the run before anything is written
+	if err := saveSVGTranslations(icons, opts); err != nil {
+		return err
+	}
+	if opts.hasFormat("json") {
+		var verify func(tmpPath string) error
+		if opts.VerifyOutput {
+			verify = svgIconsVerifier(icons, opts.Gzip)
+		}
+		if opts.Gzip {
+			if err := saveToJSONGzVerified(opts.svgJSONFile(), icons, opts.GzipLevel, verify); err != nil {
+				return err
+			}
+		} else if err := saveToJSONVerified(opts.svgJSONFile(), icons, verify); err != nil {
+			return err
+		}
+	}
+	if opts.hasFormat("ndjson") {
+		if err := saveToNDJSON("svg_icons.ndjson", icons); err != nil {
+			return err
+		}
+	}
+	if opts.hasFormat("algolia") {
+		if err := saveAlgoliaExport(icons); err != nil {
+			return err
+		}
+	}
+	if opts.hasFormat("csv") {
+		if err := saveCSVExport(icons); err != nil {
+			return err
+		}
+	}
+	if opts.hasFormat("opensearch") {
+		if err := saveOpenSearchExport(icons, opts.OpenSearchIndex); err != nil {
+			return err
+		}
+	}
+	if opts.hasFormat("meilisearch") {
+		if err := saveMeiliExport(icons); err != nil {
+			return err
+		}
+	}
+	if opts.hasFormat("sqlite") {
+		if err := saveSQLiteExport(icons); err != nil {
+			return err
+		}
+	}
+	if opts.IDStyle == svgicons.IDStyleHash {
+		if err := saveToJSON(svgHashIDsFile, svgHashIDs(icons)); err != nil {
+			return fmt.Errorf("failed to save hash IDs: %w", err)
+		}
+	}
+	if opts.Optimize {
+		if err := svgicons.SaveOptimized(icons, outputDir); err != nil {
+			return fmt.Errorf("failed to save optimized SVGs: %w", err)
+		}
+	}
+	if opts.EmitSchema {
+		if err := saveSVGIconsSchema(opts); err != nil {
+			return err
+		}
+	}
+	if opts.Sitemap {
+		if _, err := saveSVGSitemap(icons, opts.SitemapBaseURL); err != nil {
+			return fmt.Errorf("failed to save sitemap: %w", err)
+		}
+	}
+	return nil
+}
+
+// RunSVGIconsOnly generates, saves and stems the SVG icons and builds their search files.
+// Errors are returned for the caller to report, nothing exits the process.
+func RunSVGIconsOnly(ctx context.Context, start time.Time, opts svgOptions) error {
+	if opts.IndexOnly {
+		return runSVGIndexOnly(ctx, opts, start)
+	}
+	slog.Info("🎨 Generating SVG icons data only...")
+
+	// Remote sources are downloaded first, the input hash covers their SVG files too
+	sources, err := svgicons.OpenSources(ctx, opts.Options)
+	if err != nil {
+		return fmt.Errorf("Failed to open SVG sources: %w", err)
+	}
+	defer sources.Close()
 
-	// Path to cluster.json file
-	clusterPath := "../frontend/data/cluster_svg.json"
+	// Skip the run when nothing changed since the last one
+	var inputHash string
+	if !opts.DryRun {
+		inputHash, err = hashSVGInputs(opts)
+		if err != nil {
+			return fmt.Errorf("Failed to hash SVG icons inputs: %w", err)
+		}
+		manifest, err := loadSVGManifest()
+		if err != nil {
+			return fmt.Errorf("Failed to load manifest: %w", err)
+		}
+		if !opts.Force && manifest.upToDate(inputHash) {
+			slog.Info("✅ No changes since the last run, skipping regeneration (use --force to rebuild)")
+			return nil
+		}
+	}
 
-	content, err := ioutil.ReadFile(clusterPath)
+	generateOpts := opts.Options
+	generateOpts.Progress = newSVGProgress(opts).update
+	icons, report, err := svgicons.Generate(ctx, generateOpts)
+	if errors.Is(err, svgicons.ErrNoFolderMatch) {
+		// Nothing to do, and writing empty files would wipe the existing output
+		slog.Warn(fmt.Sprintf("⚠️  Warning: %v, nothing was written", err))
+		return nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to read cluster.json: %w", err)
+		return fmt.Errorf("SVG icons data generation failed: %w", err)
 	}
 
-	var cluster SVGCluster
-	if err := json.Unmarshal(content, &cluster); err != nil {
-		return nil, fmt.Errorf("failed to parse cluster.json: %w", err)
+	// Do not write anything once interrupted
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	var svgIconsData []SVGIconData
-	categoryCount := 0
-	iconCount := 0
+	if opts.DryRun {
+		return runSVGIconsDryRun(icons, report, opts, start)
+	}
 
-	fmt.Println("Processing categories:")
+	if err := checkSVGStrict(report, opts); err != nil {
+		return fmt.Errorf("%w, not writing output", err)
+	}
 
-	for _, clusterEntry := range cluster.Clusters {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
+	// Compare with the previous output before it is overwritten
+	parity, err := compareSVGIcons(icons, opts)
+	if err != nil {
+		return err
+	}
 
-		categoryCount++
+	// Save to JSON
+	if err := saveSVGIcons(icons, opts); err != nil {
+		return fmt.Errorf("Failed to save SVG icons data: %w", err)
+	}
 
-		// Process each icon in the cluster
-		for _, fileName := range clusterEntry.FileNames {
-			iconCount++
+	elapsed := time.Since(start)
+	slog.Info(fmt.Sprintf("\n🎉 SVG icons data generation completed in %v", elapsed), "category", "svg_icons", "elapsed", elapsed.String())
+	slog.Info(fmt.Sprintf("📊 Generated %d SVG icons", len(icons)), "category", "svg_icons", "iconCount", len(icons))
 
-			// Remove leading underscore if present and get the name without extension
-			iconName := strings.TrimPrefix(fileName.FileName, "_")
-			iconName = strings.TrimSuffix(iconName, ".svg")
+	// Show sample data
+	slog.Info("\n📝 Sample SVG icons:")
+	for i, icon := range icons {
+		if i >= 10 { // Show first 10
+			slog.Info(fmt.Sprintf("  ... and %d more icons", len(icons)-10))
+			break
+		}
+		slog.Info(fmt.Sprintf("  %d. %s (ID: %s)", i+1, icon.Name, icon.ID))
+		if icon.Description != "" {
+			slog.Info(fmt.Sprintf("     Description: %s", truncateString(icon.Description, 80)))
+		}
+		slog.Info(fmt.Sprintf("     Image: %s", icon.Image))
+		slog.Info(fmt.Sprintf("     Path: %s", icon.Path))
+		slog.Info("")
+	}
+
+	if opts.hasFormat("json") {
+		slog.Info(fmt.Sprintf("💾 Data saved to %s", filepath.Join(outputDir, opts.svgJSONFile())))
+	}
+	for _, lang := range opts.Langs {
+		slog.Info(fmt.Sprintf("💾 %s names saved to %s", lang, filepath.Join(outputDir, opts.svgLangJSONFile(lang))))
+	}
+	if opts.hasFormat("ndjson") {
+		slog.Info(fmt.Sprintf("💾 Data saved to %s", filepath.Join(outputDir, "svg_icons.ndjson")))
+	}
+	if opts.hasFormat("algolia") {
+		slog.Info(fmt.Sprintf("💾 Algolia records saved to %s", filepath.Join(outputDir, svgAlgoliaFile)))
+	}
+	if opts.hasFormat("csv") {
+		slog.Info(fmt.Sprintf("💾 CSV catalog saved to %s", filepath.Join(outputDir, svgCSVFile)))
+	}
+	if opts.hasFormat("opensearch") {
+		slog.Info(fmt.Sprintf("💾 OpenSearch bulk actions for index %s saved to %s", opts.OpenSearchIndex, filepath.Join(outputDir, svgOpenSearchFile)))
+	}
+	if opts.hasFormat("meilisearch") {
+		slog.Info(fmt.Sprintf("💾 Meilisearch documents and settings saved to %s and %s", filepath.Join(outputDir, svgMeiliFile), filepath.Join(outputDir, svgMeiliSettingsFile)))
+	}
+	if opts.hasFormat("sqlite") {
+		slog.Info(fmt.Sprintf("💾 SQLite database saved to %s", filepath.Join(outputDir, svgSQLiteFile)))
+	}
+	if opts.IDStyle == svgicons.IDStyleHash {
+		slog.Info(fmt.Sprintf("💾 Path to hash ID map saved to %s", filepath.Join(outputDir, svgHashIDsFile)))
+	}
+	if opts.Optimize {
+		slog.Info(fmt.Sprintf("💾 Optimized SVGs saved to %s", filepath.Join(outputDir, svgicons.OptimizedDir)))
+	}
+	if opts.EmitSchema {
+		slog.Info(fmt.Sprintf("💾 JSON Schema saved to %s", filepath.Join(outputDir, svgSchemaFile)))
+	}
+	if opts.Sitemap {
+		slog.Info(fmt.Sprintf("💾 Sitemap saved to %s", filepath.Join(outputDir, svgSitemapFile)))
+	}
+	
+	// Automatically run stem processing
+	slog.Info("\n🔍 Running stem processing...")
+	if opts.hasFormat("json") {
+		if err := jargon_stemmer.ProcessJSONFileContext(ctx, filepath.Join(outputDir, opts.svgJSONFile()), opts.GzipLevel, svgStemOptions(icons, opts)); err != nil {
+			return fmt.Errorf("Stem processing failed: %w", err)
+		}
+	}
+	if opts.hasFormat("ndjson") {
+		if err := jargon_stemmer.ProcessNDJSONFileWithOptions(filepath.Join(outputDir, "svg_icons.ndjson"), opts.stemOptions()); err != nil {
+			return fmt.Errorf("Stem processing failed: %w", err)
+		}
+	}
+	slog.Info("✅ Stem processing completed!")
+	if opts.VerifyOutput && opts.hasFormat("json") {
+		slog.Info(fmt.Sprintf("✅ Verified the %d icons of %s as written and after stemming", len(icons), opts.svgJSONFile()), "icons", len(icons))
+	}
 
-			// Format the display name to be more user-friendly
-			displayName := formatIconName(iconName)
+	// After the stem step, which rewrites svg_icons.json
+	if err := saveSVGOffsets(opts); err != nil {
+		return err
+	}
 
-			// Create the path (similar to Python logic)
-			iconPath := fmt.Sprintf("/freedevtools/svg_icons/%s/%s/", clusterEntry.SourceFolder, iconName)
+	// Build the inverted search index from the same stemmer
+	slog.Info("\n🗂️ Building search index...")
+	index := buildSVGSearchIndex(icons, opts)
+	if err := saveToJSON(svgIndexFile, index); err != nil {
+		return fmt.Errorf("Failed to save search index: %w", err)
+	}
+	slog.Info(fmt.Sprintf("💾 Indexed %d tokens to %s", len(index.Tokens), filepath.Join(outputDir, svgIndexFile)))
 
-			// Generate ID from path (similar to Python logic)
-			iconID := generateIconIDFromPath(iconPath)
+	if opts.ReportTokens {
+		tokens := buildTokenFrequencies(icons, index, opts)
+		printTokenFrequencies(tokens)
+		if err := saveToJSON(svgTokenFrequencyFile, tokens); err != nil {
+			return fmt.Errorf("Failed to save token frequencies: %w", err)
+		}
+		slog.Info(fmt.Sprintf("💾 Token frequencies saved to %s", filepath.Join(outputDir, svgTokenFrequencyFile)))
+	}
 
-			// Use description from fileName if available, otherwise create default
-			description := fileName.Description
-			if description == "" {
-				description = fmt.Sprintf("SVG icon for %s", displayName)
-			}
+	autocomplete := buildAutocomplete(icons, rankPopular)
+	if err := saveToJSON(svgAutocompleteFile, autocomplete); err != nil {
+		return fmt.Errorf("Failed to save autocomplete data: %w", err)
+	}
+	slog.Info(fmt.Sprintf("💾 Saved %d autocomplete prefixes to %s", len(autocomplete), filepath.Join(outputDir, svgAutocompleteFile)))
 
-			// Generate icon data
-			iconData := SVGIconData{
-				ID:          iconID,
-				Name:        displayName,
-				Description: description,
-				Path:        iconPath,
-				Image:   fmt.Sprintf("/svg_icons/%s/%s", clusterEntry.SourceFolder, fileName.FileName),
-				Category:    "svg_icons",
-			}
+	if opts.ReportSimilarNames {
+		pairs := findSimilarSVGNames(icons, opts.SimilarNamesDistance)
+		reportSimilarSVGNames(pairs)
+		if err := saveToJSON(svgSimilarNamesFile, pairs); err != nil {
+			return fmt.Errorf("Failed to save similar names: %w", err)
+		}
+		slog.Info(fmt.Sprintf("💾 Similar names saved to %s", filepath.Join(outputDir, svgSimilarNamesFile)))
+	}
 
-			svgIconsData = append(svgIconsData, iconData)
+	if opts.ReportVisualDupes {
+		groups := findSVGVisualDupes(icons)
+		reportSVGVisualDupes(groups)
+		if err := saveToJSON(svgVisualDupesFile, groups); err != nil {
+			return fmt.Errorf("Failed to save visual duplicates: %w", err)
 		}
+		slog.Info(fmt.Sprintf("💾 Visual duplicates saved to %s", filepath.Join(outputDir, svgVisualDupesFile)))
 	}
 
-	// Sort by ID
-	sort.Slice(svgIconsData, func(i, j int) bool {
-		return svgIconsData[i].ID < svgIconsData[j].ID
-	})
+	if err := saveToJSON(svgFacetsFile, svgFacets(icons)); err != nil {
+		return fmt.Errorf("Failed to save facets: %w", err)
+	}
+	slog.Info(fmt.Sprintf("💾 Facet counts saved to %s", filepath.Join(outputDir, svgFacetsFile)))
 
-	fmt.Printf("🎨 Processed %d categories with %d icons total\n", categoryCount, iconCount)
-	return svgIconsData, nil
-}
+	if err := saveToJSON(svgicons.StatsFile, report.Stats(time.Since(start))); err != nil {
+		return fmt.Errorf("Failed to save stats: %w", err)
+	}
+	slog.Info(fmt.Sprintf("💾 Stats saved to %s", filepath.Join(outputDir, svgicons.StatsFile)))
 
-func generateIconIDFromPath(path string) string {
-	// Remove the base path (similar to Python logic)
-	cleanPath := strings.Replace(path, "/freedevtools/svg_icons/", "", 1)
-	
-	// Remove trailing slash if present
-	cleanPath = strings.TrimSuffix(cleanPath, "/")
-	
-	// Replace remaining slashes with hyphens
-	cleanPath = strings.Replace(cleanPath, "/", "-", -1)
-	
-	// Replace any invalid characters with underscores
-	reg := regexp.MustCompile(`[^a-zA-Z0-9\-_]`)
-	cleanPath = reg.ReplaceAllString(cleanPath, "_")
-	
-	// Add prefix with hyphen and sanitize
-	return fmt.Sprintf("svg-icons-%s", sanitizeID(cleanPath))
+	// An incomplete run is not recorded, so the next one does not skip regenerating
+	if err := checkSVGOutput(report, parity, opts); err != nil {
+		return err
+	}
+
+	if err := saveSVGManifest(inputHash, opts.svgOutputFiles()); err != nil {
+		return fmt.Errorf("Failed to save manifest: %w", err)
+	}
+	return nil
 }
 
-func formatIconName(iconName string) string {
-	// Replace underscores and hyphens with spaces
-	name := strings.Replace(iconName, "_", " ", -1)
-	name = strings.Replace(name, "-", " ", -1)
+// runSVGIconsDryRun stems and indexes the icons in memory and prints a summary without writing files.
+// With --strict, recorded warnings are returned as an error.
+func runSVGIconsDryRun(icons []SVGIconData, report *svgicons.Report, opts svgOptions, start time.Time) error {
+	index := buildSVGSearchIndex(icons, opts)
+	autocomplete := buildAutocomplete(icons, rankPopular)
 
-	// Title case
-	words := strings.Fields(name)
-	for i, word := range words {
-		if len(word) > 0 {
-			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
-		}
+	report.PrintSummary()
+	slog.Info(fmt.Sprintf("   • Search index tokens: %d", len(index.Tokens)))
+	slog.Info(fmt.Sprintf("   • Autocomplete prefixes: %d", len(autocomplete)))
+	if opts.ReportSimilarNames {
+		reportSimilarSVGNames(findSimilarSVGNames(icons, opts.SimilarNamesDistance))
+	}
+	if opts.ReportTokens {
+		printTokenFrequencies(buildTokenFrequencies(icons, index, opts))
+	}
+	parity, err := compareSVGIcons(icons, opts)
+	if err != nil {
+		return err
 	}
+	slog.Info(fmt.Sprintf("\n🧪 Dry run completed in %v, no files were written", time.Since(start)), "category", "svg_icons", "iconCount", len(icons), "elapsed", time.Since(start).String())
 
-	return strings.Join(words, " ")
+	if err := checkSVGStrict(report, opts); err != nil {
+		return err
+	}
+	if err := checkSVGErrors(report, opts); err != nil {
+		return err
+	}
+	return checkSVGParity(parity)
 }
 
+// checkSVGStrict fails a --strict run with a summary of its warnings, except those of the
+// kinds allowed with --allow
+func checkSVGStrict(report *svgicons.Report, opts svgOptions) error {
+	if !opts.Strict {
+		return nil
+	}
+	failures := report.StrictFailures(opts.Allow)
+	if len(failures) == 0 {
+		return nil
+	}
+	svgicons.PrintStrictSummary(failures, len(report.Warnings)-len(failures))
+	return fmt.Errorf("%d warnings with --strict", len(failures))
+}
 
-func RunSVGIconsOnly(ctx context.Context, start time.Time) {
-	fmt.Println("🎨 Generating SVG icons data only...")
+// checkSVGErrors fails a --continue-on-error run that left out clusters or has icons that
+// failed, once everything else is written, with the report of every error
+func checkSVGErrors(report *svgicons.Report, opts svgOptions) error {
+	if !opts.ContinueOnError || len(report.Errors) == 0 {
+		return nil
+	}
+	report.PrintErrors()
+	return fmt.Errorf("%d errors with --continue-on-error, the output is incomplete", len(report.Errors))
+}
 
-	icons, err := generateSVGIconsData(ctx)
+// compareSVGIcons runs the --report-diff and --compare-python comparisons of the generated
+// icons, before the outputs they compare with are overwritten. The parity it returns fails
+// the run in checkSVGOutput, once everything is written.
+func compareSVGIcons(icons []SVGIconData, opts svgOptions) (*svgParity, error) {
+	if opts.ReportDiff {
+		if err := reportSVGDiff(icons, opts); err != nil {
+			return nil, fmt.Errorf("Failed to compare with the previous output: %w", err)
+		}
+	}
+	if opts.ComparePython == "" {
+		return nil, nil
+	}
+	parity, err := reportSVGParity(icons, opts)
 	if err != nil {
-		log.Fatalf("❌ SVG icons data generation failed: %v", err)
+		return nil, fmt.Errorf("Failed to compare with the Python output: %w", err)
 	}
+	return parity, nil
+}
 
-	// Save to JSON
-	if err := saveToJSON("svg_icons.json", icons); err != nil {
-		log.Fatalf("Failed to save SVG icons data: %v", err)
+// svgStemOptions returns the stem options of svg_icons.json, which with --verify-output
+// check that the stemmed file still holds the generated icons
+func svgStemOptions(icons []SVGIconData, opts svgOptions) *jargon_stemmer.Options {
+	stemOpts := opts.stemOptions()
+	if opts.VerifyOutput {
+		stemOpts.Verify = svgIconsVerifier(icons, opts.Gzip)
 	}
+	return stemOpts
+}
 
-	elapsed := time.Since(start)
-	fmt.Printf("\n🎉 SVG icons data generation completed in %v\n", elapsed)
-	fmt.Printf("📊 Generated %d SVG icons\n", len(icons))
+// saveSVGOffsets writes the --emit-offsets icon offsets of svg_icons.json, which must
+// already be stemmed since the stem step rewrites it
+func saveSVGOffsets(opts svgOptions) error {
+	if !opts.EmitOffsets {
+		return nil
+	}
+	offsets, err := buildSVGIconOffsets(opts.svgJSONFile())
+	if err != nil {
+		return fmt.Errorf("Failed to find the icon offsets: %w", err)
+	}
+	if err := saveToJSON(svgOffsetsFile, offsets); err != nil {
+		return fmt.Errorf("Failed to save icon offsets: %w", err)
+	}
+	slog.Info(fmt.Sprintf("💾 Offsets of %d icons saved to %s", len(offsets.Offsets), filepath.Join(outputDir, svgOffsetsFile)))
+	return nil
+}
 
-	// Show sample data
-	fmt.Println("\n📝 Sample SVG icons:")
-	for i, icon := range icons {
-		if i >= 10 { // Show first 10
-			fmt.Printf("  ... and %d more icons\n", len(icons)-10)
-			break
-		}
-		fmt.Printf("  %d. %s (ID: %s)\n", i+1, icon.Name, icon.ID)
-		if icon.Description != "" {
-			fmt.Printf("     Description: %s\n", truncateString(icon.Description, 80))
-		}
-		fmt.Printf("     Image: %s\n", icon.Image)
-		fmt.Printf("     Path: %s\n", icon.Path)
-		fmt.Println()
+// checkSVGOutput fails a run once its files are written and stemmed: with the errors left
+// out by --continue-on-error, then the mismatches of --compare-python and the
+// --max-output-bytes budget. Shared by the SVG-only and full runs.
+func checkSVGOutput(report *svgicons.Report, parity *svgParity, opts svgOptions) error {
+	if err := checkSVGErrors(report, opts); err != nil {
+		return err
 	}
+	if err := checkSVGParity(parity); err != nil {
+		return err
+	}
+	return checkSVGOutputBudget(opts)
+}
 
-	fmt.Printf("💾 Data saved to output/svg_icons.json\n")
-	
-	// Automatically run stem processing
-	fmt.Println("\n🔍 Running stem processing...")
-	if err := jargon_stemmer.ProcessJSONFile("output/svg_icons.json"); err != nil {
-		log.Fatalf("❌ Stem processing failed: %v", err)
+// runSVGValidateOnly checks the cluster files for --validate-only, listing every problem found,
+// without generating, stemming or writing anything
+func runSVGValidateOnly(ctx context.Context, opts svgOptions, start time.Time) error {
+	if err := svgicons.LoadIgnore(svgicons.IgnoreFile); err != nil {
+		return err
 	}
-	fmt.Println("✅ Stem processing completed!")
-}
\ No newline at end of file
+	sources, err := svgicons.OpenSources(ctx, opts.Options)
+	if err != nil {
+		return err
+	}
+	defer sources.Close()
+	result, err := opts.Validate()
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range result.Issues {
+		slog.Error(fmt.Sprintf("   • %s", issue), "file", issue.File, "line", issue.Line, "column", issue.Column)
+	}
+	if len(result.Issues) > 0 {
+		return fmt.Errorf("%d problems in %d cluster files", len(result.Issues), result.ClusterFiles)
+	}
+	slog.Info(fmt.Sprintf("✅ %d cluster files are valid: %d clusters listing %d SVG files, checked in %v", result.ClusterFiles, result.Clusters, result.Files, time.Since(start)), "clusterFiles", result.ClusterFiles, "clusters", result.Clusters, "files", result.Files)
+	return nil
+}
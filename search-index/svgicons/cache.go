@@ -0,0 +1,272 @@
+package svgicons
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// svgClusterCacheFile holds the icon data of each source folder from the last --incremental run
+const svgClusterCacheFile = ".svg_cluster_cache.json"
+
+// svgClusterCacheEntry is the processed icon data of one source folder
+type svgClusterCacheEntry struct {
+	Fingerprint string          `json:"fingerprint"`
+	Settings    string          `json:"settings,omitempty"` // svgResultSettings of the run that processed the results
+	Results     []svgIconResult `json:"results"`
+}
+
+// svgClusterCache maps source folders to their cached icon data
+type svgClusterCache map[string]svgClusterCacheEntry
+
+// processSVGIconJobsIncremental is processSVGIconJobs reusing the results of source folders
+// whose cluster files, SVG files and name casing are unchanged since the last run.
+// The results are the same as processing every job, up to order.
+// The cached results of keptFolders, the folders left out by --folder, are added as they are,
+// so rebuilding one collection still writes all of them.
+func processSVGIconJobsIncremental(ctx context.Context, jobs []svgIconJob, keptFolders []string, opts Options, metaCache *svgMetaCache) ([]svgIconResult, error) {
+	cache, err := loadSVGClusterCache(opts.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	nameCasingHash, err := hashFileIfExists(NameCasingFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group jobs by source folder, keeping the cluster file order
+	var folders []string
+	jobsByFolder := make(map[string][]svgIconJob)
+	for _, job := range jobs {
+		if _, ok := jobsByFolder[job.SourceFolder]; !ok {
+			folders = append(folders, job.SourceFolder)
+		}
+		jobsByFolder[job.SourceFolder] = append(jobsByFolder[job.SourceFolder], job)
+	}
+
+	var results []svgIconResult
+	var changedJobs []svgIconJob
+	fingerprints := make(map[string]string, len(folders))
+	for _, folder := range folders {
+		fingerprint, err := fingerprintSVGFolder(jobsByFolder[folder], nameCasingHash)
+		if err != nil {
+			return nil, err
+		}
+		fingerprints[folder] = fingerprint
+
+		if entry, ok := cache[folder]; ok && entry.Fingerprint == fingerprint {
+			slog.Debug(fmt.Sprintf("  • %s unchanged, reusing %d icons", folder, len(entry.Results)), "sourceFolder", folder, "iconCount", len(entry.Results))
+			results = append(results, entry.Results...)
+			continue
+		}
+		slog.Debug(fmt.Sprintf("  • %s changed, reprocessing %d icons", folder, len(jobsByFolder[folder])), "sourceFolder", folder, "iconCount", len(jobsByFolder[folder]))
+		changedJobs = append(changedJobs, jobsByFolder[folder]...)
+	}
+
+	for _, folder := range keptFolders {
+		if entry, ok := cache[folder]; ok {
+			slog.Debug(fmt.Sprintf("  • %s not selected by --folder, keeping %d cached icons", folder, len(entry.Results)), "sourceFolder", folder, "iconCount", len(entry.Results))
+			results = append(results, entry.Results...)
+			folders = append(folders, folder)
+			fingerprints[folder] = entry.Fingerprint
+		}
+	}
+
+	changedResults, err := processSVGIconJobs(ctx, changedJobs, opts, metaCache)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, changedResults...)
+	slog.Info(fmt.Sprintf("♻️  Reused %d of %d icons, reprocessed %d changed icons", len(results)-len(changedResults), len(jobs), len(changedResults)), "reused", len(results)-len(changedResults), "reprocessed", len(changedResults))
+
+	if !opts.DryRun && opts.Limit == 0 {
+		if err := saveSVGResultsCache(opts.CacheDir, folders, fingerprints, results, svgResultSettings(nameCasingHash)); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// processSVGIconJobsSince is processSVGIconJobs reusing the cached result of every SVG file
+// last modified at or before --since, when the cache has one for the same cluster entry
+// processed with the same settings. Newer files, missing files and files without a cached
+// result are processed. Like --incremental, the cached results of keptFolders are kept too.
+// It relies on modification times alone, so a file changed without a newer mtime is not seen.
+func processSVGIconJobsSince(ctx context.Context, jobs []svgIconJob, keptFolders []string, opts Options, metaCache *svgMetaCache) ([]svgIconResult, error) {
+	cache, err := loadSVGClusterCache(opts.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	nameCasingHash, err := hashFileIfExists(NameCasingFile)
+	if err != nil {
+		return nil, err
+	}
+	settings := svgResultSettings(nameCasingHash)
+
+	// Index the cached results by folder and cluster entry, skipping those of other settings
+	cached := make(map[string]svgIconResult)
+	for folder, entry := range cache {
+		if entry.Settings != settings {
+			continue
+		}
+		for _, result := range entry.Results {
+			key, err := sinceCacheKey(folder, result.FileName, iconAttribution(result.Icon))
+			if err != nil {
+				return nil, err
+			}
+			cached[key] = result
+		}
+	}
+
+	var folders []string
+	jobsByFolder := make(map[string][]svgIconJob)
+	var results []svgIconResult
+	var changedJobs []svgIconJob
+	for _, job := range jobs {
+		if _, ok := jobsByFolder[job.SourceFolder]; !ok {
+			folders = append(folders, job.SourceFolder)
+		}
+		jobsByFolder[job.SourceFolder] = append(jobsByFolder[job.SourceFolder], job)
+
+		key, err := sinceCacheKey(job.SourceFolder, job.FileName, job.Attribution)
+		if err != nil {
+			return nil, err
+		}
+		result, ok := cached[key]
+		if ok && !job.Missing {
+			info, err := os.Stat(FilePath(job.SourceFolder, job.FileName.FileName))
+			if err == nil && !info.ModTime().After(opts.Since) {
+				// The file may have moved within its cluster entry
+				result.ClusterKey, result.Position = job.ClusterKey, job.Position
+				results = append(results, result)
+				continue
+			}
+		}
+		changedJobs = append(changedJobs, job)
+	}
+
+	fingerprints := make(map[string]string, len(folders))
+	for _, folder := range folders {
+		fingerprint, err := fingerprintSVGFolder(jobsByFolder[folder], nameCasingHash)
+		if err != nil {
+			return nil, err
+		}
+		fingerprints[folder] = fingerprint
+	}
+	for _, folder := range keptFolders {
+		if entry, ok := cache[folder]; ok {
+			results = append(results, entry.Results...)
+			folders = append(folders, folder)
+			fingerprints[folder] = entry.Fingerprint
+		}
+	}
+
+	changedResults, err := processSVGIconJobs(ctx, changedJobs, opts, metaCache)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, changedResults...)
+	slog.Info(fmt.Sprintf("♻️  Reused %d of %d icons not modified since %s, reprocessed %d icons", len(results)-len(changedResults), len(jobs), opts.Since.Format(time.RFC3339), len(changedResults)), "reused", len(results)-len(changedResults), "reprocessed", len(changedResults))
+
+	if !opts.DryRun && opts.Limit == 0 {
+		if err := saveSVGResultsCache(opts.CacheDir, folders, fingerprints, results, settings); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// sinceCacheKey identifies the cached result of a cluster entry in a source folder
+// credited with the given attribution
+func sinceCacheKey(folder string, fileName FileName, attribution svgAttribution) (string, error) {
+	entry, err := json.Marshal(fileName)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s\x00%s\x00%q", folder, entry, attribution), nil
+}
+
+// svgResultSettings identifies what besides the cluster entry and SVG file shapes a result,
+// so results processed by another version or with other name casings are not reused
+func svgResultSettings(nameCasingHash string) string {
+	return fmt.Sprintf("version %d name casing %s", DataVersion, nameCasingHash)
+}
+
+// saveSVGResultsCache writes the results of the given folders to the cluster cache in dir
+func saveSVGResultsCache(dir string, folders []string, fingerprints map[string]string, results []svgIconResult, settings string) error {
+	newCache := make(svgClusterCache, len(folders))
+	for _, folder := range folders {
+		newCache[folder] = svgClusterCacheEntry{Fingerprint: fingerprints[folder], Settings: settings}
+	}
+	for _, result := range results {
+		folder := result.SourceFolder
+		entry := newCache[folder]
+		entry.Results = append(entry.Results, result)
+		newCache[folder] = entry
+	}
+	return saveSVGClusterCache(dir, newCache)
+}
+
+// fingerprintSVGFolder hashes the cluster files of a source folder, with their position in
+// the cluster file and the size and modification time of their SVG files, so unchanged
+// folders are found without reading them
+func fingerprintSVGFolder(jobs []svgIconJob, nameCasingHash string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "version %d name casing %s\n", DataVersion, nameCasingHash)
+	for _, job := range jobs {
+		fileName, err := json.Marshal(job.FileName)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %d %s %q\n", job.ClusterKey, job.Position, fileName, job.Attribution)
+		if job.System {
+			fmt.Fprintf(h, "system\n")
+		}
+
+		info, err := os.Stat(FilePath(job.SourceFolder, job.FileName.FileName))
+		if err != nil {
+			fmt.Fprintf(h, "missing\n")
+			continue
+		}
+		fmt.Fprintf(h, "%d %d\n", info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func loadSVGClusterCache(dir string) (svgClusterCache, error) {
+	cachePath := filepath.Join(dir, svgClusterCacheFile)
+	content, err := ioutil.ReadFile(cachePath)
+	if os.IsNotExist(err) {
+		return svgClusterCache{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", cachePath, err)
+	}
+
+	var cache svgClusterCache
+	if err := json.Unmarshal(content, &cache); err != nil {
+		// A broken cache only costs a full rebuild
+		slog.Warn(fmt.Sprintf("⚠️  Warning: Ignoring unreadable %s: %v", cachePath, err))
+		return svgClusterCache{}, nil
+	}
+	return cache, nil
+}
+
+func saveSVGClusterCache(dir string, cache svgClusterCache) error {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	return writeCacheFile(dir, svgClusterCacheFile, data)
+}
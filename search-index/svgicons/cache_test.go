@@ -0,0 +1,128 @@
+package svgicons
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func incrementalTestClusters(homeDescription string) map[string]ClusterEntry {
+	return map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", FileNames: []FileName{
+			{FileName: "home.svg", Description: homeDescription},
+			{FileName: "arrow-up.svg", Description: "Points up"},
+		}},
+		"material": {SourceFolder: "material", FileNames: testFiles("cog.svg", "bell.svg")},
+	}
+}
+
+// mustJSON encodes icons for comparing generated outputs
+func mustJSON(t *testing.T, icons []Icon) string {
+	t.Helper()
+	data, err := json.MarshalIndent(icons, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestIncrementalMatchesFullRebuild(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(incrementalTestClusters("A house"))
+	tt.generate(Options{Incremental: true})
+
+	// Change one cluster entry and one SVG of the other folder
+	tt.writeClusters(incrementalTestClusters("The home page"))
+	tt.writeSVG("material", "bell.svg", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 16 16"><title>Bell</title><circle cx="8" cy="8" r="4"/></svg>`)
+
+	incremental, _ := tt.generate(Options{Incremental: true})
+	full, _ := tt.generate(Options{NoCache: true})
+	if got, want := mustJSON(t, incremental), mustJSON(t, full); got != want {
+		t.Errorf("incremental output differs from a full rebuild:\n%s\nwant:\n%s", got, want)
+	}
+	if home := iconByImage(t, incremental, "/svg_icons/feather/home.svg"); home.Description != "The home page" {
+		t.Errorf("home description = %q, want the changed one", home.Description)
+	}
+	if bell := iconByImage(t, incremental, "/svg_icons/material/bell.svg"); bell.ViewBox != "0 0 16 16" {
+		t.Errorf("bell viewBox = %q, want the one of the changed SVG", bell.ViewBox)
+	}
+}
+
+func TestIncrementalReusesUnchangedFolders(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(incrementalTestClusters("A house"))
+	tt.generate(Options{Incremental: true})
+
+	// Mark the cached results, so the next run shows which folders it reused
+	cache, err := loadSVGClusterCache("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for folder, entry := range cache {
+		for i := range entry.Results {
+			entry.Results[i].Icon.Description = "cached"
+		}
+		cache[folder] = entry
+	}
+	if err := saveSVGClusterCache("", cache); err != nil {
+		t.Fatal(err)
+	}
+
+	tt.writeClusters(incrementalTestClusters("The home page"))
+	icons, _ := tt.generate(Options{Incremental: true})
+	for _, icon := range icons {
+		cached := icon.Description == "cached"
+		if changed := strings.HasPrefix(icon.Image, "/svg_icons/feather/"); cached == changed {
+			t.Errorf("%s has description %q, want feather reprocessed and material reused", icon.Image, icon.Description)
+		}
+	}
+}
+
+func TestLoadSVGClusterCacheUnreadable(t *testing.T) {
+	newTestTree(t)
+	cache, err := loadSVGClusterCache("")
+	if err != nil || len(cache) != 0 {
+		t.Errorf("loadSVGClusterCache without a file = %v, %v; want an empty cache", cache, err)
+	}
+	if err := ioutil.WriteFile(svgClusterCacheFile, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A broken cache only costs a full rebuild
+	if cache, err := loadSVGClusterCache(""); err != nil || len(cache) != 0 {
+		t.Errorf("loadSVGClusterCache of a malformed file = %v, %v; want an empty cache", cache, err)
+	}
+}
+
+func TestSinceReusesOlderFiles(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(incrementalTestClusters("A house"))
+	old := time.Now().Add(-48 * time.Hour)
+	for _, file := range []string{IconsDirPath("feather", "home.svg"), IconsDirPath("feather", "arrow-up.svg"), IconsDirPath("material", "cog.svg"), IconsDirPath("material", "bell.svg")} {
+		if err := os.Chtimes(file, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	since := time.Now().Add(-24 * time.Hour)
+	tt.generate(Options{Since: since, ModifiedFrom: ModifiedFromNone})
+
+	// An old file changed without a newer mtime keeps its cached result, a new one is reindexed
+	tt.writeSVG("feather", "home.svg", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 32 32"><path d="M1 1h2"/></svg>`)
+	if err := os.Chtimes(IconsDirPath("feather", "home.svg"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	tt.writeSVG("material", "cog.svg", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 48 48"><path d="M1 1h2"/></svg>`)
+
+	icons, _ := tt.generate(Options{Since: since, ModifiedFrom: ModifiedFromNone})
+	if home := iconByImage(t, icons, "/svg_icons/feather/home.svg"); home.ViewBox != "0 0 24 24" {
+		t.Errorf("home viewBox = %q, want the cached 0 0 24 24", home.ViewBox)
+	}
+	if cog := iconByImage(t, icons, "/svg_icons/material/cog.svg"); cog.ViewBox != "0 0 48 48" {
+		t.Errorf("cog viewBox = %q, want the reindexed 0 0 48 48", cog.ViewBox)
+	}
+	if len(icons) != 4 {
+		t.Errorf("got %d icons, want 4", len(icons))
+	}
+}
@@ -0,0 +1,238 @@
+package svgicons
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ClusterPatterns returns the cluster files or globs to read, from ClusterPaths (--cluster),
+// then the CLUSTER_SVG_PATH environment variable, then the default location, along with their source
+func (o Options) ClusterPatterns() ([]string, string) {
+	if len(o.ClusterPaths) > 0 {
+		return o.ClusterPaths, "--cluster"
+	}
+	if env := SplitList(os.Getenv("CLUSTER_SVG_PATH")); len(env) > 0 {
+		return env, "CLUSTER_SVG_PATH"
+	}
+	return []string{DefaultClusterPath}, "default path"
+}
+
+// ResolveClusterPaths returns the absolute paths of the cluster files, in the order given with
+// the matches of each glob sorted. A file listed twice is read once.
+func (o Options) ResolveClusterPaths() ([]string, error) {
+	patterns, source := o.ClusterPatterns()
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		absPattern, err := filepath.Abs(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve cluster path %s: %w", pattern, err)
+		}
+
+		matches := []string{absPattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			matches, err = filepath.Glob(absPattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cluster glob %s: %w", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("cluster glob %s (from %s) matches no file: set --cluster or CLUSTER_SVG_PATH", pattern, source)
+			}
+			sort.Strings(matches)
+		} else if _, err := os.Stat(absPattern); err != nil {
+			return nil, fmt.Errorf("cluster file %s (from %s) not found: set --cluster or CLUSTER_SVG_PATH", absPattern, source)
+		}
+
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				paths = append(paths, match)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// DuplicateFolder is a source folder used by clusters of more than one cluster file
+type DuplicateFolder struct {
+	SourceFolder string
+	Files        []string
+}
+
+// LoadClusters reads and validates the cluster files and merges their clusters into one
+// Cluster, the same as if they had been written as a single file. A cluster key defined by
+// two files is an error, since one would silently replace the other. Source folders shared by
+// clusters of different files are returned, sorted by folder, for the caller to report.
+// Files that are temporarily unavailable are retried, see readClusterFile. With
+// ContinueOnError, the files and clusters failing are left out instead, see loadClusters.
+func (o Options) LoadClusters(paths []string) (Cluster, []DuplicateFolder, error) {
+	merged, duplicates, _, err := o.loadClusters(paths)
+	return merged, duplicates, err
+}
+
+// loadClusters is LoadClusters also returning what it left out with ContinueOnError: cluster
+// files that cannot be read or parsed, clusters failing validation and cluster keys already
+// defined by an earlier file. It still fails if no cluster is left.
+func (o Options) loadClusters(paths []string) (Cluster, []DuplicateFolder, []GenerateError, error) {
+	merged := Cluster{Clusters: make(map[string]ClusterEntry)}
+	keyFiles := make(map[string]string)
+	folderFiles := make(map[string][]string)
+	var failures []GenerateError
+
+	for _, clusterPath := range paths {
+		cluster, _, err := o.readClusterFile(clusterPath)
+		if err != nil {
+			if !o.ContinueOnError {
+				return Cluster{}, nil, nil, err
+			}
+			var skipped []GenerateError
+			cluster, skipped = skipInvalidClusters(clusterPath, cluster, err)
+			failures = append(failures, skipped...)
+		}
+
+		// Walk the keys in order so the first file to share a folder does not depend on map order
+		keys := make([]string, 0, len(cluster.Clusters))
+		for key := range cluster.Clusters {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if other, ok := keyFiles[key]; ok {
+				if !o.ContinueOnError {
+					return Cluster{}, nil, nil, fmt.Errorf("cluster %q is defined in both %s and %s", key, other, clusterPath)
+				}
+				failures = append(failures, GenerateError{Phase: PhaseCluster, Cluster: key, File: clusterPath, Message: fmt.Sprintf("cluster %q is already defined in %s, left out", key, other)})
+				continue
+			}
+			keyFiles[key] = clusterPath
+
+			entry := cluster.Clusters[key]
+			merged.Clusters[key] = entry
+			if files := folderFiles[entry.SourceFolder]; !ContainsString(files, clusterPath) {
+				folderFiles[entry.SourceFolder] = append(files, clusterPath)
+			}
+		}
+	}
+
+	var duplicates []DuplicateFolder
+	for folder, files := range folderFiles {
+		if len(files) > 1 {
+			duplicates = append(duplicates, DuplicateFolder{SourceFolder: folder, Files: files})
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].SourceFolder < duplicates[j].SourceFolder
+	})
+	if len(merged.Clusters) == 0 && len(failures) > 0 {
+		return Cluster{}, nil, failures, fmt.Errorf("no valid cluster left in %d cluster files, first problem: %s", len(paths), failures[0])
+	}
+	return merged, duplicates, failures, nil
+}
+
+// skipInvalidClusters returns the clusters of a cluster file that failed to load which can
+// still be used, along with what was left out: the invalid clusters, or the whole file when
+// it cannot be read or is not valid JSON
+func skipInvalidClusters(clusterPath string, cluster Cluster, err error) (Cluster, []GenerateError) {
+	var clusterErr *svgClusterError
+	if !errors.As(err, &clusterErr) {
+		return Cluster{}, []GenerateError{{Phase: PhaseCluster, File: clusterPath, Message: fmt.Sprintf("%v, file left out", err)}}
+	}
+
+	var failures []GenerateError
+	kept := Cluster{Clusters: make(map[string]ClusterEntry, len(cluster.Clusters))}
+	for key, entry := range cluster.Clusters {
+		kept.Clusters[key] = entry
+	}
+	for _, issue := range clusterErr.Issues {
+		message := issue.Message
+		switch {
+		case cluster.Clusters == nil:
+			message += ", file left out"
+		case issue.Cluster != "":
+			message += ", cluster left out"
+			delete(kept.Clusters, issue.Cluster)
+		}
+		failures = append(failures, GenerateError{Phase: PhaseCluster, Cluster: issue.Cluster, File: fmt.Sprintf("%s:%d:%d", issue.File, issue.Line, issue.Column), Message: message})
+	}
+	if cluster.Clusters == nil {
+		return Cluster{}, failures
+	}
+	return kept, failures
+}
+
+// readClusterFile reads and parses a cluster file, retrying up to ClusterReadAttempts times
+// with a backoff doubling from ClusterReadBackoff while the file is temporarily unavailable:
+// a transient I/O error, or content cut short because another step is still writing it.
+// A missing file and any other error fail right away. The content read is returned too.
+func (o Options) readClusterFile(clusterPath string) (Cluster, []byte, error) {
+	attempts := o.ClusterReadAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := o.ClusterReadBackoff
+
+	for attempt := 1; ; attempt++ {
+		content, err := ioutil.ReadFile(clusterPath)
+		if os.IsNotExist(err) {
+			return Cluster{}, nil, fmt.Errorf("cluster file %s not found: %w", clusterPath, err)
+		}
+
+		var cluster Cluster
+		if err == nil {
+			cluster, err = parseSVGClusterFile(clusterPath, content)
+			if err == nil {
+				return cluster, content, nil
+			}
+			if !isPartialClusterFile(content) {
+				return cluster, content, err
+			}
+		} else if !isTransientReadError(err) {
+			return Cluster{}, nil, fmt.Errorf("failed to read cluster file %s: %w", clusterPath, err)
+		}
+
+		if attempt == attempts {
+			if attempts == 1 {
+				return Cluster{}, nil, fmt.Errorf("cluster file %s is temporarily unavailable: %w", clusterPath, err)
+			}
+			return Cluster{}, nil, fmt.Errorf("cluster file %s still unavailable after %d attempts: %w", clusterPath, attempts, err)
+		}
+		slog.Warn(fmt.Sprintf("⏳ Cluster file %s is unavailable (%v), retrying in %v (attempt %d of %d)", clusterPath, err, backoff, attempt, attempts), "file", clusterPath, "attempt", attempt)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isTransientReadError reports whether reading a file failed for a reason that may go away
+// on its own, such as the file being locked or busy
+func isTransientReadError(err error) bool {
+	for _, errno := range []syscall.Errno{syscall.EAGAIN, syscall.EBUSY, syscall.EINTR, syscall.ETXTBSY} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPartialClusterFile reports whether content looks like a cluster file still being written:
+// empty, or JSON that ends before its last value is closed
+func isPartialClusterFile(content []byte) bool {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return true
+	}
+	var value interface{}
+	err := json.Unmarshal(content, &value)
+	var syntaxErr *json.SyntaxError
+	return errors.As(err, &syntaxErr) && syntaxErr.Offset >= int64(len(content))
+}
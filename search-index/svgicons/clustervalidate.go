@@ -0,0 +1,211 @@
+package svgicons
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ClusterIssue is a problem found in a cluster file, at a 1-based line and column
+type ClusterIssue struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+	Cluster string // Key of the invalid cluster, empty for a problem with the file as a whole
+}
+
+func (i ClusterIssue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+// svgClusterError lists every issue found in a cluster file, so all of them can be fixed at once
+type svgClusterError struct {
+	File   string
+	Issues []ClusterIssue
+}
+
+func (e *svgClusterError) Error() string {
+	if len(e.Issues) == 1 {
+		return fmt.Sprintf("invalid cluster file %s", e.Issues[0])
+	}
+	lines := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		lines[i] = "  " + issue.String()
+	}
+	return fmt.Sprintf("invalid cluster file %s, %d problems:\n%s", e.File, len(e.Issues), strings.Join(lines, "\n"))
+}
+
+// parseSVGClusterFile parses and validates the content of a cluster file. JSON syntax and
+// type errors are reported at their line and column, a missing or empty "clusters" object
+// at the start of the file; otherwise every cluster missing its source_folder, listing no
+// fileNames, listing an empty fileName or with an invalid id_prefix is reported at the line of its key. Any issue is returned as an *svgClusterError, along with the clusters parsed
+// when the content is valid JSON, for ContinueOnError to keep the valid ones.
+func parseSVGClusterFile(file string, content []byte) (Cluster, error) {
+	issueAt := func(offset int64, format string, args ...interface{}) ClusterIssue {
+		line, column := lineColumn(content, offset)
+		return ClusterIssue{File: file, Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+	}
+	clusterIssueAt := func(key string, offset int64, format string, args ...interface{}) ClusterIssue {
+		issue := issueAt(offset, format, args...)
+		issue.Cluster = key
+		return issue
+	}
+
+	var cluster Cluster
+	if err := json.Unmarshal(content, &cluster); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return Cluster{}, &svgClusterError{File: file, Issues: []ClusterIssue{issueAt(syntaxErr.Offset-1, "%v", syntaxErr)}}
+		case errors.As(err, &typeErr):
+			return Cluster{}, &svgClusterError{File: file, Issues: []ClusterIssue{issueAt(typeErr.Offset, "%s must be %s, got %s", typeErr.Field, jsonTypeName(typeErr.Type.Kind().String()), typeErr.Value)}}
+		}
+		return Cluster{}, fmt.Errorf("failed to parse cluster file %s: %w", file, err)
+	}
+
+	offsets, duplicateKeys := clusterKeyOffsets(content)
+	var issues []ClusterIssue
+	if cluster.Clusters == nil {
+		issues = append(issues, issueAt(0, `missing "clusters" object`))
+	} else if len(cluster.Clusters) == 0 {
+		issues = append(issues, issueAt(0, `empty "clusters" object, the file lists no icons`))
+	}
+	for _, duplicate := range duplicateKeys {
+		issues = append(issues, issueAt(duplicate.offset, "cluster %q is defined more than once, only the last one is used", duplicate.key))
+	}
+
+	keys := make([]string, 0, len(cluster.Clusters))
+	for key := range cluster.Clusters {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return offsets[keys[i]] < offsets[keys[j]] })
+
+	for _, key := range keys {
+		entry := cluster.Clusters[key]
+		offset := offsets[key]
+		if strings.TrimSpace(entry.SourceFolder) == "" {
+			issues = append(issues, clusterIssueAt(key, offset, "cluster %q has no source_folder", key))
+		}
+		if len(entry.FileNames) == 0 {
+			issues = append(issues, clusterIssueAt(key, offset, "cluster %q lists no fileNames", key))
+		}
+		if entry.IDPrefix != "" && !validIDPrefix(entry.IDPrefix) {
+			issues = append(issues, clusterIssueAt(key, offset, "cluster %q has an invalid id_prefix %q, expected lowercase letters, digits and single hyphens such as fa", key, entry.IDPrefix))
+		}
+		for i, fileName := range entry.FileNames {
+			if strings.TrimSpace(fileName.FileName) == "" {
+				issues = append(issues, clusterIssueAt(key, offset, "cluster %q has an empty fileName at fileNames[%d]", key, i))
+			}
+		}
+	}
+
+	if len(issues) > 0 {
+		sort.SliceStable(issues, func(i, j int) bool {
+			if issues[i].Line != issues[j].Line {
+				return issues[i].Line < issues[j].Line
+			}
+			return issues[i].Column < issues[j].Column
+		})
+		return cluster, &svgClusterError{File: file, Issues: issues}
+	}
+	return cluster, nil
+}
+
+// clusterKey is a key of the "clusters" object and the byte offset of its opening quote
+type clusterKey struct {
+	key    string
+	offset int64
+}
+
+// clusterKeyOffsets returns the byte offset of each key of the "clusters" object, the last
+// one for a key defined twice, along with the repeated definitions. The content must already
+// be known to be valid JSON.
+func clusterKeyOffsets(content []byte) (map[string]int64, []clusterKey) {
+	offsets := make(map[string]int64)
+	var duplicates []clusterKey
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	// keyOffset skips the whitespace and separators the decoder leaves before a key
+	keyOffset := func(from int64) int64 {
+		for from < int64(len(content)) && bytes.IndexByte([]byte(" \t\r\n,{"), content[from]) >= 0 {
+			from++
+		}
+		return from
+	}
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return offsets, nil
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return offsets, duplicates
+		}
+		if token != "clusters" {
+			var skip json.RawMessage
+			if decoder.Decode(&skip) != nil {
+				return offsets, duplicates
+			}
+			continue
+		}
+
+		if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+			return offsets, duplicates
+		}
+		for decoder.More() {
+			start := keyOffset(decoder.InputOffset())
+			token, err := decoder.Token()
+			if err != nil {
+				return offsets, duplicates
+			}
+			key, _ := token.(string)
+			if _, ok := offsets[key]; ok {
+				duplicates = append(duplicates, clusterKey{key: key, offset: offsets[key]})
+			}
+			offsets[key] = start
+
+			var skip json.RawMessage
+			if decoder.Decode(&skip) != nil {
+				return offsets, duplicates
+			}
+		}
+		return offsets, duplicates
+	}
+	return offsets, duplicates
+}
+
+// lineColumn converts a byte offset of content to a 1-based line and column,
+// the column counting characters rather than bytes
+func lineColumn(content []byte, offset int64) (int, int) {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return line, utf8.RuneCount(before[lineStart:]) + 1
+}
+
+// jsonTypeName names a Go kind the way a cluster file author thinks of JSON values
+func jsonTypeName(kind string) string {
+	switch kind {
+	case "string":
+		return "a string"
+	case "slice", "array":
+		return "an array"
+	case "map", "struct":
+		return "an object"
+	case "bool":
+		return "true or false"
+	}
+	return "a number"
+}
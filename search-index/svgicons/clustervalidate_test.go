@@ -0,0 +1,84 @@
+package svgicons
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSVGClusterFileEmpty(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string // Message of the only issue
+		line    int
+	}{
+		{"no clusters object", `{}`, `missing "clusters" object`, 1},
+		{"empty clusters object", `{"clusters": {}}`, `empty "clusters" object, the file lists no icons`, 1},
+		{"empty fileNames", "{\"clusters\": {\n  \"feather\": {\"source_folder\": \"feather\", \"fileNames\": []}\n}}", `cluster "feather" lists no fileNames`, 2},
+		{"missing fileNames", "{\"clusters\": {\n\n  \"feather\": {\"source_folder\": \"feather\"}\n}}", `cluster "feather" lists no fileNames`, 3},
+	}
+	for _, tc := range tests {
+		_, err := parseSVGClusterFile("cluster_svg.json", []byte(tc.content))
+		var clusterErr *svgClusterError
+		if !errors.As(err, &clusterErr) || len(clusterErr.Issues) != 1 {
+			t.Errorf("%s: error %v, want one cluster issue", tc.name, err)
+			continue
+		}
+		if issue := clusterErr.Issues[0]; issue.Message != tc.want || issue.Line != tc.line {
+			t.Errorf("%s: issue %q at line %d, want %q at line %d", tc.name, issue.Message, issue.Line, tc.want, tc.line)
+		}
+	}
+}
+
+func TestGenerateEmptyClusters(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeFile(DefaultClusterPath, `{"clusters": {}}`)
+	// No output is better than an empty one replacing the last good run
+	if _, _, err := Generate(context.Background(), Options{}); err == nil || !strings.Contains(err.Error(), "lists no icons") {
+		t.Errorf("Generate of empty clusters = %v, want an error", err)
+	}
+
+	tt.writeFile(DefaultClusterPath, `{"clusters": {"feather": {"source_folder": "feather", "fileNames": []}}}`)
+	if _, _, err := Generate(context.Background(), Options{}); err == nil || !strings.Contains(err.Error(), `cluster "feather" lists no fileNames`) {
+		t.Errorf("Generate of a cluster without fileNames = %v, want an error naming it", err)
+	}
+}
+
+func TestGenerateEmptyFileNamesContinueOnError(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeSVG("material", "cog.svg", testSVG)
+	tt.writeFile(DefaultClusterPath, `{"clusters": {
+		"feather": {"source_folder": "feather", "fileNames": []},
+		"material": {"source_folder": "material", "fileNames": [{"fileName": "cog.svg"}]}
+	}}`)
+
+	// The empty cluster is left out and reported, not silently dropped
+	icons, report := tt.generate(Options{ContinueOnError: true})
+	if len(icons) != 1 || icons[0].Image != "/svg_icons/material/cog.svg" {
+		t.Errorf("icons = %+v, want only material/cog.svg", icons)
+	}
+	var reported bool
+	for _, err := range report.Errors {
+		reported = reported || strings.Contains(err.Error(), `cluster "feather" lists no fileNames`)
+	}
+	if !reported {
+		t.Errorf("report errors = %v, want the empty feather cluster", report.Errors)
+	}
+}
+
+func TestGenerateWarnsClusterLeftEmpty(t *testing.T) {
+	tt := newTestTree(t)
+	useIgnore(t, "old-*.svg\n")
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather":  {SourceFolder: "feather", FileNames: testFiles("old-home.svg", "old-bell.svg")},
+		"material": {SourceFolder: "material", FileNames: testFiles("cog.svg")},
+	})
+
+	_, report := tt.generate(Options{})
+	warnings := warningsOfKind(report, warnEmptyCluster)
+	if len(warnings) != 1 || warnings[0].Cluster != "feather" || !strings.Contains(warnings[0].Message, "all 2 files of feather") {
+		t.Errorf("empty-cluster warnings = %+v, want one naming the feather folder", warnings)
+	}
+}
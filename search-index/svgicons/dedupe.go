@@ -0,0 +1,38 @@
+package svgicons
+
+import "bytes"
+
+// normalizeSVGWhitespace collapses whitespace runs to a single space and trims the ends,
+// so SVGs that only differ in indentation or line endings hash the same
+func normalizeSVGWhitespace(content []byte) []byte {
+	return bytes.Join(bytes.Fields(content), []byte(" "))
+}
+
+// dedupeSVGIcons keeps the first icon of each group with the same normalized content and
+// records the IDs of the others in its Aliases. Icons that could not be read are kept as is.
+// The icons must be sorted so the canonical icon comes first. Returns the kept icons and
+// the number of icons folded.
+func dedupeSVGIcons(icons []Icon, hashes map[string]string) ([]Icon, int) {
+	primary := make(map[string]int) // Hash to index of the canonical icon in kept
+	kept := make([]Icon, 0, len(icons))
+	folded := 0
+
+	for _, icon := range icons {
+		hash := hashes[icon.Image]
+		if hash == "" {
+			kept = append(kept, icon)
+			continue
+		}
+
+		if i, ok := primary[hash]; ok {
+			kept[i].Aliases = append(kept[i].Aliases, icon.ID)
+			folded++
+			continue
+		}
+
+		primary[hash] = len(kept)
+		kept = append(kept, icon)
+	}
+
+	return kept, folded
+}
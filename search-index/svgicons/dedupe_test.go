@@ -0,0 +1,95 @@
+package svgicons
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSVGWhitespace(t *testing.T) {
+	a := normalizeSVGWhitespace([]byte("<svg>\r\n  <path d=\"M0 0\"/>\r\n</svg>\n"))
+	b := normalizeSVGWhitespace([]byte("<svg>\n\t<path d=\"M0 0\"/>\n</svg>"))
+	if string(a) != string(b) || string(a) != `<svg> <path d="M0 0"/> </svg>` {
+		t.Errorf("normalized = %q and %q, want both %q", a, b, `<svg> <path d="M0 0"/> </svg>`)
+	}
+}
+
+func TestDedupeSVGIcons(t *testing.T) {
+	icons := func(ids ...string) []Icon {
+		list := make([]Icon, len(ids))
+		for i, id := range ids {
+			list[i] = Icon{ID: id, Image: "/svg_icons/" + id + ".svg"}
+		}
+		return list
+	}
+	tests := []struct {
+		name        string
+		icons       []Icon
+		hashes      map[string]string // ID to content hash, none when it could not be read
+		wantAliases map[string][]string
+		wantFolded  int
+	}{
+		{
+			name:        "the first icon is canonical",
+			icons:       icons("a", "b", "c"),
+			hashes:      map[string]string{"a": "1", "b": "1", "c": "1"},
+			wantAliases: map[string][]string{"a": {"b", "c"}},
+			wantFolded:  2,
+		},
+		{
+			name:        "distinct content is kept",
+			icons:       icons("a", "b", "c", "d"),
+			hashes:      map[string]string{"a": "1", "b": "2", "c": "1", "d": "2"},
+			wantAliases: map[string][]string{"a": {"c"}, "b": {"d"}},
+			wantFolded:  2,
+		},
+		{
+			name:        "unreadable icons are never folded",
+			icons:       icons("a", "b"),
+			hashes:      map[string]string{},
+			wantAliases: map[string][]string{},
+			wantFolded:  0,
+		},
+	}
+	for _, tc := range tests {
+		hashes := make(map[string]string)
+		for id, hash := range tc.hashes {
+			hashes["/svg_icons/"+id+".svg"] = hash
+		}
+		kept, folded := dedupeSVGIcons(tc.icons, hashes)
+		aliases := make(map[string][]string)
+		for _, icon := range kept {
+			if len(icon.Aliases) > 0 {
+				aliases[icon.ID] = icon.Aliases
+			}
+		}
+		if !reflect.DeepEqual(aliases, tc.wantAliases) || folded != tc.wantFolded || len(kept) != len(tc.icons)-tc.wantFolded {
+			t.Errorf("%s: kept %d icons with aliases %v, folded %d; want aliases %v, folded %d", tc.name, len(kept), aliases, folded, tc.wantAliases, tc.wantFolded)
+		}
+	}
+}
+
+func TestGenerateDedupe(t *testing.T) {
+	tt := newTestTree(t)
+	// The same SVG indented differently in two folders, and a different one
+	tt.writeSVG("feather", "home.svg", testSVG)
+	tt.writeSVG("material", "house.svg", strings.Replace(testSVG, " ", "\r\n    ", -1)+"\n")
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather":  {SourceFolder: "feather", FileNames: testFiles("home.svg", "bell.svg")},
+		"material": {SourceFolder: "material", FileNames: testFiles("house.svg")},
+	})
+	tt.writeSVG("feather", "bell.svg", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><circle r="4"/></svg>`)
+
+	icons, report := tt.generate(Options{Dedupe: true})
+	if len(icons) != 2 || report.Duplicates != 1 {
+		t.Fatalf("got %d icons, %d duplicates; want 2 and 1", len(icons), report.Duplicates)
+	}
+	home := iconByImage(t, icons, "/svg_icons/feather/home.svg")
+	if !reflect.DeepEqual(home.Aliases, []string{"svg-icons-material-house"}) {
+		t.Errorf("home aliases = %v, want [svg-icons-material-house]", home.Aliases)
+	}
+
+	if icons, _ := tt.generate(Options{}); len(icons) != 3 {
+		t.Errorf("without Dedupe got %d icons, want 3", len(icons))
+	}
+}
@@ -0,0 +1,73 @@
+package svgicons
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Phases of a GenerateError
+const (
+	PhaseCluster = "cluster" // Reading and validating the cluster files
+	PhaseRead    = "read"    // Reading an SVG file
+	PhaseParse   = "parse"   // Parsing an SVG file
+	PhaseRaster  = "raster"  // Rendering the --rasterize fallback of an SVG file
+)
+
+// errorPhases are the phases the failures among the warning kinds belong to. Their icons are
+// still written, with whatever could be recovered, so they are warnings as well.
+var errorPhases = map[string]string{
+	warnMissingFile: PhaseRead,
+	warnReadError:   PhaseRead,
+	warnParseError:  PhaseParse,
+	warnRasterError: PhaseRaster,
+}
+
+// errorPhaseOrder is the order of the phases in the error report
+var errorPhaseOrder = []string{PhaseCluster, PhaseRead, PhaseParse, PhaseRaster}
+
+// GenerateError is a cluster or icon that failed to generate, fully or in part
+type GenerateError struct {
+	Phase   string `json:"phase"`             // One of the Phase* constants
+	Cluster string `json:"cluster,omitempty"` // Key of the cluster, empty for a whole cluster file
+	File    string `json:"file"`              // Cluster file, with the line and column of the problem, or SVG file
+	Message string `json:"message"`
+}
+
+func (e GenerateError) Error() string {
+	if e.Cluster == "" {
+		return fmt.Sprintf("%s: %s: %s", e.Phase, e.File, e.Message)
+	}
+	return fmt.Sprintf("%s: %s (cluster %s): %s", e.Phase, e.File, e.Cluster, e.Message)
+}
+
+// fail prints and records a cluster or icon left out by ContinueOnError
+func (r *Report) fail(e GenerateError) {
+	slog.Error(fmt.Sprintf("❌ %s: %s", e.File, e.Message), "phase", e.Phase, "cluster", e.Cluster, "file", e.File)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Errors = append(r.Errors, e)
+}
+
+// PrintErrors prints every error of the run grouped by phase, for --continue-on-error
+func (r *Report) PrintErrors() {
+	slog.Error(fmt.Sprintf("\n🚫 %d errors, the icons and clusters below are missing or incomplete:", len(r.Errors)), "errors", len(r.Errors))
+
+	byPhase := make(map[string][]GenerateError)
+	for _, e := range r.Errors {
+		byPhase[e.Phase] = append(byPhase[e.Phase], e)
+	}
+	for _, phase := range errorPhaseOrder {
+		if len(byPhase[phase]) == 0 {
+			continue
+		}
+		slog.Error(fmt.Sprintf("   • %s (%d):", phase, len(byPhase[phase])))
+		for _, e := range byPhase[phase] {
+			source := e.File
+			if e.Cluster != "" {
+				source = fmt.Sprintf("%s (cluster %s)", e.File, e.Cluster)
+			}
+			slog.Error(fmt.Sprintf("     - %s: %s", source, e.Message), "phase", e.Phase, "cluster", e.Cluster, "file", e.File)
+		}
+	}
+}
@@ -0,0 +1,838 @@
+// Package svgicons generates the search records of the FreeDevTools SVG icons from the
+// cluster files, the core of the search-index svg_icons category. The CLI writes, stems and
+// indexes the records; other tools can call Generate to embed icon generation in their build.
+package svgicons
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrNoFolderMatch is returned when no cluster source folder matches --folder
+var ErrNoFolderMatch = errors.New("no cluster source folder matches --folder")
+
+// DataVersion is bumped whenever processSVGIcon produces different data for the same
+// input, so the --incremental cache and the change detection manifest are invalidated
+const DataVersion = 10
+
+// svgIconJob is a single cluster file waiting to be turned into icon data
+type svgIconJob struct {
+	ClusterKey   string // Key of the cluster entry listing the file
+	Position     int    // Index of the file in the cluster entry's fileNames
+	SourceFolder string
+	FileName     FileName
+	Attribution  svgAttribution
+	Missing      bool // The SVG file does not exist, already reported by checkMissingSVGFiles
+	System       bool // A hidden file indexed as a system icon by HiddenSystem
+}
+
+// svgAttribution credits the collection of a cluster entry on each of its icons
+type svgAttribution struct {
+	Collection string
+	License    string
+	LicenseURL string
+}
+
+// clusterAttribution returns the cleaned attribution of a cluster entry, empty when absent
+func clusterAttribution(entry ClusterEntry) svgAttribution {
+	return svgAttribution{Collection: cleanText(entry.Author), License: cleanText(entry.License), LicenseURL: cleanText(entry.SourceURL)}
+}
+
+// iconAttribution returns the attribution an icon was generated with
+func iconAttribution(icon Icon) svgAttribution {
+	return svgAttribution{Collection: icon.Collection, License: icon.License, LicenseURL: icon.LicenseURL}
+}
+
+// svgIconResult is the icon data produced for a job
+type svgIconResult struct {
+	ClusterKey   string
+	Position     int
+	SourceFolder string
+	FileName     FileName // Cluster entry of the icon, matched by --since to reuse cached results
+	Icon         Icon
+	ContentHash  string    // SHA-256 of the SVG file, empty if it could not be read
+	DedupeHash   string    // SHA-256 of the whitespace-normalized SVG file, used by --dedupe
+	Warnings     []Warning // Problems found reading the SVG, added to the report by the caller
+}
+
+// Generate builds the SVG icon records from the cluster files selected by opts.
+// The report holds the counts and warnings of the run. Unless opts.DryRun is set, the
+// ID map and the caches are updated in opts.CacheDir.
+func Generate(ctx context.Context, opts Options) ([]Icon, *Report, error) {
+	slog.Info("🎨 Generating SVG icons data...")
+	report := &Report{}
+
+	if !validIDStyle(opts.IDStyle) {
+		return nil, nil, fmt.Errorf("unknown ID style %q, expected one of: %s", opts.IDStyle, strings.Join(IDStyles, ", "))
+	}
+	if !validSortOrder(opts.Sort) {
+		return nil, nil, fmt.Errorf("unknown sort order %q, expected one of: %s", opts.Sort, strings.Join(SortOrders, ", "))
+	}
+	if !validModifiedFrom(opts.ModifiedFrom) {
+		return nil, nil, fmt.Errorf("unknown modified date source %q, expected one of: %s", opts.ModifiedFrom, strings.Join(ModifiedFromSources, ", "))
+	}
+	if !validHidden(opts.Hidden) {
+		return nil, nil, fmt.Errorf("unknown hidden file handling %q, expected one of: %s", opts.Hidden, strings.Join(HiddenPolicies, ", "))
+	}
+	if opts.UpdateLock && (opts.Limit > 0 || opts.Folder != "") {
+		return nil, nil, fmt.Errorf("updating %s needs every icon, it cannot be combined with a limit or folder filter", LockFile)
+	}
+
+	// Cluster files, merged as if they were one
+	clusterPaths, err := opts.ResolveClusterPaths()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cluster, duplicateFolders, clusterFailures, err := opts.loadClusters(clusterPaths)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, failure := range clusterFailures {
+		report.fail(failure)
+	}
+	if len(clusterPaths) > 1 {
+		slog.Info(fmt.Sprintf("📚 Merged %d cluster files with %d clusters", len(clusterPaths), len(cluster.Clusters)), "clusterFiles", len(clusterPaths), "clusters", len(cluster.Clusters))
+	}
+	for _, duplicate := range duplicateFolders {
+		report.warn(warnDuplicateFolder, duplicate.SourceFolder, "Source folder %s is used by clusters in %s", duplicate.SourceFolder, strings.Join(duplicate.Files, ", "))
+	}
+	clusterPrefixes := clusterIDPrefixes(cluster)
+	checkIDPrefixes(cluster, clusterPrefixes, report)
+
+	var jobs []svgIconJob
+	categoryCount := 0
+
+	slog.Info("Processing categories:")
+
+	// Walk the clusters in key order so jobs, and the --incremental fingerprints built
+	// from them, do not depend on map iteration order
+	clusterKeys := make([]string, 0, len(cluster.Clusters))
+	for key := range cluster.Clusters {
+		clusterKeys = append(clusterKeys, key)
+	}
+	sort.Strings(clusterKeys)
+
+	var keptFolders []string // Source folders left out by --folder, see processSVGIconJobsIncremental
+	for _, key := range clusterKeys {
+		if opts.Limit > 0 && len(jobs) >= opts.Limit {
+			break
+		}
+		clusterEntry := cluster.Clusters[key]
+		if !opts.MatchesFolder(clusterEntry.SourceFolder) {
+			if !ContainsString(keptFolders, clusterEntry.SourceFolder) {
+				keptFolders = append(keptFolders, clusterEntry.SourceFolder)
+			}
+			continue
+		}
+		if folderIgnored(clusterEntry.SourceFolder) {
+			slog.Debug(fmt.Sprintf("  • %s: %s is ignored by %s", key, clusterEntry.SourceFolder, IgnoreFile), "cluster", key, "sourceFolder", clusterEntry.SourceFolder)
+			report.Ignored += len(clusterEntry.FileNames)
+			continue
+		}
+		if (clusterEntry.Source != "" || opts.Source != "") && !sourceOpen(clusterEntry.SourceFolder) {
+			return nil, nil, fmt.Errorf("cluster %q reads its SVG files from a source, open it with OpenSources first", key)
+		}
+		categoryCount++
+		slog.Debug(fmt.Sprintf("  • %s: %d files in %s", key, len(clusterEntry.FileNames), clusterEntry.SourceFolder), "cluster", key, "sourceFolder", clusterEntry.SourceFolder, "files", len(clusterEntry.FileNames))
+
+		ignored, skipped := 0, 0
+		for position, fileName := range clusterEntry.FileNames {
+			if opts.Limit > 0 && len(jobs) >= opts.Limit {
+				break
+			}
+			if fileIgnored(clusterEntry.SourceFolder, fileName.FileName) {
+				report.Ignored++
+				ignored++
+				continue
+			}
+			hidden := isHiddenFile(fileName.FileName)
+			if hidden && strings.EqualFold(opts.Hidden, HiddenSkip) {
+				report.Hidden++
+				skipped++
+				continue
+			}
+			system := hidden && strings.EqualFold(opts.Hidden, HiddenSystem)
+			jobs = append(jobs, svgIconJob{ClusterKey: key, Position: position, SourceFolder: clusterEntry.SourceFolder, FileName: fileName, Attribution: clusterAttribution(clusterEntry), System: system})
+			if cleanText(fileName.Description) == "" {
+				report.EmptyDescriptions++
+			}
+		}
+		// Cluster files without any fileNames are rejected when loading, this catches the
+		// ones left empty by .svgignore, which would otherwise vanish from the output unnoticed
+		if left := ignored + skipped; left > 0 && left == len(clusterEntry.FileNames) {
+			w := newSVGWarning(warnEmptyCluster, clusterEntry.SourceFolder, "Cluster %s contributes no icons, all %d files of %s are ignored by %s or hidden", key, left, clusterEntry.SourceFolder, IgnoreFile)
+			w.Cluster = key
+			report.record(w)
+		}
+	}
+	iconCount := len(jobs)
+	if opts.Folder != "" {
+		if iconCount == 0 {
+			return nil, nil, fmt.Errorf("%w %q", ErrNoFolderMatch, opts.Folder)
+		}
+		slog.Info(fmt.Sprintf("📁 --folder %s: processing %d clusters with %d files", opts.Folder, categoryCount, iconCount))
+	}
+	if iconCount == 0 {
+		// Writing the empty output would wipe the icons of the last good run
+		return nil, nil, fmt.Errorf("no SVG icons to process in %d clusters, %d files ignored by %s and %d hidden files skipped", len(cluster.Clusters), report.Ignored, IgnoreFile, report.Hidden)
+	}
+	if report.Ignored > 0 {
+		slog.Info(fmt.Sprintf("🙈 Ignored %d cluster files matching %s", report.Ignored, IgnoreFile), "ignored", report.Ignored)
+	}
+	if report.Hidden > 0 {
+		slog.Info(fmt.Sprintf("🙈 Skipped %d hidden cluster files, their name starting with an underscore", report.Hidden), "hidden", report.Hidden)
+	}
+	if opts.Limit > 0 {
+		slog.Info(fmt.Sprintf("✂️  --limit %d: processing the first %d cluster files", opts.Limit, iconCount))
+	}
+
+	if missing := checkMissingSVGFiles(jobs, report); missing > 0 {
+		slog.Warn(fmt.Sprintf("⚠️  Warning: %d cluster entries reference missing SVG files", missing))
+	}
+
+	var metaCache *svgMetaCache
+	if !opts.NoCache {
+		if metaCache, err = loadSVGMetaCache(opts.CacheDir); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var results []svgIconResult
+	switch {
+	case opts.Incremental:
+		results, err = processSVGIconJobsIncremental(ctx, jobs, keptFolders, opts, metaCache)
+	case !opts.Since.IsZero():
+		results, err = processSVGIconJobsSince(ctx, jobs, keptFolders, opts, metaCache)
+	default:
+		results, err = processSVGIconJobs(ctx, jobs, opts, metaCache)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if metaCache != nil {
+		slog.Info(fmt.Sprintf("♻️  Reused the cached metadata of %d of %d SVG files read", metaCache.hits, len(metaCache.seen)), "cacheHits", metaCache.hits, "filesRead", len(metaCache.seen))
+		if !opts.DryRun {
+			if err := metaCache.save(); err != nil {
+				return nil, nil, fmt.Errorf("failed to save %s: %w", svgMetaCacheFile, err)
+			}
+		}
+	}
+
+	// Workers and the --incremental cache return results in any order. Put them back in
+	// cluster file order so warnings, ID inheritance and tie-breaks between icons with the
+	// same ID and image are the same on every run.
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].ClusterKey != results[j].ClusterKey {
+			return results[i].ClusterKey < results[j].ClusterKey
+		}
+		return results[i].Position < results[j].Position
+	})
+
+	for _, result := range results {
+		for _, w := range result.Warnings {
+			w.Cluster = result.ClusterKey
+			report.record(w)
+		}
+	}
+
+	// The parallel phase ends here. The maps below and every step after (ID collisions, the ID
+	// map, --dedupe) are only touched by this goroutine, so workers must not read or write them.
+	svgIconsData := make([]Icon, 0, len(results))
+	contentHashes := make(map[string]string, len(results))
+	dedupeHashes := make(map[string]string, len(results))
+	sourceFolders := make(map[string]string, len(results)) // Image to source folder
+	positions := make(map[string]int, len(results))        // Image to its first index in cluster file order, for SortNone
+	imageClusters := make(map[string]string, len(results)) // Image to the key of the cluster listing it
+	for i, result := range results {
+		svgIconsData = append(svgIconsData, result.Icon)
+		sourceFolders[result.Icon.Image] = result.SourceFolder
+		imageClusters[result.Icon.Image] = result.ClusterKey
+		if _, ok := positions[result.Icon.Image]; !ok {
+			positions[result.Icon.Image] = i
+		}
+		if result.ContentHash != "" {
+			contentHashes[result.Icon.Image] = result.ContentHash
+			dedupeHashes[result.Icon.Image] = result.DedupeHash
+		}
+	}
+
+	// Check the SVG files against the lock before spending any more work on them
+	if opts.Verify || opts.UpdateLock {
+		current := hashLockedFiles(sourceFolders)
+		if opts.Verify {
+			if err := verifyLock(LockFile, current, opts.Limit == 0 && opts.Folder == ""); err != nil {
+				return nil, nil, err
+			}
+		}
+		if opts.UpdateLock && opts.DryRun {
+			slog.Info(fmt.Sprintf("🔒 Dry run, not updating %s", LockFile))
+		} else if opts.UpdateLock {
+			if err := saveLock(LockFile, current); err != nil {
+				return nil, nil, fmt.Errorf("failed to save %s: %w", LockFile, err)
+			}
+			slog.Info(fmt.Sprintf("🔒 Locked the hashes of %d SVG files in %s", len(current), LockFile), "files", len(current))
+		}
+	}
+
+	idPrefixes := make(map[string]string) // Image to the id_prefix of its cluster
+	for image, key := range imageClusters {
+		if prefix := clusterPrefixes[key]; prefix != "" {
+			idPrefixes[image] = prefix
+		}
+	}
+	if strings.EqualFold(opts.IDStyle, IDStyleHash) {
+		if err := applyHashIDs(svgIconsData, idPrefixes); err != nil {
+			return nil, nil, err
+		}
+	} else if prefixed := applyIDPrefixes(svgIconsData, idPrefixes, sourceFolders); prefixed > 0 {
+		slog.Info(fmt.Sprintf("🏷️  Gave %d icons the id_prefix of their cluster", prefixed), "prefixed", prefixed)
+	}
+
+	// Keep the IDs of icons that were moved or renamed since the last run
+	idMap, err := loadIDMap(opts.CacheDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	inheritedIDs := idMap.apply(svgIconsData, contentHashes)
+	if len(inheritedIDs) > 0 {
+		slog.Info(fmt.Sprintf("🔗 Kept previous IDs for %d moved or renamed icons", len(inheritedIDs)))
+	}
+
+	// Sort by ID, using the image path to order icons whose IDs collide. The sort is stable
+	// so icons listed more than once keep their cluster file order.
+	sort.SliceStable(svgIconsData, func(i, j int) bool {
+		if svgIconsData[i].ID != svgIconsData[j].ID {
+			return svgIconsData[i].ID < svgIconsData[j].ID
+		}
+		return svgIconsData[i].Image < svgIconsData[j].Image
+	})
+
+	collisions := resolveDuplicateIDs(svgIconsData, report)
+	report.Collisions = collisions
+	if collisions > 0 {
+		slog.Info(fmt.Sprintf("🔧 Resolved %d duplicate icon IDs", collisions))
+		sort.Slice(svgIconsData, func(i, j int) bool {
+			return svgIconsData[i].ID < svgIconsData[j].ID
+		})
+	}
+
+	if opts.Dedupe {
+		var folded int
+		svgIconsData, folded = dedupeSVGIcons(svgIconsData, dedupeHashes)
+		report.Duplicates = folded
+		slog.Info(fmt.Sprintf("🧬 Folded %d duplicate SVGs into aliases", folded))
+	}
+
+	if opts.GroupVariants {
+		var folded int
+		svgIconsData, folded = groupSVGVariants(svgIconsData, opts.variantSuffixes())
+		report.Variants = folded
+		slog.Info(fmt.Sprintf("🎭 Grouped %d style variants under their base icons", folded))
+	}
+
+	if len(popularity) > 0 {
+		scored := applyPopularity(svgIconsData)
+		slog.Info(fmt.Sprintf("📈 Applied popularity scores from %s to %d of %d icons", PopularityFile, scored, len(svgIconsData)))
+	}
+
+	if opts.Related {
+		linked, err := linkRelatedIcons(ctx, svgIconsData, opts.RelatedCount, opts.WorkerCount())
+		if err != nil {
+			return nil, nil, err
+		}
+		slog.Info(fmt.Sprintf("🔗 Linked related icons for %d of %d icons", linked, len(svgIconsData)))
+	}
+
+	if opts.Optimize {
+		original, optimized := optimizeSVGIcons(svgIconsData)
+		saved := 0.0
+		if original > 0 {
+			saved = 100 * float64(original-optimized) / float64(original)
+		}
+		slog.Info(fmt.Sprintf("🪶 Optimized SVGs from %d to %d bytes (-%.1f%%)", original, optimized, saved), "originalBytes", original, "optimizedBytes", optimized)
+	}
+
+	if opts.InlineSVG {
+		inlined := inlineSVGIcons(svgIconsData, opts.InlineSVGMaxBytes, opts.Optimize)
+		slog.Info(fmt.Sprintf("🖼️  Inlined %d of %d icons as data URIs", inlined, len(svgIconsData)))
+	}
+
+	if opts.Rasterize {
+		size, format := opts.rasterSize(), opts.rasterFormat()
+		if opts.DryRun {
+			slog.Info(fmt.Sprintf("🧱 Dry run, skipping the %dpx %s rasters", size, format))
+		} else {
+			rendered, kept, err := rasterizeSVGIcons(ctx, svgIconsData, size, format, opts.WorkerCount(), imageClusters, report)
+			if err != nil {
+				return nil, nil, err
+			}
+			slog.Info(fmt.Sprintf("🧱 Rasterized %d icons to %dpx %s next to their SVGs, %d already up to date", rendered, size, format, kept), "rendered", rendered, "upToDate", kept)
+		}
+	}
+
+	if opts.MaxDescriptionLen > 0 {
+		truncated := truncateDescriptions(svgIconsData, opts.MaxDescriptionLen, opts.KeepFullDescription)
+		slog.Info(fmt.Sprintf("✂️  Truncated %d descriptions longer than %d characters", truncated, opts.MaxDescriptionLen), "truncated", truncated)
+	}
+
+	missingFiles := make(map[string]bool)
+	for _, job := range jobs {
+		if job.Missing {
+			missingFiles[FilePath(job.SourceFolder, job.FileName.FileName)] = true
+		}
+	}
+	checkIconPaths(svgIconsData, missingFiles, report)
+
+	dated, err := setModifiedAt(ctx, svgIconsData, opts.ModifiedFrom, sourceFolders)
+	if err != nil {
+		return nil, nil, err
+	}
+	if dated < len(svgIconsData) && !strings.EqualFold(opts.ModifiedFrom, ModifiedFromNone) {
+		slog.Info(fmt.Sprintf("🕒 %d of %d icons have no modified date", len(svgIconsData)-dated, len(svgIconsData)), "undated", len(svgIconsData)-dated)
+	}
+
+	// A limited or filtered run only sees some of the icons, saving its map would drop the others' IDs
+	if !opts.DryRun && opts.Limit == 0 && opts.Folder == "" {
+		if err := saveIDMap(opts.CacheDir, newIDMap(svgIconsData, contentHashes, inheritedIDs)); err != nil {
+			return nil, nil, fmt.Errorf("failed to save %s: %w", idMapFile, err)
+		}
+	}
+
+	rebaseIconPaths(svgIconsData, opts.ResolveBasePath())
+
+	// Every step above works on icons sorted by ID
+	sortIcons(svgIconsData, opts.Sort, sourceFolders, positions)
+
+	report.Categories = categoryCount
+	report.Icons = len(svgIconsData)
+	report.IconsPerFolder = make(map[string]int)
+	for _, icon := range svgIconsData {
+		report.IconsPerFolder[sourceFolders[icon.Image]]++
+		if icon.Complexity > opts.ComplexityThreshold {
+			report.ComplexIcons = append(report.ComplexIcons, svgComplexIcon{ID: icon.ID, Image: icon.Image, Complexity: icon.Complexity})
+		}
+	}
+	if len(report.ComplexIcons) > 0 {
+		slog.Warn(fmt.Sprintf("⚠️  %d icons have a complexity above %d, listed in %s as complexIcons", len(report.ComplexIcons), opts.ComplexityThreshold, StatsFile), "complexIcons", len(report.ComplexIcons))
+	}
+
+	slog.Info(fmt.Sprintf("🎨 Processed %d categories with %d icons total", categoryCount, iconCount), "category", "svg_icons", "categories", categoryCount, "iconCount", iconCount)
+	return svgIconsData, report, nil
+}
+
+// checkMissingSVGFiles marks the jobs whose SVG file does not exist on disk and
+// records a warning naming the cluster source folder for each of them.
+// Returns the number of missing files.
+func checkMissingSVGFiles(jobs []svgIconJob, report *Report) int {
+	missing := 0
+	for i := range jobs {
+		svgFile := FilePath(jobs[i].SourceFolder, jobs[i].FileName.FileName)
+		if _, err := os.Stat(svgFile); os.IsNotExist(err) {
+			jobs[i].Missing = true
+			missing++
+			w := newSVGWarning(warnMissingFile, svgFile, "Cluster %s lists %s but %s does not exist", jobs[i].SourceFolder, jobs[i].FileName.FileName, svgFile)
+			w.Cluster = jobs[i].ClusterKey
+			report.record(w)
+		}
+	}
+	return missing
+}
+
+// processSVGIconJobs turns cluster files into icon data using a pool of opts.WorkerCount()
+// workers, reporting each finished job to opts.Progress.
+// Results come back in completion order; callers sort them afterwards.
+func processSVGIconJobs(ctx context.Context, jobs []svgIconJob, opts Options, metaCache *svgMetaCache) ([]svgIconResult, error) {
+	workers := opts.WorkerCount()
+	jobsChan := make(chan svgIconJob)
+	resultsChan := make(chan svgIconResult, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobsChan {
+				result := processSVGIcon(job, metaCache)
+				result.FileName = job.FileName
+				select {
+				case resultsChan <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	// Feed jobs until done or cancelled
+	go func() {
+		defer close(jobsChan)
+		for _, job := range jobs {
+			select {
+			case jobsChan <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultsChan)
+	}()
+
+	results := make([]svgIconResult, 0, len(jobs))
+	for result := range resultsChan {
+		results = append(results, result)
+		if opts.Progress != nil {
+			opts.Progress(len(results), len(jobs))
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// processSVGIcon builds the icon data for a single cluster file, reading its SVG through
+// metaCache, which may be nil
+func processSVGIcon(job svgIconJob, metaCache *svgMetaCache) svgIconResult {
+	fileName := job.FileName
+	relPath := svgRelativePath(fileName.FileName)
+	subDir, baseName := path.Split(relPath)
+
+	// Remove leading underscore if present, unless kept for a system icon, and get the name without extension
+	iconName := iconFileName(baseName, job.System)
+
+	// Format the display name to be more user-friendly, the underscore only marking the file as hidden
+	displayName := cleanText(FormatIconName(strings.TrimPrefix(iconName, "_")))
+
+	// Create the path (similar to Python logic), keeping any subfolder of the file
+	iconPath := fmt.Sprintf("%s%s/%s%s/", svgIconsBasePath, job.SourceFolder, subDir, iconName)
+
+	// Generate ID from path (similar to Python logic)
+	iconID := IconIDFromPath(iconPath)
+
+	// Use description from fileName if available, otherwise the SVG's own <desc>/<title>
+	// (set below once the file is parsed) or a default
+	clusterDescription := cleanText(fileName.Description)
+	description := clusterDescription
+	if description == "" {
+		description = fmt.Sprintf("SVG icon for %s", displayName)
+	}
+
+	// Generate icon data
+	iconData := Icon{
+		ID:          iconID,
+		Name:        displayName,
+		Description: description,
+		Path:        iconPath,
+		Image:       fmt.Sprintf("%s%s/%s", svgImageBase, job.SourceFolder, relPath),
+		Category:    "svg_icons",
+		Collection:  job.Attribution.Collection,
+		License:     job.Attribution.License,
+		LicenseURL:  job.Attribution.LicenseURL,
+		Tags:        iconTags(strings.TrimPrefix(iconName, "_")),
+		Keywords:    iconKeywords(fileName.Keywords),
+		AriaLabel:   ariaLabel(displayName, ""),
+		System:      job.System,
+	}
+
+	// Read the dimensions from the SVG file itself
+	if job.Missing {
+		return svgIconResult{ClusterKey: job.ClusterKey, Position: job.Position, SourceFolder: job.SourceFolder, Icon: iconData}
+	}
+
+	svgFile := FilePath(job.SourceFolder, fileName.FileName)
+	fileData, err := metaCache.read(svgFile)
+	if err != nil {
+		return svgIconResult{
+			ClusterKey:   job.ClusterKey,
+			Position:     job.Position,
+			SourceFolder: job.SourceFolder,
+			Icon:         iconData,
+			Warnings:     []Warning{newSVGWarning(warnReadError, svgFile, "Failed to read SVG %s: %v", svgFile, err)},
+		}
+	}
+
+	// Broken files are still indexed, with whatever metadata the lenient parser recovers
+	var warnings []Warning
+	wellFormed := true
+	if fileData.ParseError != "" {
+		w := newSVGWarning(warnParseError, svgFile, "SVG %s is not well-formed: %s", svgFile, fileData.ParseError)
+		w.Detail = fileData.ParseError
+		warnings = append(warnings, w)
+		wellFormed = false
+	}
+	if meta := fileData.Meta; meta != nil {
+		// The elements recovered from a broken file say little about its weight
+		if wellFormed {
+			iconData.Complexity = meta.Complexity
+		}
+		iconData.Width = meta.Width
+		iconData.Height = meta.Height
+		iconData.ViewBox = meta.ViewBox
+		iconData.Colors = meta.Colors
+		iconData.Monochrome = meta.Monochrome
+		iconData.AriaLabel = ariaLabel(displayName, meta.Title)
+		if clusterDescription == "" {
+			if svgDescription := svgTextDescription(meta); svgDescription != "" {
+				iconData.Description = svgDescription
+			}
+		}
+		if meta.ViewBox == "" {
+			warnings = append(warnings, newSVGWarning(warnNoViewBox, svgFile, "SVG %s has no viewBox or width/height", svgFile))
+		}
+	}
+	if iconData.Description == description && clusterDescription == "" {
+		warnings = append(warnings, newSVGWarning(warnEmptyDescription, svgFile, "Icon %s has no description in its cluster entry or SVG, using %q", iconID, description))
+	}
+
+	return svgIconResult{
+		ClusterKey:   job.ClusterKey,
+		Position:     job.Position,
+		SourceFolder: job.SourceFolder,
+		Icon:         iconData,
+		ContentHash:  fileData.ContentHash,
+		DedupeHash:   fileData.DedupeHash,
+		Warnings:     warnings,
+	}
+}
+
+// cleanText flattens text copied from source data into a single clean line: control
+// characters are dropped, whitespace runs including tabs and newlines become one space and
+// the ends are trimmed. Other Unicode characters are kept as they are.
+func cleanText(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// svgTextDescription returns the description an SVG gives itself: its <desc>, prefixed by its
+// <title> when that adds something, e.g. "Home: A house with a chimney"
+func svgTextDescription(meta *svgMetadata) string {
+	switch {
+	case meta.Desc == "":
+		return meta.Title
+	case meta.Title == "" || strings.Contains(strings.ToLower(meta.Desc), strings.ToLower(meta.Title)):
+		return meta.Desc
+	default:
+		return meta.Title + ": " + meta.Desc
+	}
+}
+
+// ariaLabel suggests an accessible label for an icon: the SVG's own <title> when it has one,
+// otherwise the display name followed by "icon", e.g. "Arrow Up Circle icon"
+func ariaLabel(name, title string) string {
+	if title != "" {
+		return title
+	}
+	return name + " icon"
+}
+
+// resolveDuplicateIDs makes icon IDs unique by suffixing collisions with -2, -3, ...
+// The icons must be sorted so that the first icon of each colliding group keeps its ID.
+// Returns the number of collisions that were resolved.
+func resolveDuplicateIDs(icons []Icon, report *Report) int {
+	taken := make(map[string]bool, len(icons))
+	for _, icon := range icons {
+		taken[icon.ID] = true
+	}
+
+	firstSource := make(map[string]string, len(icons))
+	collisions := 0
+
+	for i := range icons {
+		id := icons[i].ID
+		first, seen := firstSource[id]
+		if !seen {
+			firstSource[id] = icons[i].Image
+			continue
+		}
+
+		suffix := 2
+		newID := fmt.Sprintf("%s-%d", id, suffix)
+		for taken[newID] {
+			suffix++
+			newID = fmt.Sprintf("%s-%d", id, suffix)
+		}
+		taken[newID] = true
+		collisions++
+
+		report.warn(warnDuplicateID, icons[i].Image, "Duplicate icon ID %s for %s and %s, renamed to %s", id, first, icons[i].Image, newID)
+		icons[i].ID = newID
+	}
+
+	return collisions
+}
+
+// svgRelativePath cleans a cluster file name that may include a subfolder of the source
+// folder, e.g. social/twitter.svg. Backslashes become slashes and empty, "." and ".."
+// segments are dropped, so the result never starts with a slash or leaves the source folder.
+func svgRelativePath(fileName string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.Replace(fileName, "\\", "/", -1)), "/")
+}
+
+// IconsDirPath returns the location of a cluster file in IconsDir, where FilePath finds it
+// unless OpenSources opened another source for the folder
+func IconsDirPath(sourceFolder, fileName string) string {
+	return filepath.Join(IconsDir, sourceFolder, filepath.FromSlash(svgRelativePath(fileName)))
+}
+
+// FilePath returns the location on disk of a cluster file, in IconsDir unless OpenSources
+// opened another source for the folder
+func FilePath(sourceFolder, fileName string) string {
+	return filepath.Join(folderRoot(sourceFolder), filepath.FromSlash(svgRelativePath(fileName)))
+}
+
+const (
+	// svgIconsBasePath is the default page path SVG icon pages live under. Icons are always
+	// generated under it, then moved to Options.BasePath by rebaseIconPaths.
+	svgIconsBasePath = "/freedevtools/svg_icons/"
+	// svgIconsIDPrefix namespaces the SVG icon IDs
+	svgIconsIDPrefix = "svg-icons"
+)
+
+// IconIDFromPath derives an icon ID from its page path,
+// e.g. /freedevtools/svg_icons/feather/home/ gives svg-icons-feather-home
+func IconIDFromPath(path string) string {
+	return CategoryIDFromPath(path, svgIconsBasePath, svgIconsIDPrefix)
+}
+
+// CategoryIDFromPath derives the ID of a page of any category from its path: basePath is
+// removed, the remaining slashes become hyphens and prefix is added with a hyphen, e.g.
+// /freedevtools/png_icons/feather/home/ with /freedevtools/png_icons/ and png-icons gives
+// png-icons-feather-home. An empty prefix leaves the ID unprefixed.
+func CategoryIDFromPath(path, basePath, prefix string) string {
+	// Remove the base path (similar to Python logic)
+	cleanPath := strings.Replace(path, basePath, "", 1)
+
+	// Remove trailing slash if present
+	cleanPath = strings.TrimSuffix(cleanPath, "/")
+
+	// Replace remaining slashes with hyphens
+	cleanPath = strings.Replace(cleanPath, "/", "-", -1)
+
+	// Sanitize, which transliterates non-ASCII letters and replaces any other invalid
+	// characters with underscores
+	if prefix == "" {
+		return SanitizeID(cleanPath)
+	}
+	return fmt.Sprintf("%s-%s", prefix, SanitizeID(cleanPath))
+}
+
+// iconTags returns the distinct lowercased words of an icon name, split like FormatIconName,
+// e.g. arrow-up-circle -> [arrow up circle]
+func iconTags(iconName string) []string {
+	name := strings.Replace(iconName, "_", " ", -1)
+	name = strings.Replace(name, "-", " ", -1)
+
+	var tags []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Fields(name) {
+		for _, word := range splitCamelCase(field) {
+			tag := strings.ToLower(word)
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// iconKeywords returns the search keywords authored for an icon in its cluster entry, cleaned
+// like other cluster text, without empty ones or repeats (ignoring case), in authored order
+func iconKeywords(keywords []string) []string {
+	var cleaned []string
+	seen := make(map[string]bool)
+	for _, keyword := range keywords {
+		keyword = cleanText(keyword)
+		if keyword != "" && !seen[strings.ToLower(keyword)] {
+			seen[strings.ToLower(keyword)] = true
+			cleaned = append(cleaned, keyword)
+		}
+	}
+	return cleaned
+}
+
+// FormatIconName turns a file name without extension into a display name,
+// e.g. arrowLeftCircle gives "Arrow Left Circle"
+func FormatIconName(iconName string) string {
+	// Replace underscores and hyphens with spaces
+	name := strings.Replace(iconName, "_", " ", -1)
+	name = strings.Replace(name, "-", " ", -1)
+
+	// Split camelCase words, then title case keeping the canonical casing of known acronyms and brands
+	var words []string
+	for _, field := range strings.Fields(name) {
+		parts := splitCamelCase(field)
+		for _, word := range parts {
+			if casing, ok := nameCasing[strings.ToLower(word)]; ok {
+				words = append(words, casing)
+			} else if len(parts) > 1 && utf8.RuneCountInString(word) > 1 && strings.ToUpper(word) == word {
+				// Acronym run inside a camelCase name, e.g. the HTML in parseHTMLNode
+				words = append(words, word)
+			} else if len(word) > 0 {
+				words = append(words, titleCase(word))
+			}
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// titleCase upper cases the first letter of a word and lower cases the rest, rune by rune
+// so multibyte letters such as the ü of über stay intact
+func titleCase(word string) string {
+	first, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToTitle(first)) + strings.ToLower(word[size:])
+}
+
+// splitCamelCase splits a word at camelCase boundaries and before digit runs.
+// A run of capitals stays together, so "parseHTMLNode" becomes ["parse", "HTML", "Node"].
+func splitCamelCase(word string) []string {
+	runes := []rune(word)
+	var parts []string
+	start := 0
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		boundary := false
+
+		switch {
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			// arrowLeft -> arrow Left
+			boundary = true
+		case unicode.IsUpper(cur) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// HTMLNode -> HTML Node
+			boundary = true
+		case unicode.IsDigit(cur) && unicode.IsLetter(prev):
+			// icon24 -> icon 24
+			boundary = true
+		}
+
+		if boundary {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	return append(parts, string(runes[start:]))
+}
@@ -0,0 +1,628 @@
+package svgicons
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testSVG is a well-formed icon with a viewBox, so it records no warnings
+const testSVG = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path d="M3 12h18"/></svg>`
+
+// testTree is a temporary checkout laid out like the real one: the working directory is its
+// search-index directory, the SVG files are in IconsDir and the clusters in DefaultClusterPath
+type testTree struct {
+	t    testing.TB
+	root string
+}
+
+// newTestTree creates a testTree and moves into it until the test ends. Tests using it
+// change the working directory, so they must not run in parallel.
+func newTestTree(t testing.TB) *testTree {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "search-index"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(root, "search-index")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return &testTree{t: t, root: root}
+}
+
+// writeFile writes a file relative to the working directory, creating its directory
+func (tt *testTree) writeFile(name, content string) string {
+	tt.t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		tt.t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+		tt.t.Fatal(err)
+	}
+	return name
+}
+
+// writeSVG writes an SVG file of a source folder in IconsDir
+func (tt *testTree) writeSVG(sourceFolder, fileName, content string) string {
+	tt.t.Helper()
+	return tt.writeFile(IconsDirPath(sourceFolder, fileName), content)
+}
+
+// writeClusters writes the default cluster file, also writing testSVG for every file a
+// cluster lists that does not exist yet
+func (tt *testTree) writeClusters(clusters map[string]ClusterEntry) {
+	tt.t.Helper()
+	for _, entry := range clusters {
+		for _, fileName := range entry.FileNames {
+			if _, err := os.Stat(IconsDirPath(entry.SourceFolder, fileName.FileName)); os.IsNotExist(err) {
+				tt.writeSVG(entry.SourceFolder, fileName.FileName, testSVG)
+			}
+		}
+	}
+	data, err := json.Marshal(Cluster{Clusters: clusters})
+	if err != nil {
+		tt.t.Fatal(err)
+	}
+	tt.writeFile(DefaultClusterPath, string(data))
+}
+
+// generate runs Generate on the default cluster file, failing the test on error
+func (tt *testTree) generate(opts Options) ([]Icon, *Report) {
+	tt.t.Helper()
+	icons, report, err := Generate(context.Background(), opts)
+	if err != nil {
+		tt.t.Fatalf("Generate: %v", err)
+	}
+	return icons, report
+}
+
+// testFiles lists cluster files by name
+func testFiles(names ...string) []FileName {
+	fileNames := make([]FileName, len(names))
+	for i, name := range names {
+		fileNames[i] = FileName{FileName: name}
+	}
+	return fileNames
+}
+
+// iconByImage returns the icon generated from an image, failing the test when there is none
+func iconByImage(t *testing.T, icons []Icon, image string) Icon {
+	t.Helper()
+	for _, icon := range icons {
+		if icon.Image == image {
+			return icon
+		}
+	}
+	t.Fatalf("no icon has image %s", image)
+	return Icon{}
+}
+
+func warningsOfKind(report *Report, kind string) []Warning {
+	var warnings []Warning
+	for _, w := range report.Warnings {
+		if w.Kind == kind {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+func TestGenerateResolvesCollidingIDs(t *testing.T) {
+	tt := newTestTree(t)
+	// Both folders sanitize to svg-icons-foo_bar-home
+	tt.writeClusters(map[string]ClusterEntry{
+		"a": {SourceFolder: "foo bar", FileNames: testFiles("home.svg")},
+		"b": {SourceFolder: "foo_bar", FileNames: testFiles("home.svg")},
+	})
+
+	icons, report := tt.generate(Options{})
+	if len(icons) != 2 {
+		t.Fatalf("got %d icons, want 2", len(icons))
+	}
+	// Ties are broken by image, so the suffix does not depend on cluster order
+	first := iconByImage(t, icons, "/svg_icons/foo bar/home.svg")
+	second := iconByImage(t, icons, "/svg_icons/foo_bar/home.svg")
+	if first.ID != "svg-icons-foo_bar-home" || second.ID != "svg-icons-foo_bar-home-2" {
+		t.Errorf("IDs = %s and %s, want svg-icons-foo_bar-home and svg-icons-foo_bar-home-2", first.ID, second.ID)
+	}
+	if report.Collisions != 1 {
+		t.Errorf("Collisions = %d, want 1", report.Collisions)
+	}
+	warnings := warningsOfKind(report, warnDuplicateID)
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Message, "foo bar/home.svg") || !strings.Contains(warnings[0].Message, "foo_bar/home.svg") {
+		t.Errorf("duplicate-id warnings = %+v, want one naming both files", warnings)
+	}
+}
+
+func TestResolveDuplicateIDs(t *testing.T) {
+	// svg-icons-a-2 is taken by a real icon, so the second collision skips to -3
+	icons := []Icon{
+		{ID: "svg-icons-a", Image: "/svg_icons/a/1.svg"},
+		{ID: "svg-icons-a", Image: "/svg_icons/a/2.svg"},
+		{ID: "svg-icons-a", Image: "/svg_icons/a/3.svg"},
+		{ID: "svg-icons-a-2", Image: "/svg_icons/a/4.svg"},
+	}
+	report := &Report{}
+	if got := resolveDuplicateIDs(icons, report); got != 2 {
+		t.Errorf("resolveDuplicateIDs = %d, want 2", got)
+	}
+
+	want := []string{"svg-icons-a", "svg-icons-a-3", "svg-icons-a-4", "svg-icons-a-2"}
+	seen := make(map[string]bool)
+	for i, icon := range icons {
+		if icon.ID != want[i] {
+			t.Errorf("icon %d ID = %s, want %s", i, icon.ID, want[i])
+		}
+		if seen[icon.ID] {
+			t.Errorf("ID %s is not unique", icon.ID)
+		}
+		seen[icon.ID] = true
+	}
+}
+
+func TestFormatIconName(t *testing.T) {
+	tests := []struct {
+		iconName string
+		want     string
+	}{
+		{"arrow-left", "Arrow Left"},
+		{"arrow_left_circle", "Arrow Left Circle"},
+		{"arrowLeftCircle", "Arrow Left Circle"},
+		{"UserProfile", "User Profile"},
+		{"parseHTMLNode", "Parse HTML Node"},
+		{"HTMLParser", "HTML Parser"},
+		{"getURL", "Get URL"},
+		{"icon24", "Icon 24"},
+		{"arrow-upRight_2", "Arrow Up Right 2"},
+		{"NASA", "Nasa"},
+		{"github-logo", "GitHub Logo"},
+		{"ios_settings", "iOS Settings"},
+		{"home", "Home"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := FormatIconName(tt.iconName); got != tt.want {
+			t.Errorf("FormatIconName(%q) = %q, want %q", tt.iconName, got, tt.want)
+		}
+	}
+}
+
+func TestSplitCamelCase(t *testing.T) {
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"arrowLeftCircle", []string{"arrow", "Left", "Circle"}},
+		{"parseHTMLNode", []string{"parse", "HTML", "Node"}},
+		{"HTML", []string{"HTML"}},
+		{"icon24px", []string{"icon", "24px"}},
+		{"x", []string{"x"}},
+	}
+	for _, tt := range tests {
+		if got := splitCamelCase(tt.word); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("splitCamelCase(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	tt := newTestTree(t)
+	clusters := make(map[string]ClusterEntry)
+	for _, folder := range []string{"feather", "material", "tabler"} {
+		var files []FileName
+		for i := 0; i < 40; i++ {
+			files = append(files, FileName{FileName: fmt.Sprintf("icon-%d.svg", i), Tags: []string{"b", "a"}})
+			tt.writeSVG(folder, fmt.Sprintf("icon-%d.svg", i), fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" fill="#%06x" stroke="#000"><path d="M%d 0h1"/></svg>`, i, i))
+		}
+		files = append(files, testFiles("home-filled.svg", "home-outline.svg")...)
+		clusters[folder] = ClusterEntry{SourceFolder: folder, Name: folder, FileNames: files}
+	}
+	tt.writeClusters(clusters)
+
+	// Many workers finishing in any order, with every step deriving data from maps
+	opts := Options{Workers: 8, Dedupe: true, GroupVariants: true, Related: true, RelatedCount: 3, NoCache: true}
+	first, _ := tt.generate(opts)
+	for run := 0; run < 3; run++ {
+		again, _ := tt.generate(opts)
+		if got, want := mustJSON(t, again), mustJSON(t, first); got != want {
+			t.Fatalf("run %d differs from the first:\n%s\nwant:\n%s", run+2, got, want)
+		}
+	}
+}
+
+func TestGenerateSameWithAnyWorkerCount(t *testing.T) {
+	tt := newTestTree(t)
+	clusters := make(map[string]ClusterEntry)
+	for _, folder := range []string{"foo bar", "foo_bar", "tabler"} {
+		var files []FileName
+		for i := 0; i < 30; i++ {
+			files = append(files, FileName{FileName: fmt.Sprintf("icon-%d.svg", i), Tags: []string{"shape", fmt.Sprintf("size-%d", i%4)}})
+			// Every other file is shared by all folders, so --dedupe folds it
+			tt.writeSVG(folder, fmt.Sprintf("icon-%d.svg", i), fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path d="M%d 0h1"/></svg>`, i%2*i))
+		}
+		files = append(files, testFiles("missing.svg", "broken.svg")...)
+		tt.writeSVG(folder, "broken.svg", `<svg xmlns="http://www.w3.org/2000/svg"><path d="M3 12h18"`)
+		clusters[folder] = ClusterEntry{SourceFolder: folder, Name: folder, FileNames: files}
+	}
+	tt.writeClusters(clusters)
+	for _, folder := range []string{"foo bar", "foo_bar", "tabler"} {
+		os.Remove(IconsDirPath(folder, "missing.svg"))
+	}
+
+	// One worker against many sharing the report, the metadata cache and the related and
+	// raster pools: the icons and every count match
+	run := func(workers int) (string, map[string]int, *Report) {
+		os.Remove(svgMetaCacheFile)
+		var progress []int
+		icons, report := tt.generate(Options{Workers: workers, Dedupe: true, Related: true, RelatedCount: 3, Rasterize: true, Progress: func(done, total int) {
+			progress = append(progress, done)
+		}})
+		for i, done := range progress {
+			if done != i+1 {
+				t.Fatalf("%d workers: progress %v, want one call per file in order", workers, progress)
+			}
+		}
+		return mustJSON(t, icons), report.warningCounts(), report
+	}
+	oneIcons, oneWarnings, one := run(1)
+	manyIcons, manyWarnings, many := run(16)
+	if manyIcons != oneIcons {
+		t.Errorf("16 workers give different icons:\n%s\nwant:\n%s", manyIcons, oneIcons)
+	}
+	if !reflect.DeepEqual(manyWarnings, oneWarnings) || many.Collisions != one.Collisions || many.Duplicates != one.Duplicates || len(many.Errors) != len(one.Errors) {
+		t.Errorf("16 workers: warnings %v, %d collisions, %d duplicates, %d errors; want %v, %d, %d, %d", manyWarnings, many.Collisions, many.Duplicates, len(many.Errors), oneWarnings, one.Collisions, one.Duplicates, len(one.Errors))
+	}
+	if one.Collisions == 0 || one.Duplicates == 0 || oneWarnings[warnMissingFile] != 3 || oneWarnings[warnRasterError] == 0 {
+		t.Errorf("1 worker: warnings %v, %d collisions, %d duplicates; want each kind of shared state exercised", oneWarnings, one.Collisions, one.Duplicates)
+	}
+}
+
+func TestGenerateNestedSubfolders(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(map[string]ClusterEntry{
+		"brands": {SourceFolder: "brands", FileNames: testFiles("social/twitter.svg", "/social//github.svg", "home.svg")},
+	})
+
+	icons, _ := tt.generate(Options{})
+	tests := []struct {
+		image string
+		id    string
+		path  string
+	}{
+		{"/svg_icons/brands/social/twitter.svg", "svg-icons-brands-social-twitter", "/freedevtools/svg_icons/brands/social/twitter/"},
+		{"/svg_icons/brands/social/github.svg", "svg-icons-brands-social-github", "/freedevtools/svg_icons/brands/social/github/"},
+		{"/svg_icons/brands/home.svg", "svg-icons-brands-home", "/freedevtools/svg_icons/brands/home/"},
+	}
+	for _, tc := range tests {
+		icon := iconByImage(t, icons, tc.image)
+		if icon.ID != tc.id || icon.Path != tc.path {
+			t.Errorf("%s: ID %s, path %s; want %s, %s", tc.image, icon.ID, icon.Path, tc.id, tc.path)
+		}
+		if strings.Contains(icon.Path, "//") || strings.Contains(icon.Image, "//") {
+			t.Errorf("%s: double slash in path %s or image %s", tc.image, icon.Path, icon.Image)
+		}
+	}
+}
+
+func TestSVGRelativePath(t *testing.T) {
+	tests := []struct {
+		fileName string
+		want     string
+	}{
+		{"home.svg", "home.svg"},
+		{"social/twitter.svg", "social/twitter.svg"},
+		{"/social//twitter.svg", "social/twitter.svg"},
+		{`social\twitter.svg`, "social/twitter.svg"},
+		{"./social/./twitter.svg", "social/twitter.svg"},
+		// A file name cannot leave its source folder
+		{"../../secret.svg", "secret.svg"},
+	}
+	for _, tc := range tests {
+		if got := svgRelativePath(tc.fileName); got != tc.want {
+			t.Errorf("svgRelativePath(%q) = %q, want %q", tc.fileName, got, tc.want)
+		}
+	}
+}
+
+func TestIconTags(t *testing.T) {
+	tests := []struct {
+		iconName string
+		want     []string
+	}{
+		{"arrow-up-circle", []string{"arrow", "up", "circle"}},
+		{"arrow_upCircle", []string{"arrow", "up", "circle"}},
+		{"parseHTMLNode", []string{"parse", "html", "node"}},
+		// Repeated words are listed once
+		{"arrow-right-arrow", []string{"arrow", "right"}},
+		{"Arrow_arrow", []string{"arrow"}},
+		{"--", nil},
+	}
+	for _, tc := range tests {
+		if got := iconTags(tc.iconName); strings.Join(got, "|") != strings.Join(tc.want, "|") {
+			t.Errorf("iconTags(%q) = %q, want %q", tc.iconName, got, tc.want)
+		}
+	}
+}
+
+func TestGenerateTags(t *testing.T) {
+	tt := newTestTree(t)
+	// The underscore of a hidden file is not a tag
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", FileNames: testFiles("arrow-up-circle.svg", "_arrow-down.svg")},
+	})
+
+	icons, _ := tt.generate(Options{})
+	if got := iconByImage(t, icons, "/svg_icons/feather/arrow-up-circle.svg").Tags; strings.Join(got, "|") != "arrow|up|circle" {
+		t.Errorf("arrow-up-circle tags = %q, want [arrow up circle]", got)
+	}
+	if got := iconByImage(t, icons, "/svg_icons/feather/_arrow-down.svg").Tags; strings.Join(got, "|") != "arrow|down" {
+		t.Errorf("_arrow-down tags = %q, want [arrow down]", got)
+	}
+}
+
+func TestFormatIconNameUnicode(t *testing.T) {
+	tests := []struct {
+		iconName string
+		want     string
+	}{
+		{"café", "Café"},
+		{"über", "Über"},
+		{"ÜBER-alles", "Über Alles"},
+		{"überCool", "Über Cool"},
+		{"привет-мир", "Привет Мир"},
+		{"ёж", "Ёж"},
+		{"日本-icon", "日本 Icon"},
+	}
+	for _, tc := range tests {
+		if got := FormatIconName(tc.iconName); got != tc.want {
+			t.Errorf("FormatIconName(%q) = %q, want %q", tc.iconName, got, tc.want)
+		}
+	}
+}
+
+func TestGenerateUnicodeNames(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(map[string]ClusterEntry{
+		"intl": {SourceFolder: "intl", FileNames: testFiles("café.svg", "привет.svg")},
+	})
+
+	icons, _ := tt.generate(Options{})
+	if cafe := iconByImage(t, icons, "/svg_icons/intl/café.svg"); cafe.Name != "Café" || cafe.ID != "svg-icons-intl-cafe" {
+		t.Errorf("café: name %q, ID %s; want Café, svg-icons-intl-cafe", cafe.Name, cafe.ID)
+	}
+	if privet := iconByImage(t, icons, "/svg_icons/intl/привет.svg"); privet.Name != "Привет" || privet.ID != "svg-icons-intl-privet" {
+		t.Errorf("привет: name %q, ID %s; want Привет, svg-icons-intl-privet", privet.Name, privet.ID)
+	}
+}
+
+func TestAriaLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"Arrow Up Circle", "", "Arrow Up Circle icon"},
+		{"GitHub Logo", "", "GitHub Logo icon"},
+		{"Home", "Go to the home page", "Go to the home page"},
+	}
+	for _, tc := range tests {
+		if got := ariaLabel(tc.name, tc.title); got != tc.want {
+			t.Errorf("ariaLabel(%q, %q) = %q, want %q", tc.name, tc.title, got, tc.want)
+		}
+	}
+}
+
+func TestGenerateAriaLabel(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeSVG("feather", "home.svg", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><title>
+		Back to   home
+	</title><path d="M3 12h18"/></svg>`)
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", FileNames: testFiles("home.svg", "arrow-up-circle.svg")},
+	})
+
+	icons, _ := tt.generate(Options{})
+	// The embedded title wins, flattened to one line
+	if got := iconByImage(t, icons, "/svg_icons/feather/home.svg").AriaLabel; got != "Back to home" {
+		t.Errorf("home ariaLabel = %q, want the SVG title", got)
+	}
+	if got := iconByImage(t, icons, "/svg_icons/feather/arrow-up-circle.svg").AriaLabel; got != "Arrow Up Circle icon" {
+		t.Errorf("arrow-up-circle ariaLabel = %q, want Arrow Up Circle icon", got)
+	}
+}
+
+func TestGenerateAsLibrary(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeSVG("feather", "home.svg", testSVG)
+	tt.writeSVG("material", "cog.svg", testSVG)
+	data, err := json.Marshal(Cluster{Clusters: map[string]ClusterEntry{
+		"feather":  {SourceFolder: "feather", FileNames: []FileName{{FileName: "home.svg", Description: "A house"}}},
+		"material": {SourceFolder: "material", FileNames: testFiles("cog.svg")},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusterPath := tt.writeFile(filepath.Join(tt.root, "tool", "clusters.json"), string(data))
+
+	// An embedding tool picks its own cluster file and caches, and follows progress
+	cacheDir := filepath.Join(tt.root, "cache")
+	var progress []int
+	icons, report, err := Generate(context.Background(), Options{
+		ClusterPaths: []string{clusterPath},
+		CacheDir:     cacheDir,
+		Folder:       "feather",
+		Workers:      1,
+		Progress:     func(done, total int) { progress = append(progress, done, total) },
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(icons) != 1 || icons[0].ID != "svg-icons-feather-home" || icons[0].Name != "Home" {
+		t.Errorf("icons = %+v, want only the feather home icon", icons)
+	}
+	if report.IconsPerFolder["feather"] != 1 || len(report.Warnings) != 0 {
+		t.Errorf("report folders %v, warnings %v; want one feather icon and no warnings", report.IconsPerFolder, report.Warnings)
+	}
+	if len(progress) != 2 || progress[0] != 1 || progress[1] != 1 {
+		t.Errorf("progress = %v, want [1 1]", progress)
+	}
+	if entries, err := os.ReadDir(cacheDir); err != nil || len(entries) == 0 {
+		t.Errorf("cache directory entries = %v, %v; want the caches written there", entries, err)
+	}
+
+	// Nothing is written by a dry run
+	dryCache := filepath.Join(tt.root, "dry-cache")
+	if _, _, err := Generate(context.Background(), Options{ClusterPaths: []string{clusterPath}, CacheDir: dryCache, DryRun: true}); err != nil {
+		t.Fatalf("Generate with DryRun: %v", err)
+	}
+	if _, err := os.Stat(dryCache); !os.IsNotExist(err) {
+		t.Errorf("DryRun created %s", dryCache)
+	}
+}
+
+func TestCleanText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"  An arrow\tpointing\n\n  up  ", "An arrow pointing up"},
+		{"Line one\r\nLine two", "Line one Line two"},
+		{"Bell\x00\x07 ring ing", "Bell ring ing"},
+		{"Café  «über»  日本", "Café «über» 日本"},
+		{"\n\t ", ""},
+	}
+	for _, tc := range tests {
+		if got := cleanText(tc.text); got != tc.want {
+			t.Errorf("cleanText(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestGenerateFlattensDescriptions(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", FileNames: []FileName{{
+			FileName:    "home.svg",
+			Description: "  A house\twith a chimney.\n\n    Use it for   the home page.\r\n",
+		}}},
+	})
+
+	icons, _ := tt.generate(Options{})
+	home := iconByImage(t, icons, "/svg_icons/feather/home.svg")
+	if want := "A house with a chimney. Use it for the home page."; home.Description != want {
+		t.Errorf("description = %q, want %q", home.Description, want)
+	}
+}
+
+func TestCategoryIDFromPath(t *testing.T) {
+	tests := []struct {
+		path, basePath, prefix string
+		want                   string
+	}{
+		{"/freedevtools/svg_icons/feather/home/", "/freedevtools/svg_icons/", "svg-icons", "svg-icons-feather-home"},
+		{"/freedevtools/png_icons/feather/home/", "/freedevtools/png_icons/", "png-icons", "png-icons-feather-home"},
+		{"/freedevtools/emojis/smileys/grinning-face/", "/freedevtools/emojis/", "emoji", "emoji-smileys-grinning-face"},
+		{"/freedevtools/png_icons/brands/social/twitter/", "/freedevtools/png_icons/", "png-icons", "png-icons-brands-social-twitter"},
+		{"/freedevtools/svg_icons/foo bar/café/", "/freedevtools/svg_icons/", "svg-icons", "svg-icons-foo_bar-cafe"},
+		{"/freedevtools/svg_icons/feather/home/", "/freedevtools/svg_icons/", "", "feather-home"},
+	}
+	for _, tc := range tests {
+		if got := CategoryIDFromPath(tc.path, tc.basePath, tc.prefix); got != tc.want {
+			t.Errorf("CategoryIDFromPath(%q, %q, %q) = %q, want %q", tc.path, tc.basePath, tc.prefix, got, tc.want)
+		}
+	}
+	// The SVG wrapper keeps its historical output
+	if got := IconIDFromPath("/freedevtools/svg_icons/feather/home/"); got != "svg-icons-feather-home" {
+		t.Errorf("IconIDFromPath = %q, want svg-icons-feather-home", got)
+	}
+}
+
+// BenchmarkIconIDFromPath measures deriving the ID of an icon page, done for every icon of
+// a 40k icon catalog
+func BenchmarkIconIDFromPath(b *testing.B) {
+	paths := []string{
+		"/freedevtools/svg_icons/feather/home/",
+		"/freedevtools/svg_icons/material-design/arrow_upward_circle/",
+		"/freedevtools/svg_icons/flags/côte d'ivoire/",
+	}
+	for i := 0; i < b.N; i++ {
+		IconIDFromPath(paths[i%len(paths)])
+	}
+}
+
+func TestGenerateAttribution(t *testing.T) {
+	tt := newTestTree(t)
+	clusters := map[string]ClusterEntry{
+		"feather": {
+			SourceFolder: "feather",
+			Author:       " Cole  Bemis ",
+			License:      "MIT",
+			SourceURL:    "https://feathericons.com",
+			FileNames:    testFiles("home.svg", "bell.svg"),
+		},
+		"plain": {SourceFolder: "plain", FileNames: testFiles("cog.svg")},
+	}
+	tt.writeClusters(clusters)
+
+	icons, _ := tt.generate(Options{Incremental: true})
+	for _, image := range []string{"/svg_icons/feather/home.svg", "/svg_icons/feather/bell.svg"} {
+		icon := iconByImage(t, icons, image)
+		if icon.Collection != "Cole Bemis" || icon.License != "MIT" || icon.LicenseURL != "https://feathericons.com" {
+			t.Errorf("%s attribution = %q, %q, %q; want the cluster's", image, icon.Collection, icon.License, icon.LicenseURL)
+		}
+	}
+	if cog := iconByImage(t, icons, "/svg_icons/plain/cog.svg"); cog.Collection != "" || cog.License != "" || cog.LicenseURL != "" {
+		t.Errorf("cog attribution = %q, %q, %q; want empty", cog.Collection, cog.License, cog.LicenseURL)
+	}
+
+	// A changed license reaches icons reused by an incremental run
+	feather := clusters["feather"]
+	feather.License = "Apache-2.0"
+	clusters["feather"] = feather
+	tt.writeClusters(clusters)
+	icons, _ = tt.generate(Options{Incremental: true})
+	if home := iconByImage(t, icons, "/svg_icons/feather/home.svg"); home.License != "Apache-2.0" {
+		t.Errorf("license after the change = %q, want Apache-2.0", home.License)
+	}
+}
+
+func TestIconKeywords(t *testing.T) {
+	got := iconKeywords([]string{" settings ", "Gear", "", "gear", "control\tpanel"})
+	if strings.Join(got, "|") != "settings|Gear|control panel" {
+		t.Errorf("iconKeywords = %q, want [settings Gear control panel]", got)
+	}
+	if got := iconKeywords(nil); got != nil {
+		t.Errorf("iconKeywords(nil) = %q, want nil", got)
+	}
+}
+
+func TestGenerateKeywords(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", FileNames: []FileName{
+			{FileName: "cog.svg", Keywords: []string{"settings", "gear"}},
+			{FileName: "arrow-up.svg"},
+		}},
+	})
+
+	icons, _ := tt.generate(Options{})
+	if got := iconByImage(t, icons, "/svg_icons/feather/cog.svg").Keywords; strings.Join(got, "|") != "settings|gear" {
+		t.Errorf("cog keywords = %q, want [settings gear]", got)
+	}
+	// Without authored keywords the file name tags are still there to search
+	arrow := iconByImage(t, icons, "/svg_icons/feather/arrow-up.svg")
+	if len(arrow.Keywords) != 0 || strings.Join(arrow.Tags, "|") != "arrow|up" {
+		t.Errorf("arrow-up keywords %q, tags %q; want none and [arrow up]", arrow.Keywords, arrow.Tags)
+	}
+}
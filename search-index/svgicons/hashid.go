@@ -0,0 +1,64 @@
+package svgicons
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"strings"
+)
+
+// ID styles accepted by Options.IDStyle
+const (
+	IDStyleSlug = "slug" // Readable IDs derived from the icon path, e.g. svg-icons-feather-home
+	IDStyleHash = "hash" // svgIconsIDPrefix plus a short hash of the icon path, e.g. svg-icons-k3v7q2m9xa
+)
+
+// IDStyles lists every ID style, the values accepted by --id-style
+var IDStyles = []string{IDStyleSlug, IDStyleHash}
+
+// hashIDLength is the number of base32 characters of a hash ID, 50 bits of the SHA-256
+const hashIDLength = 10
+
+// hashIDEncoding is lowercase base32 without padding, so hash IDs are URL and ID safe
+var hashIDEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// HashIconID derives the short ID of --id-style hash from an icon's page path,
+// e.g. /freedevtools/svg_icons/feather/home/ gives svg-icons- and 10 base32 characters
+func HashIconID(path string) string {
+	return prefixedHashIconID(path, svgIconsIDPrefix)
+}
+
+// hashIDSum hashes the page path of a hash ID, replaced by tests to force collisions
+var hashIDSum = sha256.Sum256
+
+// prefixedHashIconID is HashIconID under the id_prefix of a cluster instead of svgIconsIDPrefix
+func prefixedHashIconID(path, prefix string) string {
+	sum := hashIDSum([]byte(path))
+	return fmt.Sprintf("%s-%s", prefix, hashIDEncoding.EncodeToString(sum[:])[:hashIDLength])
+}
+
+// applyHashIDs replaces the IDs of the icons by the hash of their path. Icons listed more
+// than once share a path and so an ID, which resolveDuplicateIDs handles like any other
+// duplicate, but two different paths with the same hash would silently swap icons between
+// runs, so that is an error. prefixes maps images to the id_prefix of their cluster, used
+// instead of svgIconsIDPrefix.
+func applyHashIDs(icons []Icon, prefixes map[string]string) error {
+	paths := make(map[string]string, len(icons)) // Hash ID to the path it was derived from
+	for i := range icons {
+		id := HashIconID(icons[i].Path)
+		if prefix := prefixes[icons[i].Image]; prefix != "" {
+			id = prefixedHashIconID(icons[i].Path, prefix)
+		}
+		if other, ok := paths[id]; ok && other != icons[i].Path {
+			return fmt.Errorf("hash ID %s collides for %s and %s, use --id-style %s", id, other, icons[i].Path, IDStyleSlug)
+		}
+		paths[id] = icons[i].Path
+		icons[i].ID = id
+	}
+	return nil
+}
+
+// validIDStyle reports whether style is one of IDStyles, the empty string meaning slug
+func validIDStyle(style string) bool {
+	return style == "" || ContainsString(IDStyles, strings.ToLower(style))
+}
@@ -0,0 +1,53 @@
+package svgicons
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+)
+
+func TestHashIconID(t *testing.T) {
+	// Hash IDs end up in URLs, so they must never change for a path
+	if got := HashIconID("/freedevtools/svg_icons/feather/home/"); got != "svg-icons-n2rjtjw55y" {
+		t.Errorf("HashIconID = %s, want svg-icons-n2rjtjw55y", got)
+	}
+	if a, b := HashIconID("/freedevtools/svg_icons/feather/home/"), HashIconID("/freedevtools/svg_icons/feather/bell/"); a == b {
+		t.Errorf("home and bell both have hash ID %s", a)
+	}
+	if got := prefixedHashIconID("/freedevtools/svg_icons/feather/home/", "fa"); got != "fa-n2rjtjw55y" {
+		t.Errorf("prefixedHashIconID = %s, want fa-n2rjtjw55y", got)
+	}
+}
+
+func TestApplyHashIDs(t *testing.T) {
+	icons := []Icon{
+		{Path: "/freedevtools/svg_icons/feather/home/", Image: "/svg_icons/feather/home.svg"},
+		{Path: "/freedevtools/svg_icons/fontawesome/home/", Image: "/svg_icons/fontawesome/home.svg"},
+		// Listed twice, left for resolveDuplicateIDs
+		{Path: "/freedevtools/svg_icons/feather/home/", Image: "/svg_icons/feather/home.svg"},
+	}
+	if err := applyHashIDs(icons, map[string]string{"/svg_icons/fontawesome/home.svg": "fa"}); err != nil {
+		t.Fatalf("applyHashIDs: %v", err)
+	}
+	if icons[0].ID != "svg-icons-n2rjtjw55y" || icons[2].ID != icons[0].ID || !strings.HasPrefix(icons[1].ID, "fa-") {
+		t.Errorf("IDs = %s, %s, %s; want the feather hash twice and a fa- hash", icons[0].ID, icons[1].ID, icons[2].ID)
+	}
+}
+
+func TestApplyHashIDsCollision(t *testing.T) {
+	// Paths starting alike hash the same, as two real paths could once truncated
+	previous := hashIDSum
+	defer func() { hashIDSum = previous }()
+	hashIDSum = func(data []byte) [sha256.Size]byte {
+		return sha256.Sum256(data[:len("/freedevtools/svg_icons/feather/")])
+	}
+
+	icons := []Icon{
+		{Path: "/freedevtools/svg_icons/feather/home/"},
+		{Path: "/freedevtools/svg_icons/feather/bell/"},
+	}
+	err := applyHashIDs(icons, nil)
+	if err == nil || !strings.Contains(err.Error(), "/freedevtools/svg_icons/feather/home/ and /freedevtools/svg_icons/feather/bell/") {
+		t.Errorf("applyHashIDs of colliding paths = %v, want an error naming both", err)
+	}
+}
@@ -0,0 +1,28 @@
+package svgicons
+
+import (
+	"path"
+	"strings"
+)
+
+// What Options.Hidden does with hidden SVG files, whose name starts with an underscore to mark
+// an internal or system icon, e.g. _spinner.svg
+const (
+	HiddenStrip  = "strip"  // Index them like any other file, the underscore dropped from the name and detail path, the default
+	HiddenSkip   = "skip"   // Leave them out of the output, counted in the report
+	HiddenSystem = "system" // Index them with System set, keeping the underscore in the detail path and ID
+)
+
+// HiddenPolicies lists every handling of hidden files, the values accepted by --hidden
+var HiddenPolicies = []string{HiddenStrip, HiddenSkip, HiddenSystem}
+
+// validHidden reports whether hidden is one of HiddenPolicies, the empty string meaning strip
+func validHidden(hidden string) bool {
+	return hidden == "" || ContainsString(HiddenPolicies, strings.ToLower(hidden))
+}
+
+// isHiddenFile reports whether the base name of a cluster file starts with an underscore,
+// e.g. icons/_spinner.svg
+func isHiddenFile(fileName string) bool {
+	return strings.HasPrefix(path.Base(svgRelativePath(fileName)), "_")
+}
@@ -0,0 +1,117 @@
+package svgicons
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// idMapFile records the ID assigned to every icon so IDs survive folder and file renames.
+// It is kept in Options.CacheDir, next to the generated data it belongs to.
+const idMapFile = "id_map.json"
+
+// idMapEntry is the ID previously assigned to an icon along with its content hash.
+// Inherited marks IDs carried over from an icon's old path, which stay pinned to the new path.
+type idMapEntry struct {
+	ID        string `json:"id"`
+	Hash      string `json:"hash,omitempty"`
+	Inherited bool   `json:"inherited,omitempty"`
+}
+
+// idMap maps an icon's image path to the ID it was assigned
+type idMap map[string]idMapEntry
+
+// loadIDMap reads the ID map written by the previous run in dir, or returns an empty map on the first run
+func loadIDMap(dir string) (idMap, error) {
+	filePath := filepath.Join(dir, idMapFile)
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return idMap{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	m := idMap{}
+	if err := json.Unmarshal(content, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return m, nil
+}
+
+// apply replaces freshly derived IDs with previously assigned ones.
+// Icons at a new path reuse the ID of a vanished icon with the same content, and icons that
+// inherited an ID in an earlier run keep it. Returns the image paths of icons with an inherited ID.
+func (m idMap) apply(icons []Icon, contentHashes map[string]string) map[string]bool {
+	current := make(map[string]bool, len(icons))
+	for _, icon := range icons {
+		current[icon.Image] = true
+	}
+
+	// Index the entries whose image no longer exists by content hash, in a stable order
+	var vanished []string
+	for image, entry := range m {
+		if !current[image] && entry.Hash != "" {
+			vanished = append(vanished, image)
+		}
+	}
+	sort.Strings(vanished)
+
+	byHash := make(map[string][]string)
+	for _, image := range vanished {
+		hash := m[image].Hash
+		byHash[hash] = append(byHash[hash], image)
+	}
+
+	inherited := make(map[string]bool)
+	for i := range icons {
+		if entry, ok := m[icons[i].Image]; ok {
+			if entry.Inherited {
+				icons[i].ID = entry.ID
+				inherited[icons[i].Image] = true
+			}
+			continue
+		}
+
+		hash := contentHashes[icons[i].Image]
+		if candidates := byHash[hash]; hash != "" && len(candidates) > 0 {
+			icons[i].ID = m[candidates[0]].ID
+			byHash[hash] = candidates[1:]
+			inherited[icons[i].Image] = true
+		}
+	}
+	return inherited
+}
+
+// newIDMap builds the ID map for the icons generated in this run
+func newIDMap(icons []Icon, contentHashes map[string]string, inherited map[string]bool) idMap {
+	m := make(idMap, len(icons))
+	for _, icon := range icons {
+		m[icon.Image] = idMapEntry{
+			ID:        icon.ID,
+			Hash:      contentHashes[icon.Image],
+			Inherited: inherited[icon.Image],
+		}
+	}
+	return m
+}
+
+// saveIDMap atomically replaces the ID map file in dir
+func saveIDMap(dir string, m idMap) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeCacheFile(dir, idMapFile, append(data, '\n'))
+}
+
+// hashContent returns the hex encoded SHA-256 of the content
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
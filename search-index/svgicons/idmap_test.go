@@ -0,0 +1,34 @@
+package svgicons
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIDMapKeptInCacheDir(t *testing.T) {
+	tt := newTestTree(t)
+	cacheDir := filepath.Join(tt.root, "output")
+	tt.writeClusters(map[string]ClusterEntry{"feather": {SourceFolder: "feather", FileNames: testFiles("home.svg")}})
+	tt.generate(Options{CacheDir: cacheDir})
+
+	// The map lives with the generated data, never in the working directory
+	if _, err := os.Stat(filepath.Join(cacheDir, idMapFile)); err != nil {
+		t.Errorf("ID map in the cache directory: %v", err)
+	}
+	if _, err := os.Stat(idMapFile); !os.IsNotExist(err) {
+		t.Errorf("%s written to the working directory", idMapFile)
+	}
+
+	// A renamed icon keeps its ID through the map of its cache directory only
+	if err := os.Rename(IconsDirPath("feather", "home.svg"), IconsDirPath("feather", "house.svg")); err != nil {
+		t.Fatal(err)
+	}
+	tt.writeClusters(map[string]ClusterEntry{"feather": {SourceFolder: "feather", FileNames: testFiles("house.svg")}})
+	if icons, _ := tt.generate(Options{CacheDir: filepath.Join(tt.root, "other"), DryRun: true}); icons[0].ID != "svg-icons-feather-house" {
+		t.Errorf("ID with another cache directory = %s, want the derived svg-icons-feather-house", icons[0].ID)
+	}
+	if icons, _ := tt.generate(Options{CacheDir: cacheDir}); icons[0].ID != "svg-icons-feather-home" {
+		t.Errorf("ID of the renamed icon = %s, want the inherited svg-icons-feather-home", icons[0].ID)
+	}
+}
@@ -0,0 +1,97 @@
+package svgicons
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// idPrefixPattern matches the id_prefix of a cluster once its trailing hyphen is removed:
+// lowercase ASCII words joined by single hyphens, e.g. fa or material-symbols
+var idPrefixPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// normalizeIDPrefix returns the id_prefix of a cluster without surrounding spaces and its
+// optional trailing hyphen, so "fa-" and "fa" are the same prefix
+func normalizeIDPrefix(prefix string) string {
+	return strings.TrimSuffix(strings.TrimSpace(prefix), "-")
+}
+
+// validIDPrefix reports whether an id_prefix can start an icon ID as is, which SanitizeID
+// would otherwise rewrite
+func validIDPrefix(prefix string) bool {
+	return idPrefixPattern.MatchString(normalizeIDPrefix(prefix))
+}
+
+// ClusterIconID derives the ID of an icon of a cluster with an id_prefix from its page path:
+// the prefix replaces both svgIconsIDPrefix and the source folder, e.g.
+// /freedevtools/svg_icons/fontawesome/home/ of source folder fontawesome with prefix fa
+// gives fa-home. An empty prefix gives the default IconIDFromPath.
+func ClusterIconID(path, sourceFolder, prefix string) string {
+	prefix = normalizeIDPrefix(prefix)
+	if prefix == "" {
+		return IconIDFromPath(path)
+	}
+	return CategoryIDFromPath(path, svgIconsBasePath+sourceFolder+"/", prefix)
+}
+
+// clusterIDPrefixes returns the id_prefix of every cluster setting one, by cluster key
+func clusterIDPrefixes(cluster Cluster) map[string]string {
+	prefixes := make(map[string]string)
+	for key, entry := range cluster.Clusters {
+		if prefix := normalizeIDPrefix(entry.IDPrefix); prefix != "" {
+			prefixes[key] = prefix
+		}
+	}
+	return prefixes
+}
+
+// checkIDPrefixes records an id-prefix-conflict warning for every id_prefix used by clusters
+// of different source folders, whose icons of the same name would get the same ID, and for
+// every id_prefix in the svgIconsIDPrefix namespace, which can give the ID of an icon of a
+// cluster without one. Returns the number of warnings.
+func checkIDPrefixes(cluster Cluster, prefixes map[string]string, report *Report) int {
+	keys := make([]string, 0, len(prefixes))
+	for key := range prefixes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	conflicts := 0
+	byPrefix := make(map[string]string) // Prefix to the first cluster key using it
+	for _, key := range keys {
+		prefix := prefixes[key]
+		if prefix == svgIconsIDPrefix || strings.HasPrefix(prefix, svgIconsIDPrefix+"-") {
+			w := newSVGWarning(warnIDPrefixConflict, key, "Cluster %s has id_prefix %s, which can clash with the default %s- IDs of other clusters", key, prefix, svgIconsIDPrefix)
+			w.Cluster = key
+			report.record(w)
+			conflicts++
+		}
+		other, ok := byPrefix[prefix]
+		if !ok {
+			byPrefix[prefix] = key
+			continue
+		}
+		if cluster.Clusters[other].SourceFolder != cluster.Clusters[key].SourceFolder {
+			w := newSVGWarning(warnIDPrefixConflict, key, "Clusters %s and %s both have id_prefix %s, icons with the same name in %s and %s get the same ID", other, key, prefix, cluster.Clusters[other].SourceFolder, cluster.Clusters[key].SourceFolder)
+			w.Cluster = key
+			report.record(w)
+			conflicts++
+		}
+	}
+	return conflicts
+}
+
+// applyIDPrefixes replaces the IDs of the icons of clusters with an id_prefix by their
+// ClusterIconID. prefixes maps images to the id_prefix of their cluster, sourceFolders to
+// their source folder. Icons are prefixed once generated, like applyHashIDs, so the caches
+// and IDs of other clusters do not depend on the prefixes.
+func applyIDPrefixes(icons []Icon, prefixes, sourceFolders map[string]string) int {
+	prefixed := 0
+	for i := range icons {
+		if prefix := prefixes[icons[i].Image]; prefix != "" {
+			icons[i].ID = ClusterIconID(icons[i].Path, sourceFolders[icons[i].Image], prefix)
+			prefixed++
+		}
+	}
+	return prefixed
+}
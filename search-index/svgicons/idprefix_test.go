@@ -0,0 +1,91 @@
+package svgicons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidIDPrefix(t *testing.T) {
+	for _, prefix := range []string{"fa", "fa-", " fa ", "material-symbols", "mdi2"} {
+		if !validIDPrefix(prefix) {
+			t.Errorf("validIDPrefix(%q) = false, want true", prefix)
+		}
+	}
+	for _, prefix := range []string{"FA", "fa--x", "-fa", "fa_x", "fa icons", "café", "-"} {
+		if validIDPrefix(prefix) {
+			t.Errorf("validIDPrefix(%q) = true, want false", prefix)
+		}
+	}
+}
+
+func TestClusterIconID(t *testing.T) {
+	tests := []struct {
+		path, sourceFolder, prefix string
+		want                       string
+	}{
+		{"/freedevtools/svg_icons/fontawesome/home/", "fontawesome", "fa", "fa-home"},
+		{"/freedevtools/svg_icons/fontawesome/home/", "fontawesome", "fa-", "fa-home"},
+		{"/freedevtools/svg_icons/fontawesome/brands/github/", "fontawesome", "fa", "fa-brands-github"},
+		{"/freedevtools/svg_icons/fontawesome/home/", "fontawesome", "", "svg-icons-fontawesome-home"},
+	}
+	for _, tc := range tests {
+		if got := ClusterIconID(tc.path, tc.sourceFolder, tc.prefix); got != tc.want {
+			t.Errorf("ClusterIconID(%q, %q, %q) = %q, want %q", tc.path, tc.sourceFolder, tc.prefix, got, tc.want)
+		}
+	}
+}
+
+func TestCheckIDPrefixes(t *testing.T) {
+	cluster := Cluster{Clusters: map[string]ClusterEntry{
+		"fa-solid":   {SourceFolder: "fontawesome", IDPrefix: "fa"},
+		"fa-regular": {SourceFolder: "fontawesome", IDPrefix: "fa-"},
+		"fluent":     {SourceFolder: "fluent", IDPrefix: "fa"},
+		"own":        {SourceFolder: "own", IDPrefix: "svg-icons-own"},
+		"feather":    {SourceFolder: "feather"},
+	}}
+	report := &Report{}
+	if got := checkIDPrefixes(cluster, clusterIDPrefixes(cluster), report); got != 2 {
+		t.Errorf("checkIDPrefixes = %d, want 2", got)
+	}
+
+	// Two clusters of the same folder may share a prefix, other folders may not
+	var messages []string
+	for _, w := range warningsOfKind(report, warnIDPrefixConflict) {
+		messages = append(messages, w.Cluster+": "+w.Message)
+	}
+	joined := strings.Join(messages, "\n")
+	if !strings.Contains(joined, "fluent: Clusters fa-regular and fluent both have id_prefix fa") {
+		t.Errorf("warnings:\n%s\nwant fluent clashing with fontawesome", joined)
+	}
+	if !strings.Contains(joined, "own: Cluster own has id_prefix svg-icons-own") {
+		t.Errorf("warnings:\n%s\nwant own clashing with the default namespace", joined)
+	}
+}
+
+func TestGenerateIDPrefixes(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(map[string]ClusterEntry{
+		"fa":      {SourceFolder: "fontawesome", IDPrefix: "fa", FileNames: testFiles("home.svg", "brands/github.svg")},
+		"feather": {SourceFolder: "feather", FileNames: testFiles("home.svg")},
+	})
+
+	for _, style := range []string{IDStyleSlug, IDStyleHash} {
+		icons, report := tt.generate(Options{IDStyle: style})
+		home := iconByImage(t, icons, "/svg_icons/fontawesome/home.svg")
+		github := iconByImage(t, icons, "/svg_icons/fontawesome/brands/github.svg")
+		feather := iconByImage(t, icons, "/svg_icons/feather/home.svg")
+		switch style {
+		case IDStyleSlug:
+			if home.ID != "fa-home" || github.ID != "fa-brands-github" || feather.ID != "svg-icons-feather-home" {
+				t.Errorf("slug IDs = %s, %s, %s; want fa-home, fa-brands-github, svg-icons-feather-home", home.ID, github.ID, feather.ID)
+			}
+		case IDStyleHash:
+			if !strings.HasPrefix(home.ID, "fa-") || len(home.ID) != len("fa-")+hashIDLength || !strings.HasPrefix(feather.ID, "svg-icons-") {
+				t.Errorf("hash IDs = %s, %s; want fa- and svg-icons- hashes", home.ID, feather.ID)
+			}
+		}
+		if warnings := warningsOfKind(report, warnIDPrefixConflict); len(warnings) != 0 {
+			t.Errorf("%s: id-prefix-conflict warnings %+v, want none", style, warnings)
+		}
+	}
+}
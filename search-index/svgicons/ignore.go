@@ -0,0 +1,69 @@
+package svgicons
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// IgnoreFile is an optional list of globs, one per line, of the source folders and SVG files
+// to leave out of the output, e.g. deprecated icons still in the source tree
+const IgnoreFile = ".svgignore"
+
+// ignorePatterns holds the globs loaded from IgnoreFile
+var ignorePatterns []string
+
+// LoadIgnore reads the globs of a .svgignore file into ignorePatterns, applied by Generate.
+// Blank lines and lines starting with # are skipped. A missing file is not an error: nothing
+// is ignored then.
+func LoadIgnore(filePath string) error {
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		pattern := strings.TrimSuffix(strings.TrimSpace(scanner.Text()), "/")
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s:%d: invalid pattern %q: %w", filePath, line, pattern, err)
+		}
+		ignorePatterns = append(ignorePatterns, pattern)
+	}
+	return scanner.Err()
+}
+
+// ignoreMatch reports whether any ignore pattern matches one of the names
+func ignoreMatch(names ...string) bool {
+	for _, pattern := range ignorePatterns {
+		for _, name := range names {
+			if matched, _ := path.Match(pattern, name); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// folderIgnored reports whether a whole source folder is ignored, e.g. by deprecated or old-*
+func folderIgnored(sourceFolder string) bool {
+	return ignoreMatch(sourceFolder)
+}
+
+// fileIgnored reports whether a cluster file is ignored: a pattern matches its file name
+// (old-*.svg), its path within the source folder (social/twitter.svg) or its path with the
+// source folder (feather/x-*.svg)
+func fileIgnored(sourceFolder, fileName string) bool {
+	relPath := svgRelativePath(fileName)
+	return ignoreMatch(path.Base(relPath), relPath, sourceFolder+"/"+relPath)
+}
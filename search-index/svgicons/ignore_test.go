@@ -0,0 +1,105 @@
+package svgicons
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useIgnore loads a .svgignore holding content until the test ends
+func useIgnore(t *testing.T, content string) {
+	t.Helper()
+	previous := ignorePatterns
+	t.Cleanup(func() { ignorePatterns = previous })
+	ignorePatterns = nil
+
+	filePath := filepath.Join(t.TempDir(), IgnoreFile)
+	if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadIgnore(filePath); err != nil {
+		t.Fatalf("LoadIgnore: %v", err)
+	}
+}
+
+func TestIgnoreMatching(t *testing.T) {
+	useIgnore(t, `
+# Deprecated sets
+deprecated/
+old-*
+
+x-*.svg
+social/twitter.svg
+feather/bell*.svg
+`)
+
+	folders := []struct {
+		folder string
+		want   bool
+	}{
+		{"deprecated", true},
+		{"old-material", true},
+		{"feather", false},
+		{"x-set", false},
+	}
+	for _, tc := range folders {
+		if got := folderIgnored(tc.folder); got != tc.want {
+			t.Errorf("folderIgnored(%q) = %v, want %v", tc.folder, got, tc.want)
+		}
+	}
+
+	files := []struct {
+		folder, fileName string
+		want             bool
+	}{
+		{"feather", "x-circle.svg", true},
+		{"material", "icons/x-circle.svg", true},
+		{"brands", "social/twitter.svg", true},
+		{"brands", "/social//twitter.svg", true},
+		{"brands", "twitter.svg", false},
+		{"feather", "bell-off.svg", true},
+		{"material", "bell-off.svg", false},
+		{"feather", "home.svg", false},
+	}
+	for _, tc := range files {
+		if got := fileIgnored(tc.folder, tc.fileName); got != tc.want {
+			t.Errorf("fileIgnored(%q, %q) = %v, want %v", tc.folder, tc.fileName, got, tc.want)
+		}
+	}
+}
+
+func TestLoadIgnoreErrors(t *testing.T) {
+	previous := ignorePatterns
+	defer func() { ignorePatterns = previous }()
+	ignorePatterns = nil
+
+	if err := LoadIgnore(filepath.Join(t.TempDir(), IgnoreFile)); err != nil || len(ignorePatterns) != 0 {
+		t.Errorf("LoadIgnore of a missing file = %v with patterns %v, want nothing ignored", err, ignorePatterns)
+	}
+
+	filePath := filepath.Join(t.TempDir(), IgnoreFile)
+	if err := ioutil.WriteFile(filePath, []byte("home.svg\n[x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadIgnore(filePath); err == nil || !strings.Contains(err.Error(), IgnoreFile+":2:") {
+		t.Errorf("LoadIgnore of a bad pattern = %v, want an error naming line 2", err)
+	}
+}
+
+func TestGenerateIgnore(t *testing.T) {
+	tt := newTestTree(t)
+	useIgnore(t, "deprecated\nold-*.svg\n")
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather":    {SourceFolder: "feather", FileNames: testFiles("home.svg", "old-home.svg")},
+		"deprecated": {SourceFolder: "deprecated", FileNames: testFiles("bell.svg", "cog.svg")},
+	})
+
+	icons, report := tt.generate(Options{})
+	if len(icons) != 1 || icons[0].Image != "/svg_icons/feather/home.svg" {
+		t.Errorf("icons = %+v, want only feather/home.svg", icons)
+	}
+	if report.Ignored != 3 {
+		t.Errorf("Ignored = %d, want the old file and both files of the ignored folder", report.Ignored)
+	}
+}
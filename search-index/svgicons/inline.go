@@ -0,0 +1,50 @@
+package svgicons
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"strings"
+)
+
+// svgDataURIPrefix starts the DataURI of every inlined icon
+const svgDataURIPrefix = "data:image/svg+xml;base64,"
+
+// minifySVG collapses whitespace and drops it between tags, which is enough to shrink
+// hand-formatted icons without changing how they render
+func minifySVG(content []byte) []byte {
+	return bytes.ReplaceAll(normalizeSVGWhitespace(content), []byte("> <"), []byte("><"))
+}
+
+// inlineSVGIcons sets DataURI on each icon whose minified SVG is at most maxBytes long,
+// or on every icon when maxBytes is 0. With optimize the SVG is passed through optimizeSVG first.
+// Icons whose file cannot be read were already reported while processing them and are left
+// without a DataURI. Returns the number of icons inlined.
+func inlineSVGIcons(icons []Icon, maxBytes int, optimize bool) int {
+	inlined := 0
+	for i := range icons {
+		content, err := ioutil.ReadFile(SourceFile(icons[i]))
+		if err != nil {
+			continue
+		}
+		if optimize {
+			if optimized, err := optimizeSVG(content); err == nil {
+				content = optimized
+			}
+		}
+
+		minified := minifySVG(content)
+		if maxBytes > 0 && len(minified) > maxBytes {
+			continue
+		}
+		icons[i].DataURI = svgDataURIPrefix + base64.StdEncoding.EncodeToString(minified)
+		inlined++
+	}
+	return inlined
+}
+
+// SourceFile returns the SVG file an icon was generated from, in IconsDir unless OpenSources
+// opened another source for its folder
+func SourceFile(icon Icon) string {
+	return iconsFile(strings.TrimPrefix(icon.Image, "/svg_icons/"))
+}
@@ -0,0 +1,130 @@
+package svgicons
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LockFile records the SHA-256 of every indexed SVG file, written by Options.UpdateLock and
+// checked by Options.Verify. It is meant to be committed next to the cluster files.
+const LockFile = "svg.lock"
+
+// lockHeader starts the lock file, the lines after it are "<sha256>  <image>" sorted by image
+const lockHeader = "# SHA-256 of every indexed SVG icon, regenerate with --update-lock after vetting changes\n"
+
+// svgLock maps an icon image path such as /svg_icons/feather/home.svg to the hash of its file
+type svgLock map[string]string
+
+// loadLock reads a lock file, failing when it does not exist since --verify needs one
+func loadLock(filePath string) (svgLock, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s not found, create it with --update-lock", filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	lock := svgLock{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 || len(fields[0]) != 64 {
+			return nil, fmt.Errorf("%s:%d: expected \"<sha256>  <image>\", got %q", filePath, line, text)
+		}
+		lock[fields[1]] = fields[0]
+	}
+	return lock, scanner.Err()
+}
+
+// saveLock atomically replaces the lock file with one line per image in sorted order, so it
+// is the same on every run over the same files and diffs show the changed icons only
+func saveLock(filePath string, lock svgLock) error {
+	images := make([]string, 0, len(lock))
+	for image := range lock {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+
+	var b strings.Builder
+	b.WriteString(lockHeader)
+	for _, image := range images {
+		fmt.Fprintf(&b, "%s  %s\n", lock[image], image)
+	}
+	return writeFileAtomic(filePath, []byte(b.String()))
+}
+
+// hashLockedFiles hashes the SVG file of every image. Files are always read again rather than
+// taken from the metadata cache, which trusts unchanged sizes and modification times.
+// Unreadable files are left out, they are already reported as warnings.
+func hashLockedFiles(images map[string]string) svgLock {
+	lock := make(svgLock, len(images))
+	for image := range images {
+		content, err := ioutil.ReadFile(SourceFile(Icon{Image: image}))
+		if err != nil {
+			continue
+		}
+		lock[image] = hashContent(content)
+	}
+	return lock
+}
+
+// lockMismatch is an SVG file that does not match the lock file
+type lockMismatch struct {
+	Image  string
+	Change string // changed, unlocked or removed
+}
+
+// diffLock compares the current hashes with the lock. Files only in the lock are reported as
+// removed when every icon was processed, a limited or filtered run does not see them all.
+func diffLock(lock, current svgLock, fullRun bool) []lockMismatch {
+	var mismatches []lockMismatch
+	for image, hash := range current {
+		locked, ok := lock[image]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, lockMismatch{Image: image, Change: "unlocked"})
+		case locked != hash:
+			mismatches = append(mismatches, lockMismatch{Image: image, Change: "changed"})
+		}
+	}
+	if fullRun {
+		for image := range lock {
+			if _, ok := current[image]; !ok {
+				mismatches = append(mismatches, lockMismatch{Image: image, Change: "removed"})
+			}
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].Image < mismatches[j].Image })
+	return mismatches
+}
+
+// verifyLock fails when an SVG file was changed, added or removed since the lock was written,
+// listing every such file
+func verifyLock(filePath string, current svgLock, fullRun bool) error {
+	lock, err := loadLock(filePath)
+	if err != nil {
+		return err
+	}
+	mismatches := diffLock(lock, current, fullRun)
+	if len(mismatches) == 0 {
+		slog.Info(fmt.Sprintf("🔒 All %d SVG files match %s", len(current), filePath), "files", len(current))
+		return nil
+	}
+
+	slog.Error(fmt.Sprintf("🔓 %d SVG files do not match %s:", len(mismatches), filePath), "mismatches", len(mismatches))
+	for _, m := range mismatches {
+		slog.Error(fmt.Sprintf("   • %s: %s", m.Change, m.Image), "change", m.Change, "image", m.Image)
+	}
+	return fmt.Errorf("%d SVG files do not match %s, run with --update-lock if the changes are expected", len(mismatches), filePath)
+}
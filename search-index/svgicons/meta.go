@@ -0,0 +1,227 @@
+package svgicons
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// IconsDir is the directory the frontend serves SVG icon files from
+const IconsDir = "../frontend/public/svg_icons"
+
+// svgMetadata holds the information extracted from an SVG file
+type svgMetadata struct {
+	Width      float64
+	Height     float64
+	ViewBox    string
+	Colors     []string
+	Monochrome bool
+	Title      string // Text of the <title> child of the root element, whitespace collapsed
+	Desc       string // Text of the <desc> child of the root element, whitespace collapsed
+	Complexity int    // Drawing elements plus path data commands and polygon points, see svgComplexity
+}
+
+// svgDrawingElements are the elements that each draw a shape
+var svgDrawingElements = map[string]bool{
+	"path": true, "circle": true, "ellipse": true, "rect": true, "line": true,
+	"polyline": true, "polygon": true, "text": true, "use": true, "image": true,
+}
+
+// parseSVGMetadata parses SVG content and extracts the dimensions of its root element
+// along with the colors used by its elements.
+// A missing viewBox is derived from width/height, and missing width/height from the viewBox.
+func parseSVGMetadata(content []byte) (*svgMetadata, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	decoder.Strict = false
+
+	var meta *svgMetadata
+	colors := newColorCollector()
+
+	// The first <title> and <desc> directly under the root describe the whole icon,
+	// nested ones only describe a part of it
+	depth := 0
+	var text *strings.Builder
+	var title, desc strings.Builder
+	hasTitle, hasDesc := false, false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SVG: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.CharData:
+			if text != nil {
+				text.Write(t)
+			}
+			continue
+		case xml.EndElement:
+			depth--
+			if depth == 1 {
+				text = nil
+			}
+			continue
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		depth++
+
+		if depth == 2 {
+			switch {
+			case start.Name.Local == "title" && !hasTitle:
+				text, hasTitle = &title, true
+			case start.Name.Local == "desc" && !hasDesc:
+				text, hasDesc = &desc, true
+			}
+		}
+
+		if meta == nil {
+			if start.Name.Local != "svg" {
+				return nil, fmt.Errorf("root element is <%s>, expected <svg>", start.Name.Local)
+			}
+			meta = parseSVGRoot(start)
+		}
+
+		colors.addElement(start)
+		meta.Complexity += svgComplexity(start)
+	}
+
+	if meta == nil {
+		return nil, fmt.Errorf("failed to find <svg> root element")
+	}
+
+	meta.Colors = colors.colors
+	meta.Monochrome = colors.currentColor && len(colors.colors) == 0
+	meta.Title = strings.Join(strings.Fields(title.String()), " ")
+	meta.Desc = strings.Join(strings.Fields(desc.String()), " ")
+	return meta, nil
+}
+
+// validateSVG checks that content is well-formed XML with an <svg> root element. Unlike
+// parseSVGMetadata it does not tolerate unclosed or mismatched tags, which browsers refuse
+// to render and show as a blank icon.
+func validateSVG(content []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	hasRoot := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok && !hasRoot {
+			if start.Name.Local != "svg" {
+				return fmt.Errorf("root element is <%s>, expected <svg>", start.Name.Local)
+			}
+			hasRoot = true
+		}
+	}
+
+	if !hasRoot {
+		return fmt.Errorf("no <svg> root element")
+	}
+	return nil
+}
+
+// svgComplexity scores how heavy an element is to render: 1 for a drawing element, plus one
+// per command of its path data and one per point of a polyline or polygon
+func svgComplexity(start xml.StartElement) int {
+	if !svgDrawingElements[start.Name.Local] {
+		return 0
+	}
+	complexity := 1
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "d":
+			complexity += countPathCommands(attr.Value)
+		case "points":
+			complexity += len(strings.Fields(strings.ReplaceAll(attr.Value, ",", " "))) / 2
+		}
+	}
+	return complexity
+}
+
+// countPathCommands counts the command letters of SVG path data, such as M, l or Z.
+// An e or E only appears as the exponent of a number.
+func countPathCommands(d string) int {
+	count := 0
+	for _, r := range d {
+		if (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') && r != 'e' && r != 'E' {
+			count++
+		}
+	}
+	return count
+}
+
+// parseSVGRoot builds the metadata from the attributes of the <svg> root element
+func parseSVGRoot(start xml.StartElement) *svgMetadata {
+	meta := &svgMetadata{}
+	var rawWidth, rawHeight string
+
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "width":
+			rawWidth = attr.Value
+		case "height":
+			rawHeight = attr.Value
+		case "viewBox":
+			meta.ViewBox = strings.Join(strings.Fields(strings.ReplaceAll(attr.Value, ",", " ")), " ")
+		}
+	}
+
+	width, hasWidth := parseSVGLength(rawWidth)
+	height, hasHeight := parseSVGLength(rawHeight)
+
+	if hasWidth && hasHeight {
+		meta.Width = width
+		meta.Height = height
+		if meta.ViewBox == "" {
+			meta.ViewBox = fmt.Sprintf("0 0 %s %s", formatSVGNumber(width), formatSVGNumber(height))
+		}
+		return meta
+	}
+
+	// Fall back to the viewBox size when explicit dimensions are missing
+	if parts := strings.Fields(meta.ViewBox); len(parts) == 4 {
+		vbWidth, errW := strconv.ParseFloat(parts[2], 64)
+		vbHeight, errH := strconv.ParseFloat(parts[3], 64)
+		if errW == nil && errH == nil {
+			meta.Width = vbWidth
+			meta.Height = vbHeight
+		}
+	}
+
+	return meta
+}
+
+// parseSVGLength parses an absolute SVG length such as "24" or "24px".
+// Relative units (%, em) cannot be resolved without a viewport and are ignored.
+func parseSVGLength(value string) (float64, bool) {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "px")
+	if value == "" {
+		return 0, false
+	}
+
+	length, err := strconv.ParseFloat(value, 64)
+	if err != nil || length <= 0 {
+		return 0, false
+	}
+	return length, true
+}
+
+func formatSVGNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,150 @@
+package svgicons
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// svgMetaCacheFile holds what was read from each SVG file by the last run, see svgMetaCache
+const svgMetaCacheFile = ".svg_meta_cache.json"
+
+// svgFileData is what processSVGIcon learns from reading an SVG file
+type svgFileData struct {
+	Meta        *svgMetadata `json:"meta,omitempty"`       // nil when the file could not be parsed at all
+	ParseError  string       `json:"parseError,omitempty"` // Why validateSVG rejected the file, empty when well-formed
+	ContentHash string       `json:"contentHash"`
+	DedupeHash  string       `json:"dedupeHash"`
+}
+
+// readSVGFileData validates, parses and hashes SVG content
+func readSVGFileData(content []byte) svgFileData {
+	data := svgFileData{
+		ContentHash: hashContent(content),
+		DedupeHash:  hashContent(normalizeSVGWhitespace(content)),
+	}
+	if err := validateSVG(content); err != nil {
+		data.ParseError = err.Error()
+	}
+	if meta, err := parseSVGMetadata(content); err == nil {
+		data.Meta = meta
+	}
+	return data
+}
+
+// svgMetaCacheEntry is the data of an SVG file along with the size and modification time
+// it had when read
+type svgMetaCacheEntry struct {
+	Size    int64       `json:"size"`
+	ModTime int64       `json:"modTime"` // Unix nanoseconds
+	Data    svgFileData `json:"data"`
+}
+
+// svgMetaCacheContent is the layout of svgMetaCacheFile
+type svgMetaCacheContent struct {
+	Version int                          `json:"version"` // DataVersion of the run that wrote the cache
+	Files   map[string]svgMetaCacheEntry `json:"files"`   // By path of the SVG file
+}
+
+// svgMetaCache reuses the parsed metadata and hashes of SVG files whose path, size and
+// modification time are unchanged since the last run, so they are not read again. Unlike
+// the --incremental cache it works per file and whatever the cluster files say. A nil cache
+// reads every file. It is safe for concurrent use by the icon workers.
+type svgMetaCache struct {
+	dir  string
+	old  map[string]svgMetaCacheEntry // Loaded from the last run, only read
+	mu   sync.Mutex
+	seen map[string]svgMetaCacheEntry // Files looked up by this run
+	hits int
+}
+
+// loadSVGMetaCache reads the metadata cache in dir. A missing, unreadable or outdated cache
+// starts empty, it only costs parsing every file again.
+func loadSVGMetaCache(dir string) (*svgMetaCache, error) {
+	cache := &svgMetaCache{dir: dir, old: map[string]svgMetaCacheEntry{}, seen: map[string]svgMetaCacheEntry{}}
+
+	cachePath := filepath.Join(dir, svgMetaCacheFile)
+	content, err := ioutil.ReadFile(cachePath)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", cachePath, err)
+	}
+
+	var saved svgMetaCacheContent
+	if err := json.Unmarshal(content, &saved); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️  Warning: Ignoring unreadable %s: %v", cachePath, err))
+		return cache, nil
+	}
+	if saved.Version == DataVersion && saved.Files != nil {
+		cache.old = saved.Files
+	}
+	return cache, nil
+}
+
+// read returns the data of an SVG file, from the cache when its size and modification time
+// match the cached entry, otherwise by reading and parsing it
+func (c *svgMetaCache) read(svgFile string) (svgFileData, error) {
+	if c == nil {
+		content, err := ioutil.ReadFile(svgFile)
+		if err != nil {
+			return svgFileData{}, err
+		}
+		return readSVGFileData(content), nil
+	}
+
+	info, err := os.Stat(svgFile)
+	if err != nil {
+		return svgFileData{}, err
+	}
+	if entry, ok := c.old[svgFile]; ok && entry.Size == info.Size() && entry.ModTime == info.ModTime().UnixNano() {
+		c.mu.Lock()
+		c.seen[svgFile] = entry
+		c.hits++
+		c.mu.Unlock()
+		return entry.Data, nil
+	}
+
+	content, err := ioutil.ReadFile(svgFile)
+	if err != nil {
+		return svgFileData{}, err
+	}
+	data := readSVGFileData(content)
+
+	c.mu.Lock()
+	c.seen[svgFile] = svgMetaCacheEntry{Size: info.Size(), ModTime: info.ModTime().UnixNano(), Data: data}
+	c.mu.Unlock()
+	return data, nil
+}
+
+// save writes the entries of the files read by this run, and those of the last run for
+// files left out by --folder or --limit that still exist, to the cache in its directory
+func (c *svgMetaCache) save() error {
+	if c == nil {
+		return nil
+	}
+
+	files := make(map[string]svgMetaCacheEntry, len(c.seen))
+	for svgFile, entry := range c.old {
+		if _, ok := c.seen[svgFile]; ok {
+			continue
+		}
+		if _, err := os.Stat(svgFile); err == nil {
+			files[svgFile] = entry
+		}
+	}
+	for svgFile, entry := range c.seen {
+		files[svgFile] = entry
+	}
+
+	data, err := json.Marshal(svgMetaCacheContent{Version: DataVersion, Files: files})
+	if err != nil {
+		return err
+	}
+	return writeCacheFile(c.dir, svgMetaCacheFile, data)
+}
@@ -0,0 +1,157 @@
+package svgicons
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// editMetaCache rewrites the cached data of every file in the metadata cache of the working
+// directory, so the next run shows which files it took from the cache
+func editMetaCache(t *testing.T, edit func(svgFile string, data *svgFileData)) {
+	t.Helper()
+	content, err := ioutil.ReadFile(svgMetaCacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved svgMetaCacheContent
+	if err := json.Unmarshal(content, &saved); err != nil {
+		t.Fatal(err)
+	}
+	for svgFile, entry := range saved.Files {
+		edit(svgFile, &entry.Data)
+		saved.Files[svgFile] = entry
+	}
+	if content, err = json.Marshal(saved); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(svgMetaCacheFile, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMetaCacheReusesUnchangedFiles(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", FileNames: testFiles("home.svg", "bell.svg", "cog.svg")},
+	})
+	tt.generate(Options{})
+	editMetaCache(t, func(svgFile string, data *svgFileData) {
+		data.Meta.ViewBox = "0 0 1 1"
+	})
+
+	// bell.svg changes size and cog.svg only its modification time, home.svg is unchanged
+	tt.writeSVG("feather", "bell.svg", strings.Replace(testSVG, "M3 12h18", "M3 12h18v2", 1))
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(IconsDirPath("feather", "cog.svg"), later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	icons, _ := tt.generate(Options{})
+	for image, want := range map[string]string{
+		"/svg_icons/feather/home.svg": "0 0 1 1",
+		"/svg_icons/feather/bell.svg": "0 0 24 24",
+		"/svg_icons/feather/cog.svg":  "0 0 24 24",
+	} {
+		if got := iconByImage(t, icons, image).ViewBox; got != want {
+			t.Errorf("%s viewBox = %q, want %q", image, got, want)
+		}
+	}
+
+	// --no-cache parses every file again
+	icons, _ = tt.generate(Options{NoCache: true})
+	if got := iconByImage(t, icons, "/svg_icons/feather/home.svg").ViewBox; got != "0 0 24 24" {
+		t.Errorf("home viewBox with NoCache = %q, want the parsed 0 0 24 24", got)
+	}
+}
+
+func TestMetaCacheMatchesNoCache(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(incrementalTestClusters("A house"))
+	tt.writeSVG("material", "bell.svg", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 16 16"><title>Bell</title><circle cx="8" cy="8" r="4" fill="#f00"/></svg>`)
+	tt.writeSVG("material", "cog.svg", `<svg viewBox="0 0 24 24"><path d="M3 12h18"`)
+	tt.generate(Options{})
+
+	cached, _ := tt.generate(Options{})
+	full, _ := tt.generate(Options{NoCache: true})
+	if got, want := mustJSON(t, cached), mustJSON(t, full); got != want {
+		t.Errorf("output from the metadata cache differs from parsing every file:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMetaCacheKeepsFilesLeftOut(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(incrementalTestClusters("A house"))
+	tt.generate(Options{})
+
+	// A run limited to one folder keeps the entries of the other, until their file is removed
+	if err := os.Remove(IconsDirPath("material", "bell.svg")); err != nil {
+		t.Fatal(err)
+	}
+	tt.generate(Options{Folder: "feather"})
+	cache, err := loadSVGMetaCache("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.old[IconsDirPath("material", "cog.svg")]; !ok {
+		t.Error("the entry of material/cog.svg was dropped by a run of feather only")
+	}
+	if _, ok := cache.old[IconsDirPath("material", "bell.svg")]; ok {
+		t.Error("the entry of the removed material/bell.svg was kept")
+	}
+}
+
+func TestLoadSVGMetaCacheOutdated(t *testing.T) {
+	newTestTree(t)
+	content := fmt.Sprintf(`{"version": %d, "files": {"a.svg": {"size": 1, "modTime": 1, "data": {}}}}`, DataVersion-1)
+	if err := ioutil.WriteFile(svgMetaCacheFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A cache written by an older data layout is ignored rather than misread
+	if cache, err := loadSVGMetaCache(""); err != nil || len(cache.old) != 0 {
+		t.Errorf("loadSVGMetaCache of an outdated file = %v, %v; want an empty cache", cache, err)
+	}
+}
+
+// benchmarkSVG is a larger icon, closer to the real catalog than testSVG, so parsing costs
+// what it does on real files
+var benchmarkSVG = `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2"><title>Settings</title>` +
+	strings.Repeat(`<path d="M12 15a3 3 0 1 0 0-6 3 3 0 0 0 0 6z M19.4 15a1.65 1.65 0 0 0 .33 1.82l.06.06a2 2 0 0 1 0 2.83 2 2 0 0 1-2.83 0l-.06-.06"/><circle cx="12" cy="12" r="3" fill="#1e88e5"/>`, 20) +
+	`</svg>`
+
+// BenchmarkGenerateMetaCache compares a run parsing 2000 SVG files with a second run on the
+// same unchanged files, which takes their metadata from the cache.
+func BenchmarkGenerateMetaCache(b *testing.B) {
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(ioutil.Discard, nil)))
+	defer slog.SetDefault(logger)
+
+	for _, bc := range []struct {
+		name string
+		opts Options
+	}{
+		{"NoCache", Options{NoCache: true}},
+		{"Cached", Options{}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			tt := newTestTree(b)
+			fileNames := make([]FileName, 2000)
+			for i := range fileNames {
+				fileNames[i] = FileName{FileName: fmt.Sprintf("settings-%d.svg", i), Description: "Settings"}
+				tt.writeSVG("feather", fileNames[i].FileName, benchmarkSVG)
+			}
+			tt.writeClusters(map[string]ClusterEntry{"feather": {SourceFolder: "feather", FileNames: fileNames}})
+			tt.generate(Options{})
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tt.generate(bc.opts)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package svgicons
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Sources of Icon.ModifiedAt accepted by Options.ModifiedFrom
+const (
+	ModifiedFromMtime = "mtime" // Modification time of the SVG file, the default
+	ModifiedFromGit   = "git"   // Committer date of the last commit touching the SVG file
+	ModifiedFromNone  = "none"  // No ModifiedAt
+)
+
+// ModifiedFromSources lists every source of ModifiedAt, the values accepted by --modified-from
+var ModifiedFromSources = []string{ModifiedFromMtime, ModifiedFromGit, ModifiedFromNone}
+
+// validModifiedFrom reports whether from is one of ModifiedFromSources, the empty string meaning mtime
+func validModifiedFrom(from string) bool {
+	return from == "" || ContainsString(ModifiedFromSources, strings.ToLower(from))
+}
+
+// formatModifiedAt formats a ModifiedAt in RFC 3339, in UTC so it does not depend on the
+// time zone of the machine
+func formatModifiedAt(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// setModifiedAt sets the ModifiedAt of the icons from the modification time of their SVG
+// file or its last git commit. Icons whose date is unknown, e.g. a missing file or one git
+// does not track, are left without one. sourceFolders maps images to their cluster source
+// folder. Returns the number of icons dated.
+func setModifiedAt(ctx context.Context, icons []Icon, from string, sourceFolders map[string]string) (int, error) {
+	from = strings.ToLower(from)
+	if from == ModifiedFromNone {
+		return 0, nil
+	}
+
+	var commitDates map[string]string
+	if from == ModifiedFromGit {
+		var err error
+		if commitDates, err = gitCommitDates(ctx, icons, sourceFolders); err != nil {
+			return 0, err
+		}
+	}
+
+	dated := 0
+	for i := range icons {
+		file := filepath.Clean(SourceFile(icons[i]))
+		if from == ModifiedFromGit {
+			icons[i].ModifiedAt = commitDates[file]
+		} else if info, err := os.Stat(file); err == nil {
+			icons[i].ModifiedAt = formatModifiedAt(info.ModTime())
+		}
+		if icons[i].ModifiedAt != "" {
+			dated++
+		}
+	}
+	return dated, nil
+}
+
+// gitCommitDates returns the committer date of the last commit of every file in the source
+// folders of the icons, by file path. git runs once per source folder rather than once per
+// file; a folder outside any git repository gets no dates and a warning.
+func gitCommitDates(ctx context.Context, icons []Icon, sourceFolders map[string]string) (map[string]string, error) {
+	dates := make(map[string]string)
+	done := make(map[string]bool)
+	for _, icon := range icons {
+		root := folderRoot(sourceFolders[icon.Image])
+		if done[root] {
+			continue
+		}
+		done[root] = true
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Newest commits come first, so the first date seen for a file is its last change
+		cmd := exec.CommandContext(ctx, "git", "-C", root, "log", "--relative", "--name-only", "--format=%x00%cI", "--", ".")
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err != nil {
+			slog.Warn(fmt.Sprintf("⚠️  Warning: No git dates for %s, its icons get no modifiedAt: %s", root, strings.TrimSpace(firstLine(stderr.String(), err.Error()))), "dir", root)
+			continue
+		}
+
+		date := ""
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "\x00"):
+				date = ""
+				if t, err := time.Parse(time.RFC3339, strings.TrimPrefix(line, "\x00")); err == nil {
+					date = formatModifiedAt(t)
+				}
+			case line != "" && date != "":
+				file := filepath.Join(root, filepath.FromSlash(line))
+				if _, ok := dates[file]; !ok {
+					dates[file] = date
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read the git log of %s: %w", root, err)
+		}
+	}
+	return dates, nil
+}
+
+// firstLine returns the first line of text, or fallback when text is blank
+func firstLine(text, fallback string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fallback
+	}
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		return text[:i]
+	}
+	return text
+}
@@ -0,0 +1,49 @@
+package svgicons
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// NameCasingFile is an optional JSON array of extra canonical spellings, e.g. ["GraphQL", "npm"]
+const NameCasingFile = "name_casing.json"
+
+// nameCasing maps lowercased words to the casing FormatIconName should use instead of title case
+var nameCasing = map[string]string{
+	"api":    "API",
+	"url":    "URL",
+	"ui":     "UI",
+	"html":   "HTML",
+	"css":    "CSS",
+	"github": "GitHub",
+	"macos":  "macOS",
+	"ios":    "iOS",
+}
+
+// LoadNameCasing merges the canonical spellings from a JSON file into nameCasing.
+// A missing file is not an error since the built-in list covers the common cases.
+func LoadNameCasing(filePath string) error {
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var words []string
+	if err := json.Unmarshal(content, &words); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word != "" {
+			nameCasing[strings.ToLower(word)] = word
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package svgicons
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OptimizedDir is the directory under the output directory --optimize writes SVGs to,
+// laid out like IconsDir
+const OptimizedDir = "svg_icons"
+
+// svgSpan is a byte range [start, end) of an SVG file
+type svgSpan struct {
+	start, end int64
+}
+
+// svgGroup is an open <g> element while optimizing, dropped on close if it drew nothing
+type svgGroup struct {
+	start   int64
+	hasDraw bool
+}
+
+// optimizeSVG strips editor cruft that does not affect rendering: comments, the XML
+// declaration, <metadata> elements and groups without any content. Everything else is
+// copied byte for byte, so paths, attributes and namespaces are untouched.
+// Content that is not well-formed XML is returned as an error.
+func optimizeSVG(content []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+
+	var drop []svgSpan
+	var groups []svgGroup
+	metadataDepth := 0 // Depth inside the <metadata> element being dropped, 0 outside
+	metadataStart := int64(0)
+
+	// A non-empty element or text marks every open group as drawing something
+	markDraw := func() {
+		for i := range groups {
+			groups[i].hasDraw = true
+		}
+	}
+
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		end := decoder.InputOffset()
+
+		if metadataDepth > 0 {
+			switch token.(type) {
+			case xml.StartElement:
+				metadataDepth++
+			case xml.EndElement:
+				metadataDepth--
+				if metadataDepth == 0 {
+					drop = append(drop, svgSpan{metadataStart, end})
+				}
+			}
+			continue
+		}
+
+		switch t := token.(type) {
+		case xml.Comment:
+			drop = append(drop, svgSpan{start, end})
+		case xml.ProcInst:
+			if t.Target == "xml" {
+				drop = append(drop, svgSpan{start, end})
+			}
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				markDraw()
+			}
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "metadata":
+				metadataDepth, metadataStart = 1, start
+			case "g":
+				groups = append(groups, svgGroup{start: start})
+			default:
+				markDraw()
+			}
+		case xml.EndElement:
+			if t.Name.Local != "g" || len(groups) == 0 {
+				continue
+			}
+			group := groups[len(groups)-1]
+			groups = groups[:len(groups)-1]
+			if !group.hasDraw {
+				// Drops made inside the group are covered by dropping the whole group
+				for len(drop) > 0 && drop[len(drop)-1].start >= group.start {
+					drop = drop[:len(drop)-1]
+				}
+				drop = append(drop, svgSpan{group.start, end})
+			}
+		}
+	}
+
+	var optimized bytes.Buffer
+	optimized.Grow(len(content))
+	last := int64(0)
+	for _, span := range drop {
+		span = expandToLine(content, span)
+		optimized.Write(content[last:span.start])
+		last = span.end
+	}
+	optimized.Write(content[last:])
+
+	// Leave no blank lines where the declaration or a leading comment used to be
+	return bytes.TrimLeft(optimized.Bytes(), " \t\r\n"), nil
+}
+
+// expandToLine widens a dropped span to its whole line when nothing else is on that line,
+// so removed elements do not leave blank lines behind
+func expandToLine(content []byte, span svgSpan) svgSpan {
+	start := span.start
+	for start > 0 && (content[start-1] == ' ' || content[start-1] == '\t') {
+		start--
+	}
+	if start > 0 && content[start-1] != '\n' {
+		return span
+	}
+	end := span.end
+	for end < int64(len(content)) && (content[end] == ' ' || content[end] == '\t' || content[end] == '\r') {
+		end++
+	}
+	if end < int64(len(content)) && content[end] != '\n' {
+		return span
+	}
+	if end < int64(len(content)) {
+		end++
+	}
+	return svgSpan{start, end}
+}
+
+// optimizeSVGIcons sets OriginalBytes and OptimizedBytes on every icon whose SVG file can be
+// read. A file that is not well-formed was already reported and is left as is.
+// Returns the total original and optimized sizes.
+func optimizeSVGIcons(icons []Icon) (int, int) {
+	originalTotal, optimizedTotal := 0, 0
+	for i := range icons {
+		content, err := ioutil.ReadFile(SourceFile(icons[i]))
+		if err != nil {
+			continue
+		}
+		optimized, err := optimizeSVG(content)
+		if err != nil {
+			optimized = content
+		}
+		icons[i].OriginalBytes = len(content)
+		icons[i].OptimizedBytes = len(optimized)
+		originalTotal += len(content)
+		optimizedTotal += len(optimized)
+	}
+	return originalTotal, optimizedTotal
+}
+
+// SaveOptimized writes the optimized SVG of every icon to svg_icons/ in dir,
+// at the same path as in IconsDir. Files that are not well-formed are copied
+// unchanged. Icons folded by --dedupe render the same as their primary icon and are not written.
+func SaveOptimized(icons []Icon, dir string) error {
+	for _, icon := range icons {
+		content, err := ioutil.ReadFile(SourceFile(icon))
+		if err != nil {
+			continue
+		}
+		optimized, err := optimizeSVG(content)
+		if err != nil {
+			optimized = content
+		}
+
+		target := filepath.Join(dir, OptimizedDir, filepath.FromSlash(strings.TrimPrefix(icon.Image, "/svg_icons/")))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+		}
+		if err := writeFileAtomic(target, optimized); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package svgicons
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestOptimizeSVG(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "declaration and comments",
+			content: `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Generator: Sketch 52 -->
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24">
+  <!-- arrow -->
+  <path d="M5 12h14M12 5l7 7-7 7"/>
+</svg>`,
+			want: `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24">
+  <path d="M5 12h14M12 5l7 7-7 7"/>
+</svg>`,
+		},
+		{
+			name: "metadata",
+			content: `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24">
+  <metadata>
+    <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"><dc:creator xmlns:dc="http://purl.org/dc/elements/1.1/">Jane</dc:creator></rdf:RDF>
+  </metadata>
+  <path d="M3 12h18"/>
+</svg>`,
+			want: `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24">
+  <path d="M3 12h18"/>
+</svg>`,
+		},
+		{
+			name: "empty groups",
+			content: `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24">
+  <g id="Layer_1"><g><!-- nothing --></g></g>
+  <g fill="none" stroke="#000"><path d="M3 12h18"/></g>
+</svg>`,
+			want: `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24">
+  <g fill="none" stroke="#000"><path d="M3 12h18"/></g>
+</svg>`,
+		},
+		{
+			name:    "nothing to strip",
+			content: testSVG,
+			want:    testSVG,
+		},
+	}
+	for _, tc := range tests {
+		got, err := optimizeSVG([]byte(tc.content))
+		if err != nil {
+			t.Errorf("%s: optimizeSVG: %v", tc.name, err)
+			continue
+		}
+		if string(got) != tc.want {
+			t.Errorf("%s: optimizeSVG =\n%s\nwant:\n%s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestOptimizeSVGMalformed(t *testing.T) {
+	if _, err := optimizeSVG([]byte(`<svg><path d="M0 0"></svg>`)); err == nil {
+		t.Error("optimizeSVG of a malformed SVG succeeded")
+	}
+}
+
+func TestGenerateOptimize(t *testing.T) {
+	tt := newTestTree(t)
+	cruft := "<?xml version=\"1.0\"?>\n<!-- Created with Inkscape -->\n" + testSVG
+	tt.writeSVG("feather", "home.svg", cruft)
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", FileNames: testFiles("home.svg")},
+	})
+
+	icons, _ := tt.generate(Options{Optimize: true})
+	home := iconByImage(t, icons, "/svg_icons/feather/home.svg")
+	if home.OriginalBytes != len(cruft) || home.OptimizedBytes != len(testSVG) {
+		t.Errorf("sizes %d -> %d, want %d -> %d", home.OriginalBytes, home.OptimizedBytes, len(cruft), len(testSVG))
+	}
+
+	dir := t.TempDir()
+	if err := SaveOptimized(icons, dir); err != nil {
+		t.Fatalf("SaveOptimized: %v", err)
+	}
+	written, err := ioutil.ReadFile(filepath.Join(dir, OptimizedDir, "feather", "home.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(written) != testSVG {
+		t.Errorf("optimized file = %s, want %s", written, testSVG)
+	}
+}
@@ -0,0 +1,128 @@
+package svgicons
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultClusterPath is read when neither Options.ClusterPaths nor CLUSTER_SVG_PATH is set
+const DefaultClusterPath = "../frontend/data/cluster_svg.json"
+
+// BasePathEnv names the environment variable setting the public base path when Options.BasePath is empty
+const BasePathEnv = "SVG_ICONS_BASE_PATH"
+
+// Options configures Generate. The zero value processes every icon of the default cluster
+// file with GOMAXPROCS workers and none of the optional steps.
+type Options struct {
+	ClusterPaths        []string      // Cluster files or globs to read and merge, CLUSTER_SVG_PATH or DefaultClusterPath when empty
+	CacheDir            string        // Directory of the ID map and the SVG metadata, Incremental and Since caches, the working directory when empty
+	ClusterReadAttempts int           // Attempts at reading a cluster file that is temporarily unavailable, 1 when 0
+	ClusterReadBackoff  time.Duration // Wait before the second attempt, doubled after each further one
+	Workers             int           // Number of goroutines processing icons, 0 means GOMAXPROCS
+	Dedupe              bool          // Fold byte-identical SVGs into a single icon with aliases
+	GroupVariants       bool          // Fold style variants such as home-filled and home-outline into one icon
+	VariantSuffixes     []string      // Style suffixes recognized by GroupVariants, DefaultVariantSuffixes when empty
+	DryRun              bool          // Write neither the ID map nor the caches
+	ContinueOnError     bool          // Leave out the cluster files and clusters failing to load instead of failing, see Report.Errors
+	NoCache             bool          // Read and parse every SVG file instead of reusing the metadata cached by the last run
+	Incremental         bool          // Reuse the cached icon data of clusters whose entry and SVG files did not change
+	Since               time.Time     // Reuse the cached icon data of SVG files not modified after this, zero for off
+	Related             bool          // List the icons sharing the most tags on each icon
+	RelatedCount        int           // Number of related icons listed per icon
+	Optimize            bool          // Record the size of each SVG before and after OptimizeSVG
+	InlineSVG           bool          // Embed each SVG as a data URI for previews
+	InlineSVGMaxBytes   int           // Largest minified SVG inlined by InlineSVG, 0 for no limit
+	Limit               int           // Only process the first Limit cluster files, 0 for all
+	Folder              string        // Glob selecting the cluster source folders to process, all when empty
+	ComplexityThreshold int           // Icons with a higher Complexity are listed in Report.ComplexIcons
+	IDStyle             string        // IDStyleSlug or IDStyleHash, slug when empty
+	BasePath            string        // Public path the icon pages are served under, BasePathEnv or /freedevtools/svg_icons/ when empty
+	Sort                string        // Order of the returned icons, one of SortOrders, SortID when empty
+	MaxDescriptionLen   int           // Longest description in characters, longer ones are cut with an ellipsis, 0 for no limit
+	KeepFullDescription bool          // Keep the text of cut descriptions in Icon.DescriptionFull
+	Rasterize           bool          // Write a raster fallback of each SVG next to it and set Icon.RasterImage, skipped by DryRun
+	RasterSize          int           // Width and height of the rasters in pixels, DefaultRasterSize when 0
+	RasterFormat        string        // One of RasterFormats, RasterPNG when empty
+	Source              string        // Directory, HTTP base URL or tarball laid out like IconsDir for clusters without their own source, see OpenSources
+	SourceTimeout       time.Duration // Longest download of a remote source, DefaultSourceTimeout when 0
+	Verify              bool          // Fail when an SVG file changed, appeared or disappeared since LockFile was written
+	UpdateLock          bool          // Rewrite LockFile with the hashes of the current SVG files, skipped by DryRun
+	ModifiedFrom        string        // Where Icon.ModifiedAt comes from, one of ModifiedFromSources, ModifiedFromMtime when empty
+	Hidden              string        // What to do with files whose name starts with an underscore, one of HiddenPolicies, HiddenStrip when empty
+	Progress            ProgressFunc  // Reports the SVG files processed so far, nil for none
+}
+
+// ProgressFunc is called from a single goroutine after each SVG file processed, with the
+// number of files processed and the number to process
+type ProgressFunc func(done, total int)
+
+// WorkerCount returns the number of workers to process icons with
+func (o Options) WorkerCount() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// MatchesFolder reports whether a cluster source folder is selected by Folder
+func (o Options) MatchesFolder(sourceFolder string) bool {
+	if o.Folder == "" {
+		return true
+	}
+	matched, _ := path.Match(o.Folder, sourceFolder)
+	return matched
+}
+
+// ResolveBasePath returns the public path the icon pages are served under, from BasePath,
+// then BasePathEnv, then the default, with a single leading and trailing slash
+func (o Options) ResolveBasePath() string {
+	basePath := o.BasePath
+	if basePath == "" {
+		basePath = os.Getenv(BasePathEnv)
+	}
+	if basePath == "" {
+		return svgIconsBasePath
+	}
+	if trimmed := strings.Trim(basePath, "/"); trimmed != "" {
+		return "/" + trimmed + "/"
+	}
+	return "/"
+}
+
+// rasterSize returns the width and height of the rasters Rasterize writes
+func (o Options) rasterSize() int {
+	if o.RasterSize > 0 {
+		return o.RasterSize
+	}
+	return DefaultRasterSize
+}
+
+// rasterFormat returns the format of the rasters Rasterize writes
+func (o Options) rasterFormat() string {
+	if o.RasterFormat == "" {
+		return RasterPNG
+	}
+	return o.RasterFormat
+}
+
+// variantSuffixes returns the style suffixes GroupVariants recognizes
+func (o Options) variantSuffixes() []string {
+	if len(o.VariantSuffixes) == 0 {
+		return DefaultVariantSuffixes
+	}
+	return o.VariantSuffixes
+}
+
+// SplitList splits a comma separated list, dropping blank entries
+func SplitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,25 @@
+package svgicons
+
+import "testing"
+
+func TestResolveBasePath(t *testing.T) {
+	tests := []struct {
+		basePath, env string
+		want          string
+	}{
+		{"", "", "/freedevtools/svg_icons/"},
+		{"/preview/svg_icons/", "", "/preview/svg_icons/"},
+		{"preview/svg_icons", "", "/preview/svg_icons/"},
+		{"//preview//", "", "/preview/"},
+		{"/", "", "/"},
+		{"", "/staging/icons", "/staging/icons/"},
+		// The option wins over the environment
+		{"/preview/", "/staging/", "/preview/"},
+	}
+	for _, tc := range tests {
+		t.Setenv(BasePathEnv, tc.env)
+		if got := (Options{BasePath: tc.basePath}).ResolveBasePath(); got != tc.want {
+			t.Errorf("ResolveBasePath with %q and %s=%q = %q, want %q", tc.basePath, BasePathEnv, tc.env, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package svgicons
+
+import (
+	"sort"
+	"strings"
+)
+
+// Output orders accepted by Options.Sort
+const (
+	SortID       = "id"       // By ID, the default
+	SortName     = "name"     // By display name ignoring case, then ID
+	SortCategory = "category" // By cluster source folder, then display name ignoring case, then ID
+	SortNone     = "none"     // Cluster traversal order: clusters by key, files in cluster order
+)
+
+// SortOrders lists every output order, the values accepted by --sort
+var SortOrders = []string{SortID, SortName, SortCategory, SortNone}
+
+// validSortOrder reports whether order is one of SortOrders, the empty string meaning id
+func validSortOrder(order string) bool {
+	return order == "" || ContainsString(SortOrders, strings.ToLower(order))
+}
+
+// sortIcons puts icons, sorted by ID, in the given output order. Every order ends with the
+// ID, unique once duplicates are resolved, so the result never depends on the input order.
+// sourceFolders maps images to their cluster source folder and positions images to their
+// index in cluster traversal order.
+func sortIcons(icons []Icon, order string, sourceFolders map[string]string, positions map[string]int) {
+	byName := func(a, b Icon) (bool, bool) {
+		if nameA, nameB := strings.ToLower(a.Name), strings.ToLower(b.Name); nameA != nameB {
+			return nameA < nameB, true
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name, true
+		}
+		return false, false
+	}
+
+	switch strings.ToLower(order) {
+	case SortName:
+		sort.Slice(icons, func(i, j int) bool {
+			if less, ok := byName(icons[i], icons[j]); ok {
+				return less
+			}
+			return icons[i].ID < icons[j].ID
+		})
+	case SortCategory:
+		sort.Slice(icons, func(i, j int) bool {
+			if folderA, folderB := sourceFolders[icons[i].Image], sourceFolders[icons[j].Image]; folderA != folderB {
+				return folderA < folderB
+			}
+			if less, ok := byName(icons[i], icons[j]); ok {
+				return less
+			}
+			return icons[i].ID < icons[j].ID
+		})
+	case SortNone:
+		sort.Slice(icons, func(i, j int) bool {
+			if positions[icons[i].Image] != positions[icons[j].Image] {
+				return positions[icons[i].Image] < positions[icons[j].Image]
+			}
+			return icons[i].ID < icons[j].ID
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package svgicons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortIcons(t *testing.T) {
+	// Images are named after their folder and position in cluster traversal order
+	icons := []Icon{
+		{ID: "svg-icons-b-bell", Name: "Bell", Image: "b/1"},
+		{ID: "svg-icons-a-home", Name: "home", Image: "a/2"},
+		{ID: "svg-icons-a-zap", Name: "Zap", Image: "a/0"},
+		{ID: "svg-icons-b-home", Name: "Home", Image: "b/0"},
+		{ID: "svg-icons-a-bell", Name: "Bell", Image: "a/1"},
+	}
+	positions := map[string]int{"a/0": 0, "a/1": 1, "a/2": 2, "b/0": 3, "b/1": 4}
+	sourceFolders := make(map[string]string)
+	for _, icon := range icons {
+		sourceFolders[icon.Image] = strings.Split(icon.Image, "/")[0]
+	}
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{SortName, []string{"svg-icons-a-bell", "svg-icons-b-bell", "svg-icons-b-home", "svg-icons-a-home", "svg-icons-a-zap"}},
+		{SortCategory, []string{"svg-icons-a-bell", "svg-icons-a-home", "svg-icons-a-zap", "svg-icons-b-bell", "svg-icons-b-home"}},
+		{SortNone, []string{"svg-icons-a-zap", "svg-icons-a-bell", "svg-icons-a-home", "svg-icons-b-home", "svg-icons-b-bell"}},
+		{"NAME", []string{"svg-icons-a-bell", "svg-icons-b-bell", "svg-icons-b-home", "svg-icons-a-home", "svg-icons-a-zap"}},
+	}
+	for _, tc := range tests {
+		// Any input order gives the same result
+		for shift := 0; shift < len(icons); shift++ {
+			sorted := append(append([]Icon(nil), icons[shift:]...), icons[:shift]...)
+			sortIcons(sorted, tc.order, sourceFolders, positions)
+			var ids []string
+			for _, icon := range sorted {
+				ids = append(ids, icon.ID)
+			}
+			if strings.Join(ids, " ") != strings.Join(tc.want, " ") {
+				t.Errorf("sort %s from shift %d = %v, want %v", tc.order, shift, ids, tc.want)
+				break
+			}
+		}
+	}
+
+	// The default expects icons already sorted by ID and leaves them as they are
+	sorted := append([]Icon(nil), icons...)
+	sortIcons(sorted, SortID, sourceFolders, positions)
+	for i := range sorted {
+		if sorted[i].ID != icons[i].ID {
+			t.Fatalf("sort id moved %s", icons[i].ID)
+		}
+	}
+}
+
+func TestValidSortOrder(t *testing.T) {
+	for _, order := range []string{"", "id", "name", "Category", "none"} {
+		if !validSortOrder(order) {
+			t.Errorf("validSortOrder(%q) = false, want true", order)
+		}
+	}
+	if validSortOrder("size") {
+		t.Error(`validSortOrder("size") = true, want false`)
+	}
+}
+
+func TestGenerateSort(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(map[string]ClusterEntry{
+		"b": {SourceFolder: "material", FileNames: testFiles("zap.svg", "arrow.svg")},
+		"a": {SourceFolder: "feather", FileNames: testFiles("home.svg", "bell.svg")},
+	})
+
+	tests := []struct {
+		order string
+		want  string
+	}{
+		{"", "feather/bell feather/home material/arrow material/zap"},
+		{SortName, "material/arrow feather/bell feather/home material/zap"},
+		{SortCategory, "feather/bell feather/home material/arrow material/zap"},
+		// Clusters by key, files in cluster order
+		{SortNone, "feather/home feather/bell material/zap material/arrow"},
+	}
+	for _, tc := range tests {
+		icons, _ := tt.generate(Options{Sort: tc.order})
+		var files []string
+		for _, icon := range icons {
+			files = append(files, strings.TrimSuffix(strings.TrimPrefix(icon.Image, "/svg_icons/"), ".svg"))
+		}
+		if got := strings.Join(files, " "); got != tc.want {
+			t.Errorf("sort %q = %s, want %s", tc.order, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package svgicons
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+)
+
+// svgImageBase is the URL prefix Icon.Image is served under, from IconsDir
+const svgImageBase = "/svg_icons/"
+
+// iconFileName returns the name an SVG file gets in its icon's detail path: without a
+// leading underscore or the .svg extension, e.g. _home.svg gives home. The underscore of a
+// system icon (HiddenSystem) is kept, _home.svg giving _home.
+func iconFileName(baseName string, system bool) string {
+	if !system {
+		baseName = strings.TrimPrefix(baseName, "_")
+	}
+	return strings.TrimSuffix(baseName, ".svg")
+}
+
+// imageFile returns the file on disk an icon image URL is served from, false when the URL
+// is not under svgImageBase
+func imageFile(image string) (string, bool) {
+	relPath := strings.TrimPrefix(image, svgImageBase)
+	if relPath == image || relPath == "" {
+		return "", false
+	}
+	return iconsFile(relPath), true
+}
+
+// pathForImage returns the detail path of the icon of an image URL,
+// e.g. /svg_icons/feather/_home.svg gives /freedevtools/svg_icons/feather/home/, or
+// /freedevtools/svg_icons/feather/_home/ for a system icon
+func pathForImage(image string, system bool) string {
+	dir, baseName := path.Split(strings.TrimPrefix(image, svgImageBase))
+	return svgIconsBasePath + dir + iconFileName(baseName, system) + "/"
+}
+
+// rebaseIconPaths moves the detail paths of the icons from svgIconsBasePath under basePath,
+// e.g. /freedevtools/svg_icons/feather/home/ under /preview/svg_icons/ gives
+// /preview/svg_icons/feather/home/. Icons are generated under svgIconsBasePath and only moved
+// once done, so their IDs, hash IDs and caches do not depend on where they are deployed.
+func rebaseIconPaths(icons []Icon, basePath string) {
+	if basePath == svgIconsBasePath {
+		return
+	}
+	for i := range icons {
+		if strings.HasPrefix(icons[i].Path, svgIconsBasePath) {
+			icons[i].Path = basePath + strings.TrimPrefix(icons[i].Path, svgIconsBasePath)
+		}
+	}
+}
+
+// checkIconPaths checks that the Image of every icon is an existing file, that its Path is
+// the detail path of that same file, and that no two files share a detail path, as _home.svg
+// and home.svg would. Images of the missing files, already reported, are not checked again.
+// Every mismatch is recorded as a path-mismatch warning; returns their number.
+func checkIconPaths(icons []Icon, missing map[string]bool, report *Report) int {
+	mismatches := 0
+	images := make(map[string]string, len(icons)) // Detail path to the first image with it
+	for _, icon := range icons {
+		file, ok := imageFile(icon.Image)
+		if !ok {
+			report.warn(warnPathMismatch, icon.Image, "Image %s of icon %s is not under %s", icon.Image, icon.ID, svgImageBase)
+			mismatches++
+			continue
+		}
+		if _, err := os.Stat(file); os.IsNotExist(err) && !missing[file] {
+			report.warn(warnPathMismatch, icon.Image, "Image %s of icon %s points to %s, which does not exist", icon.Image, icon.ID, file)
+			mismatches++
+		}
+		if expected := pathForImage(icon.Image, icon.System); icon.Path != expected {
+			report.warn(warnPathMismatch, icon.Image, "Path %s of icon %s does not match its image %s, expected %s", icon.Path, icon.ID, icon.Image, expected)
+			mismatches++
+		}
+		if other, ok := images[icon.Path]; ok && other != icon.Image {
+			report.warn(warnPathMismatch, icon.Image, "Images %s and %s share the detail path %s, rename one of the files", other, icon.Image, icon.Path)
+			mismatches++
+			continue
+		}
+		images[icon.Path] = icon.Image
+	}
+
+	if mismatches > 0 {
+		slog.Warn(fmt.Sprintf("⚠️  Warning: %d mismatches between icon images, detail paths and files", mismatches), "pathMismatches", mismatches)
+	}
+	return mismatches
+}
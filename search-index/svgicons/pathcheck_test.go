@@ -0,0 +1,107 @@
+package svgicons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPathForImage(t *testing.T) {
+	tests := []struct {
+		image  string
+		system bool
+		want   string
+	}{
+		{"/svg_icons/feather/home.svg", false, "/freedevtools/svg_icons/feather/home/"},
+		{"/svg_icons/feather/_home.svg", false, "/freedevtools/svg_icons/feather/home/"},
+		{"/svg_icons/feather/_home.svg", true, "/freedevtools/svg_icons/feather/_home/"},
+		{"/svg_icons/brands/social/twitter.svg", false, "/freedevtools/svg_icons/brands/social/twitter/"},
+	}
+	for _, tc := range tests {
+		if got := pathForImage(tc.image, tc.system); got != tc.want {
+			t.Errorf("pathForImage(%q, %v) = %q, want %q", tc.image, tc.system, got, tc.want)
+		}
+	}
+}
+
+func TestCheckIconPaths(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeSVG("feather", "home.svg", testSVG)
+	tt.writeSVG("feather", "_home.svg", testSVG)
+	tt.writeSVG("feather", "_bell.svg", testSVG)
+
+	tests := []struct {
+		name    string
+		icon    Icon
+		missing bool   // The file was already reported missing
+		want    string // Part of the warning, empty for none
+	}{
+		{"consistent", Icon{Image: "/svg_icons/feather/home.svg", Path: "/freedevtools/svg_icons/feather/home/"}, false, ""},
+		{"stripped underscore", Icon{Image: "/svg_icons/feather/_bell.svg", Path: "/freedevtools/svg_icons/feather/bell/"}, false, ""},
+		{"system underscore", Icon{Image: "/svg_icons/feather/_bell.svg", Path: "/freedevtools/svg_icons/feather/_bell/", System: true}, false, ""},
+		{"no file", Icon{Image: "/svg_icons/feather/cog.svg", Path: "/freedevtools/svg_icons/feather/cog/"}, false, "does not exist"},
+		{"reported missing", Icon{Image: "/svg_icons/feather/cog.svg", Path: "/freedevtools/svg_icons/feather/cog/"}, true, ""},
+		{"other file", Icon{Image: "/svg_icons/feather/home.svg", Path: "/freedevtools/svg_icons/feather/house/"}, false, "does not match its image"},
+		{"outside the image base", Icon{Image: "/png_icons/feather/home.png", Path: "/freedevtools/svg_icons/feather/home/"}, false, "is not under"},
+	}
+	for _, tc := range tests {
+		missing := map[string]bool{}
+		if tc.missing {
+			file, _ := imageFile(tc.icon.Image)
+			missing[file] = true
+		}
+		report := &Report{}
+		mismatches := checkIconPaths([]Icon{tc.icon}, missing, report)
+		warnings := warningsOfKind(report, warnPathMismatch)
+		switch {
+		case tc.want == "" && (mismatches != 0 || len(warnings) != 0):
+			t.Errorf("%s: %d mismatches %+v, want none", tc.name, mismatches, warnings)
+		case tc.want != "" && (mismatches != 1 || len(warnings) != 1 || !strings.Contains(warnings[0].Message, tc.want)):
+			t.Errorf("%s: %d mismatches %+v, want one saying %q", tc.name, mismatches, warnings, tc.want)
+		}
+	}
+}
+
+func TestGenerateReportsSharedDetailPath(t *testing.T) {
+	tt := newTestTree(t)
+	// Stripping the underscore gives both files the detail path of home
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", FileNames: testFiles("home.svg", "_home.svg")},
+	})
+
+	_, report := tt.generate(Options{})
+	warnings := warningsOfKind(report, warnPathMismatch)
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Message, "share the detail path /freedevtools/svg_icons/feather/home/") {
+		t.Errorf("path-mismatch warnings = %+v, want one naming the shared detail path", warnings)
+	}
+}
+
+func TestGenerateBasePath(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(map[string]ClusterEntry{
+		"brands": {SourceFolder: "brands", FileNames: testFiles("home.svg", "social/twitter.svg")},
+	})
+	t.Setenv(BasePathEnv, "")
+	defaults, _ := tt.generate(Options{})
+
+	// Only the detail paths move, IDs stay what they are under the default base path
+	for _, opts := range []Options{{BasePath: "/preview/svg_icons/"}, {BasePath: "/"}} {
+		icons, report := tt.generate(opts)
+		for i, icon := range icons {
+			if icon.ID != defaults[i].ID || icon.Image != defaults[i].Image {
+				t.Errorf("base path %s: icon %s with image %s, want %s and %s", opts.BasePath, icon.ID, icon.Image, defaults[i].ID, defaults[i].Image)
+			}
+			if want := opts.BasePath + strings.TrimPrefix(defaults[i].Path, svgIconsBasePath); icon.Path != want {
+				t.Errorf("base path %s: path %s, want %s", opts.BasePath, icon.Path, want)
+			}
+		}
+		if warnings := warningsOfKind(report, warnPathMismatch); len(warnings) != 0 {
+			t.Errorf("base path %s: path-mismatch warnings %+v, want none", opts.BasePath, warnings)
+		}
+	}
+
+	t.Setenv(BasePathEnv, "/staging")
+	icons, _ := tt.generate(Options{})
+	if home := iconByImage(t, icons, "/svg_icons/brands/home.svg"); home.Path != "/staging/brands/home/" || home.ID != "svg-icons-brands-home" {
+		t.Errorf("%s=/staging: path %s, ID %s; want /staging/brands/home/ and svg-icons-brands-home", BasePathEnv, home.Path, home.ID)
+	}
+}
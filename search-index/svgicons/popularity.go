@@ -0,0 +1,54 @@
+package svgicons
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// PopularityFile is an optional JSON object of usage scores from analytics, keyed by icon ID
+// or display name, e.g. {"svg-icons-feather-home": 1200, "Arrow Up": 300}
+const PopularityFile = "popularity.json"
+
+// popularity holds the scores loaded from PopularityFile, with names lowercased
+var popularity = map[string]int{}
+
+// LoadPopularity reads the usage scores from a JSON file into popularity, applied by Generate.
+// A missing file is not an error: every icon then has a popularity of zero.
+func LoadPopularity(filePath string) error {
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var scores map[string]int
+	if err := json.Unmarshal(content, &scores); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	for key, score := range scores {
+		popularity[strings.ToLower(strings.TrimSpace(key))] = score
+	}
+	return nil
+}
+
+// applyPopularity sets Popularity on every icon from its ID, or else its display name, in the
+// loaded scores. Icons without a score keep zero. Returns the number of icons with a score.
+func applyPopularity(icons []Icon) int {
+	scored := 0
+	for i := range icons {
+		score, ok := popularity[strings.ToLower(icons[i].ID)]
+		if !ok {
+			score, ok = popularity[strings.ToLower(icons[i].Name)]
+		}
+		if ok {
+			icons[i].Popularity = score
+			scored++
+		}
+	}
+	return scored
+}
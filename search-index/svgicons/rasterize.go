@@ -0,0 +1,191 @@
+package svgicons
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+// Raster formats accepted by Options.RasterFormat
+const (
+	RasterPNG  = "png"
+	RasterJPEG = "jpeg" // No transparency, drawn over white
+)
+
+// RasterFormats lists every raster format, the values accepted by --raster-format.
+// WebP is not offered as the standard library and its extensions only decode it.
+var RasterFormats = []string{RasterPNG, RasterJPEG}
+
+// DefaultRasterSize is the width and height in pixels of the rasters when Options.RasterSize is 0
+const DefaultRasterSize = 64
+
+// rasterCurrentColor is what currentColor is drawn in, the text color of a page without styles
+const rasterCurrentColor = "#000000"
+
+// rasterJPEGQuality is the quality JPEG rasters are encoded with
+const rasterJPEGQuality = 90
+
+// rasterFileName returns the file name of the raster of an SVG file,
+// e.g. home.svg at 64 pixels in PNG gives home.64.png
+func rasterFileName(baseName string, size int, format string) string {
+	ext := format
+	if format == RasterJPEG {
+		ext = "jpg"
+	}
+	return fmt.Sprintf("%s.%d.%s", strings.TrimSuffix(baseName, ".svg"), size, ext)
+}
+
+// rasterImage returns the URL the raster of an icon image is served from, next to the SVG
+func rasterImage(image string, size int, format string) string {
+	dir, baseName := path.Split(image)
+	return dir + rasterFileName(baseName, size, format)
+}
+
+// rasterizeSVG renders SVG content to a size x size image, keeping the aspect ratio of its
+// viewBox and centering it. oksvg panics on some malformed paths, which is returned as an error.
+func rasterizeSVG(content []byte, size int) (img *image.RGBA, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("renderer failed: %v", r)
+		}
+	}()
+
+	icon, err := oksvg.ReadReplacingCurrentColor(bytes.NewReader(content), rasterCurrentColor, oksvg.IgnoreErrorMode)
+	if err != nil {
+		return nil, err
+	}
+	viewBox := icon.ViewBox
+	if viewBox.W <= 0 || viewBox.H <= 0 {
+		return nil, fmt.Errorf("no viewBox or size to scale from")
+	}
+
+	scale := float64(size) / math.Max(viewBox.W, viewBox.H)
+	x := (float64(size) - viewBox.W*scale) / 2
+	y := (float64(size) - viewBox.H*scale) / 2
+	icon.Transform = rasterx.Identity.Translate(x, y).Scale(scale, scale).Translate(-viewBox.X, -viewBox.Y)
+
+	img = image.NewRGBA(image.Rect(0, 0, size, size))
+	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
+	icon.Draw(rasterx.NewDasher(size, size, scanner), 1)
+	return img, nil
+}
+
+// encodeRaster encodes a rendered icon in a raster format
+func encodeRaster(img *image.RGBA, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case RasterJPEG:
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, image.Point{}, draw.Over)
+		if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: rasterJPEGQuality}); err != nil {
+			return nil, err
+		}
+	default:
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// rasterUpToDate reports whether a raster exists and is not older than its SVG
+func rasterUpToDate(svgFile, rasterFile string) bool {
+	raster, err := os.Stat(rasterFile)
+	if err != nil {
+		return false
+	}
+	svg, err := os.Stat(svgFile)
+	return err == nil && !raster.ModTime().Before(svg.ModTime())
+}
+
+// rasterizeSVGIcon writes the raster of an icon next to its SVG file unless it is up to date,
+// returning whether it was rendered
+func rasterizeSVGIcon(icon Icon, size int, format string) (bool, error) {
+	svgFile := SourceFile(icon)
+	rasterFile, _ := imageFile(rasterImage(icon.Image, size, format))
+	if rasterUpToDate(svgFile, rasterFile) {
+		return false, nil
+	}
+
+	content, err := ioutil.ReadFile(svgFile)
+	if err != nil {
+		return false, err
+	}
+	img, err := rasterizeSVG(content, size)
+	if err != nil {
+		return false, err
+	}
+	data, err := encodeRaster(img, format)
+	if err != nil {
+		return false, err
+	}
+	return true, writeFileAtomic(rasterFile, data)
+}
+
+// rasterizeSVGIcons writes a size x size raster of every icon next to its SVG file and sets
+// RasterImage, with workers goroutines. Rasters newer than their SVG are kept. An icon that
+// cannot be rendered is recorded as a raster-error warning and left without RasterImage.
+// imageClusters maps images to their cluster for the warnings. Returns the number of rasters
+// rendered and kept.
+func rasterizeSVGIcons(ctx context.Context, icons []Icon, size int, format string, workers int, imageClusters map[string]string, report *Report) (int, int, error) {
+	var mu sync.Mutex
+	rendered, kept := 0, 0
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				written, err := rasterizeSVGIcon(icons[i], size, format)
+				if err != nil {
+					w := newSVGWarning(warnRasterError, icons[i].Image, "Could not rasterize %s, left without a raster fallback: %v", icons[i].Image, err)
+					w.Cluster = imageClusters[icons[i].Image]
+					report.record(w)
+					continue
+				}
+				icons[i].RasterImage = rasterImage(icons[i].Image, size, format)
+				mu.Lock()
+				if written {
+					rendered++
+				} else {
+					kept++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	func() {
+		defer close(indexes)
+		for i := range icons {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+	return rendered, kept, nil
+}
@@ -0,0 +1,80 @@
+package svgicons
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateRasterize(t *testing.T) {
+	tt := newTestTree(t)
+	var names []string
+	for i := 0; i < 20; i++ {
+		names = append(names, fmt.Sprintf("icon-%d.svg", i))
+	}
+	tt.writeSVG("feather", "broken.svg", `<svg xmlns="http://www.w3.org/2000/svg"><path d="M3 12h18"/></svg>`)
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", FileNames: testFiles(append(names, "broken.svg")...)},
+	})
+
+	icons, report := tt.generate(Options{Rasterize: true, RasterSize: 32, Workers: 8})
+	for _, name := range names {
+		icon := iconByImage(t, icons, "/svg_icons/feather/"+name)
+		if want := "/svg_icons/feather/" + strings.TrimSuffix(name, ".svg") + ".32.png"; icon.RasterImage != want {
+			t.Errorf("%s raster = %q, want %q", name, icon.RasterImage, want)
+			continue
+		}
+		file, err := os.Open(IconsDirPath("feather", strings.TrimSuffix(name, ".svg")+".32.png"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, err := png.DecodeConfig(file)
+		file.Close()
+		if err != nil || config.Width != 32 || config.Height != 32 {
+			t.Errorf("%s raster is %dx%d (%v), want a 32x32 PNG", name, config.Width, config.Height, err)
+		}
+	}
+
+	// The icon without a viewBox or size is warned about once, with its cluster, by whichever worker drew it
+	if broken := iconByImage(t, icons, "/svg_icons/feather/broken.svg"); broken.RasterImage != "" {
+		t.Errorf("broken raster = %q, want none", broken.RasterImage)
+	}
+	if warnings := warningsOfKind(report, warnRasterError); len(warnings) != 1 || warnings[0].Cluster != "feather" {
+		t.Errorf("raster-error warnings = %+v, want one for broken.svg in cluster feather", warnings)
+	}
+}
+
+func TestRasterizeSVGIconsKeepsUpToDate(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", FileNames: testFiles("home.svg", "bell.svg")},
+	})
+	icons := []Icon{{Image: "/svg_icons/feather/home.svg"}, {Image: "/svg_icons/feather/bell.svg"}}
+	if rendered, kept, err := rasterizeSVGIcons(context.Background(), icons, 16, RasterJPEG, 2, nil, &Report{}); err != nil || rendered != 2 || kept != 0 {
+		t.Fatalf("first rasterizeSVGIcons = %d rendered, %d kept, %v; want 2, 0", rendered, kept, err)
+	}
+
+	// Only the raster older than its SVG is drawn again
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(IconsDirPath("feather", "bell.svg"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if rendered, kept, err := rasterizeSVGIcons(context.Background(), icons, 16, RasterJPEG, 2, nil, &Report{}); err != nil || rendered != 1 || kept != 1 {
+		t.Errorf("second rasterizeSVGIcons = %d rendered, %d kept, %v; want 1, 1", rendered, kept, err)
+	}
+	if _, err := os.Stat(filepath.Join(IconsDir, "feather", "home.16.jpg")); err != nil {
+		t.Errorf("JPEG raster: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := rasterizeSVGIcons(ctx, icons, 24, RasterPNG, 2, nil, &Report{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("rasterizeSVGIcons after cancel = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,106 @@
+package svgicons
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// relatedCandidate is another icon scored against the icon whose related list is built
+type relatedCandidate struct {
+	index int
+	score float64
+}
+
+// linkRelatedIcons sets Related on every icon to the IDs of the k other icons whose tags
+// are most similar by Jaccard similarity, ties broken by ID. Icons sharing no tag are never
+// related. The icons are split across workers, each writing only the icons it scores.
+// Returns the number of icons with at least one related icon.
+func linkRelatedIcons(ctx context.Context, icons []Icon, k, workers int) (int, error) {
+	// Icons carrying each tag, so only icons sharing a tag are compared
+	tagIcons := make(map[string][]int)
+	for i, icon := range icons {
+		for _, tag := range icon.Tags {
+			tagIcons[tag] = append(tagIcons[tag], i)
+		}
+	}
+
+	related := make([][]string, len(icons))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shared := make(map[int]int)
+			for i := range indexes {
+				related[i] = relatedIconIDs(icons, i, tagIcons, shared, k)
+			}
+		}()
+	}
+
+	func() {
+		defer close(indexes)
+		for i := range icons {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	for i := range icons {
+		icons[i].Related = related[i]
+		if len(related[i]) > 0 {
+			linked++
+		}
+	}
+	return linked, nil
+}
+
+// relatedIconIDs returns the IDs of the k icons most similar to icons[i].
+// shared is scratch space reused between calls by the same worker.
+func relatedIconIDs(icons []Icon, i int, tagIcons map[string][]int, shared map[int]int, k int) []string {
+	for j := range shared {
+		delete(shared, j)
+	}
+	for _, tag := range icons[i].Tags {
+		for _, j := range tagIcons[tag] {
+			if j != i {
+				shared[j]++
+			}
+		}
+	}
+	if len(shared) == 0 {
+		return nil
+	}
+
+	candidates := make([]relatedCandidate, 0, len(shared))
+	for j, common := range shared {
+		union := len(icons[i].Tags) + len(icons[j].Tags) - common
+		candidates = append(candidates, relatedCandidate{index: j, score: float64(common) / float64(union)})
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		if candidates[a].score != candidates[b].score {
+			return candidates[a].score > candidates[b].score
+		}
+		return icons[candidates[a].index].ID < icons[candidates[b].index].ID
+	})
+
+	if len(candidates) > k {
+		candidates = candidates[:k]
+	}
+	ids := make([]string, len(candidates))
+	for n, candidate := range candidates {
+		ids[n] = icons[candidate.index].ID
+	}
+	return ids
+}
@@ -0,0 +1,63 @@
+package svgicons
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func relatedTestIcons() []Icon {
+	return []Icon{
+		{ID: "a", Tags: []string{"arrow", "up"}},
+		{ID: "b", Tags: []string{"arrow", "up", "circle"}},
+		{ID: "c", Tags: []string{"arrow", "down"}},
+		{ID: "d", Tags: []string{"bell"}},
+		{ID: "e", Tags: []string{"cog"}},
+		{ID: "f", Tags: []string{"cog"}},
+		{ID: "g", Tags: []string{"cog"}},
+	}
+}
+
+func TestLinkRelatedIcons(t *testing.T) {
+	want := map[string][]string{
+		"a": {"b", "c"}, // 2/3 then 1/3
+		"b": {"a", "c"}, // 2/3 then 1/4
+		"c": {"a", "b"}, // 1/3 then 1/4
+		"d": nil,        // No shared tag
+		"e": {"f", "g"}, // Ties broken by ID
+		"f": {"e", "g"},
+		"g": {"e", "f"},
+	}
+
+	// Every worker count gives the same lists, each worker writing only the icons it scores
+	for _, workers := range []int{1, 3, 16} {
+		icons := relatedTestIcons()
+		linked, err := linkRelatedIcons(context.Background(), icons, 2, workers)
+		if err != nil {
+			t.Fatalf("%d workers: linkRelatedIcons: %v", workers, err)
+		}
+		if linked != 6 {
+			t.Errorf("%d workers: linked %d icons, want 6", workers, linked)
+		}
+		for _, icon := range icons {
+			if !reflect.DeepEqual(icon.Related, want[icon.ID]) {
+				t.Errorf("%d workers: %s related = %v, want %v", workers, icon.ID, icon.Related, want[icon.ID])
+			}
+		}
+	}
+}
+
+func TestLinkRelatedIconsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	icons := relatedTestIcons()
+	if _, err := linkRelatedIcons(ctx, icons, 2, 4); !errors.Is(err, context.Canceled) {
+		t.Fatalf("linkRelatedIcons after cancel = %v, want context.Canceled", err)
+	}
+	for _, icon := range icons {
+		if icon.Related != nil {
+			t.Errorf("%s related = %v after cancel, want the icons left unchanged", icon.ID, icon.Related)
+		}
+	}
+}
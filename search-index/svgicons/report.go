@@ -0,0 +1,257 @@
+package svgicons
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Warning kinds recorded while generating SVG icons
+const (
+	warnMissingFile      = "missing-file"
+	warnReadError        = "read-error"
+	warnParseError       = "parse-error"
+	warnNoViewBox        = "no-viewbox"
+	warnDuplicateID      = "duplicate-id"
+	warnDuplicateFolder  = "duplicate-folder"   // Source folder used by clusters of several cluster files
+	warnEmptyDescription = "empty-description"  // Icon described by neither its cluster entry nor its SVG
+	warnPathMismatch     = "path-mismatch"      // Image, detail path and file of an icon disagree, see checkIconPaths
+	warnRasterError      = "raster-error"       // SVG the --rasterize renderer could not draw
+	warnEmptyCluster     = "empty-cluster"      // Cluster contributing no icons, every file it lists being ignored or skipped as hidden
+	warnIDPrefixConflict = "id-prefix-conflict" // id_prefix shared by clusters of different source folders, see checkIDPrefixes
+)
+
+// WarningKinds lists every warning kind, the values accepted by --allow
+var WarningKinds = []string{warnMissingFile, warnReadError, warnParseError, warnNoViewBox, warnDuplicateID, warnDuplicateFolder, warnEmptyDescription, warnPathMismatch, warnRasterError, warnEmptyCluster, warnIDPrefixConflict}
+
+// quietWarningKinds are recorded without logging each one, as they are common in the
+// existing catalog and would drown the other warnings; the summary still counts them
+var quietWarningKinds = map[string]bool{warnEmptyDescription: true}
+
+// Warning is a problem found while generating SVG icons that did not stop the run
+type Warning struct {
+	Kind    string `json:"kind"`   // One of the warn* kinds
+	Source  string `json:"source"` // File or icon the warning is about
+	Message string `json:"message"`
+	Detail  string `json:"detail,omitempty"`  // Underlying error, e.g. the XML syntax error of a broken SVG
+	Cluster string `json:"cluster,omitempty"` // Key of the cluster listing the file, when known
+}
+
+func newSVGWarning(kind, source, format string, args ...interface{}) Warning {
+	return Warning{Kind: kind, Source: source, Message: fmt.Sprintf(format, args...)}
+}
+
+// Report collects counts and warnings for a generation run.
+// It is safe for concurrent use by the icon workers.
+type Report struct {
+	mu                sync.Mutex
+	Categories        int
+	Icons             int
+	IconsPerFolder    map[string]int   // Output icons per cluster source folder
+	EmptyDescriptions int              // Cluster files without a description, described by their SVG or the default
+	Collisions        int              // Duplicate IDs resolved with a numeric suffix
+	Ignored           int              // Cluster files left out by IgnoreFile
+	Hidden            int              // Hidden cluster files left out by HiddenSkip
+	Duplicates        int              // Icons folded into aliases by --dedupe
+	Variants          int              // Style variants folded into their base icon by --group-variants
+	ComplexIcons      []svgComplexIcon // Icons above --complexity-threshold, in icon order
+	Warnings          []Warning
+	Errors            []GenerateError // Clusters left out by ContinueOnError and icons failing to read, parse or rasterize
+}
+
+// warn prints a warning and records it for the summary and --strict
+func (r *Report) warn(kind, source, format string, args ...interface{}) {
+	r.record(newSVGWarning(kind, source, format, args...))
+}
+
+// record prints a warning built elsewhere and records it, as an error too when its kind is
+// one of errorPhases
+func (r *Report) record(w Warning) {
+	if quietWarningKinds[w.Kind] {
+		slog.Debug(fmt.Sprintf("⚠️  Warning: %s", w.Message), "kind", w.Kind, "source", w.Source)
+	} else {
+		slog.Warn(fmt.Sprintf("⚠️  Warning: %s", w.Message), "kind", w.Kind, "source", w.Source)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Warnings = append(r.Warnings, w)
+	if phase, ok := errorPhases[w.Kind]; ok {
+		r.Errors = append(r.Errors, GenerateError{Phase: phase, Cluster: w.Cluster, File: w.Source, Message: w.Message})
+	}
+}
+
+// warningCounts returns the number of warnings of each kind
+func (r *Report) warningCounts() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[string]int)
+	for _, w := range r.Warnings {
+		counts[w.Kind]++
+	}
+	return counts
+}
+
+// StrictFailures returns the warnings failing a --strict run: every warning not of an allowed kind
+func (r *Report) StrictFailures(allow []string) []Warning {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var failures []Warning
+	for _, w := range r.Warnings {
+		if !ContainsString(allow, w.Kind) {
+			failures = append(failures, w)
+		}
+	}
+	return failures
+}
+
+// PrintStrictSummary lists the warnings failing a --strict run grouped by kind, each with the
+// file or icon it is about, so CI logs show everything to fix in one place
+func PrintStrictSummary(failures []Warning, allowed int) {
+	slog.Error(fmt.Sprintf("\n🚫 --strict: %d warnings must be fixed", len(failures)))
+
+	byKind := make(map[string][]Warning)
+	for _, w := range failures {
+		byKind[w.Kind] = append(byKind[w.Kind], w)
+	}
+	for _, kind := range WarningKinds {
+		if len(byKind[kind]) == 0 {
+			continue
+		}
+		slog.Error(fmt.Sprintf("   • %s (%d, allow with --allow %s):", kind, len(byKind[kind]), kind))
+		for _, w := range byKind[kind] {
+			slog.Error(fmt.Sprintf("     - %s: %s", w.Source, w.Message), "kind", w.Kind, "source", w.Source)
+		}
+	}
+	if allowed > 0 {
+		slog.Info(fmt.Sprintf("   • %d more warnings of allowed kinds", allowed))
+	}
+}
+
+// svgBrokenFile is an SVG file that is not well-formed, listed in the summary and stats.json
+type svgBrokenFile struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+// brokenFiles returns the SVG files that failed validation, in the order they were reported
+func (r *Report) brokenFiles() []svgBrokenFile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	broken := []svgBrokenFile{}
+	for _, w := range r.Warnings {
+		if w.Kind == warnParseError {
+			broken = append(broken, svgBrokenFile{File: w.Source, Error: w.Detail})
+		}
+	}
+	return broken
+}
+
+// svgComplexIcon is an icon heavier than --complexity-threshold, likely an un-optimized asset
+type svgComplexIcon struct {
+	ID         string `json:"id"`
+	Image      string `json:"image"`
+	Complexity int    `json:"complexity"`
+}
+
+// PrintSummary prints the counts and warnings of the run
+func (r *Report) PrintSummary() {
+	slog.Info("\n📋 SVG icons summary:")
+	slog.Info(fmt.Sprintf("   • Categories: %d", r.Categories))
+	slog.Info(fmt.Sprintf("   • Icons: %d", r.Icons))
+	if r.Ignored > 0 {
+		slog.Info(fmt.Sprintf("   • Ignored: %d", r.Ignored))
+	}
+	if r.Hidden > 0 {
+		slog.Info(fmt.Sprintf("   • Hidden skipped: %d", r.Hidden))
+	}
+	if r.Duplicates > 0 {
+		slog.Info(fmt.Sprintf("   • Duplicates folded: %d", r.Duplicates))
+	}
+	if r.Variants > 0 {
+		slog.Info(fmt.Sprintf("   • Variants grouped: %d", r.Variants))
+	}
+	slog.Info(fmt.Sprintf("   • Warnings: %d", len(r.Warnings)))
+
+	counts := r.warningCounts()
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		slog.Info(fmt.Sprintf("     - %s: %d", kind, counts[kind]))
+	}
+
+	if broken := r.brokenFiles(); len(broken) > 0 {
+		slog.Info(fmt.Sprintf("   • Broken SVGs (not well-formed, blank in browsers): %d", len(broken)))
+		for _, file := range broken {
+			slog.Info(fmt.Sprintf("     - %s: %s", file.File, file.Error))
+		}
+	}
+
+	if len(r.ComplexIcons) > 0 {
+		slog.Info(fmt.Sprintf("   • Complex icons (worth optimizing): %d", len(r.ComplexIcons)))
+		for _, icon := range r.ComplexIcons {
+			slog.Info(fmt.Sprintf("     - %s: complexity %d", icon.Image, icon.Complexity))
+		}
+	}
+}
+
+// StatsFile is the machine-readable report of an SVG icons run, for dashboards
+const StatsFile = "stats.json"
+
+// svgStatsVersion is bumped whenever a field of svgStats is renamed, removed or changes
+// meaning. Adding a field does not change the version.
+const svgStatsVersion = 1
+
+// svgStats is the schema of stats.json
+type svgStats struct {
+	Version           int              `json:"version"`
+	TotalIcons        int              `json:"totalIcons"`
+	Categories        int              `json:"categories"`
+	IconsPerFolder    map[string]int   `json:"iconsPerFolder"`
+	EmptyDescriptions int              `json:"emptyDescriptions"`
+	Collisions        int              `json:"collisionsResolved"`
+	Ignored           int              `json:"ignored"`
+	Hidden            int              `json:"hiddenSkipped"`
+	Duplicates        int              `json:"duplicatesFolded"`
+	Variants          int              `json:"variantsGrouped"`
+	Warnings          map[string]int   `json:"warnings"`
+	BrokenFiles       []svgBrokenFile  `json:"brokenFiles"`
+	ComplexIcons      []svgComplexIcon `json:"complexIcons"`
+	DurationMs        int64            `json:"durationMs"`
+}
+
+// complexIcons returns ComplexIcons, empty rather than nil for stats.json
+func (r *Report) complexIcons() []svgComplexIcon {
+	if r.ComplexIcons == nil {
+		return []svgComplexIcon{}
+	}
+	return r.ComplexIcons
+}
+
+// Stats returns the report in the stats.json schema
+func (r *Report) Stats(duration time.Duration) svgStats {
+	return svgStats{
+		Version:           svgStatsVersion,
+		TotalIcons:        r.Icons,
+		Categories:        r.Categories,
+		IconsPerFolder:    r.IconsPerFolder,
+		EmptyDescriptions: r.EmptyDescriptions,
+		Collisions:        r.Collisions,
+		Ignored:           r.Ignored,
+		Hidden:            r.Hidden,
+		Duplicates:        r.Duplicates,
+		Variants:          r.Variants,
+		Warnings:          r.warningCounts(),
+		BrokenFiles:       r.brokenFiles(),
+		ComplexIcons:      r.complexIcons(),
+		DurationMs:        duration.Milliseconds(),
+	}
+}
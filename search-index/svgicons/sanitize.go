@@ -0,0 +1,50 @@
+package svgicons
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/clipperhouse/jargon/filters/ascii"
+)
+
+// invalidIDChars matches the characters SanitizeID replaces, compiled once as it runs for every icon
+var invalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9\-_]`)
+
+// SanitizeID replaces invalid characters with underscores
+// Only allows alphanumeric characters, hyphens, and underscores.
+// Accented Latin and Cyrillic letters are transliterated first, so "café" becomes "cafe".
+func SanitizeID(id string) string {
+	return invalidIDChars.ReplaceAllString(transliterate(id), "_")
+}
+
+// cyrillicLatin transliterates Russian and Ukrainian letters, following the scientific
+// romanization without diacritics. Upper case letters are lowered before lookup.
+var cyrillicLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'ё': "e", 'є': "ye",
+	'ж': "zh", 'з': "z", 'и': "i", 'і': "i", 'ї': "yi", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh",
+	'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e",
+	'ю': "yu", 'я': "ya",
+}
+
+// transliterate folds accented Latin letters to ASCII (ü -> u, ß -> ss) and romanizes
+// Cyrillic, keeping the case of the first letter. Other scripts are left unchanged.
+func transliterate(s string) string {
+	folded, _ := ascii.FoldString(s)
+
+	var b strings.Builder
+	for _, r := range folded {
+		lower := unicode.ToLower(r)
+		latin, ok := cyrillicLatin[lower]
+		switch {
+		case !ok:
+			b.WriteRune(r)
+		case lower != r && latin != "":
+			b.WriteString(strings.ToUpper(latin[:1]) + latin[1:])
+		default:
+			b.WriteString(latin)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package svgicons
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestSanitizeID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"svg-icons-feather-home", "svg-icons-feather-home"},
+		{"svg-icons-foo bar-home", "svg-icons-foo_bar-home"},
+		{"svg-icons-a-home.old", "svg-icons-a-home_old"},
+		// Accented Latin letters are folded instead of replaced
+		{"café", "cafe"},
+		{"über-alles", "uber-alles"},
+		{"ÜberCool", "UberCool"},
+		{"straße", "strasse"},
+		// Cyrillic is romanized, keeping the case of the first letter
+		{"привет-мир", "privet-mir"},
+		{"Щука", "Shchuka"},
+		{"Ёж", "Ezh"},
+		{"объём", "obem"},
+		// Scripts without a transliteration are still replaced
+		{"日本-icon", "__-icon"},
+	}
+	for _, tc := range tests {
+		if got := SanitizeID(tc.id); got != tc.want {
+			t.Errorf("SanitizeID(%q) = %q, want %q", tc.id, got, tc.want)
+		}
+	}
+}
+
+// benchmarkIDs are names of the shapes SanitizeID sees in the catalog: plain ASCII,
+// spaces and punctuation, accented letters and Cyrillic
+var benchmarkIDs = []string{"arrow-up-circle", "Home Page (filled)", "café crème", "стрелка вверх"}
+
+// BenchmarkSanitizeID guards the cost of the ID sanitizing done for every icon. The
+// RecompiledPattern case compiles the pattern on every call, as SanitizeID once did.
+func BenchmarkSanitizeID(b *testing.B) {
+	b.Run("Precompiled", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			SanitizeID(benchmarkIDs[i%len(benchmarkIDs)])
+		}
+	})
+	b.Run("RecompiledPattern", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			regexp.MustCompile(invalidIDChars.String()).ReplaceAllString(transliterate(benchmarkIDs[i%len(benchmarkIDs)]), "_")
+		}
+	})
+}
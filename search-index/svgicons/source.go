@@ -0,0 +1,401 @@
+package svgicons
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSourceTimeout is the longest a single download of a remote source may take
+const DefaultSourceTimeout = 2 * time.Minute
+
+var (
+	sourceRootsMu sync.RWMutex
+	sourceRoots   = map[string]string{} // Source folder to the local directory holding its SVG files, set by OpenSources
+)
+
+// folderRoot returns the directory the SVG files of a source folder are read from: the
+// directory opened for it by OpenSources, IconsDir/<sourceFolder> otherwise
+func folderRoot(sourceFolder string) string {
+	sourceRootsMu.RLock()
+	defer sourceRootsMu.RUnlock()
+	if root, ok := sourceRoots[sourceFolder]; ok {
+		return root
+	}
+	return filepath.Join(IconsDir, sourceFolder)
+}
+
+// iconsFile returns the file on disk of a path relative to IconsDir, such as
+// feather/home.svg, following the longest source folder opened by OpenSources
+func iconsFile(relPath string) string {
+	sourceRootsMu.RLock()
+	defer sourceRootsMu.RUnlock()
+	best := ""
+	for folder := range sourceRoots {
+		if (relPath == folder || strings.HasPrefix(relPath, folder+"/")) && len(folder) > len(best) {
+			best = folder
+		}
+	}
+	if best == "" {
+		return filepath.Join(IconsDir, filepath.FromSlash(relPath))
+	}
+	return filepath.Join(sourceRoots[best], filepath.FromSlash(strings.TrimPrefix(relPath[len(best):], "/")))
+}
+
+// sourceOpen reports whether OpenSources opened the source of a source folder
+func sourceOpen(sourceFolder string) bool {
+	sourceRootsMu.RLock()
+	defer sourceRootsMu.RUnlock()
+	_, ok := sourceRoots[sourceFolder]
+	return ok
+}
+
+// Sources are the SVG files of the clusters read from somewhere else than IconsDir: a local
+// directory, an HTTP base URL or a tarball. Remote ones are copied to temporary directories,
+// so everything reading SVG files works on local copies until Close
+type Sources struct {
+	client   *http.Client
+	folders  []string          // Source folders opened, unregistered by Close
+	tempDirs []string          // Removed by Close
+	tarballs map[string]string // Tarball location to the directory it was extracted to
+	remote   int               // Clusters read from a URL or tarball
+	files    int               // SVG files downloaded from HTTP base URLs
+	missing  int               // SVG files an HTTP base URL did not have
+}
+
+// sourceSpec is a source split into its location and the optional subdirectory given after
+// a #, e.g. https://example.com/feather-4.29.tgz#feather-4.29/icons
+type sourceSpec struct {
+	location string
+	subdir   string
+}
+
+func parseSourceSpec(source string) sourceSpec {
+	location, subdir := source, ""
+	if i := strings.LastIndex(source, "#"); i >= 0 {
+		location, subdir = source[:i], strings.Trim(path.Clean("/"+source[i+1:]), "/")
+	}
+	return sourceSpec{location: location, subdir: subdir}
+}
+
+func (s sourceSpec) isURL() bool {
+	lower := strings.ToLower(s.location)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+func (s sourceSpec) isTarball() bool {
+	lower := strings.ToLower(s.location)
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") || strings.HasSuffix(lower, ".tar")
+}
+
+// OpenSources makes the SVG files of every selected cluster with a source, its own "source"
+// or Options.Source, available locally, downloading and extracting remote ones. Options.Source
+// is laid out like IconsDir, with a subdirectory per source folder, while the source of a
+// cluster holds the cluster's files directly. Call Close once done with the SVG files.
+func OpenSources(ctx context.Context, opts Options) (*Sources, error) {
+	timeout := opts.SourceTimeout
+	if timeout <= 0 {
+		timeout = DefaultSourceTimeout
+	}
+	s := &Sources{client: &http.Client{Timeout: timeout}, tarballs: make(map[string]string)}
+
+	// Cluster files that do not load are reported by Generate and Validate, with their
+	// line and column, before anything would read the SVG files
+	clusterPaths, err := opts.ResolveClusterPaths()
+	if err != nil {
+		return s, nil
+	}
+	cluster, _, err := opts.LoadClusters(clusterPaths)
+	if err != nil {
+		return s, nil
+	}
+
+	keys := make([]string, 0, len(cluster.Clusters))
+	for key := range cluster.Clusters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		entry := cluster.Clusters[key]
+		source, ownSource := entry.Source, entry.Source != ""
+		if !ownSource {
+			source = opts.Source
+		}
+		if source == "" || !opts.MatchesFolder(entry.SourceFolder) || folderIgnored(entry.SourceFolder) {
+			continue
+		}
+		root, err := s.open(ctx, parseSourceSpec(source), entry, ownSource, opts.WorkerCount())
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("cluster %q: %w", key, err)
+		}
+		sourceRootsMu.Lock()
+		sourceRoots[entry.SourceFolder] = root
+		sourceRootsMu.Unlock()
+		s.folders = append(s.folders, entry.SourceFolder)
+	}
+
+	if s.remote > 0 && opts.Rasterize {
+		s.Close()
+		return nil, fmt.Errorf("rasterizing writes next to the SVG files, which are temporary copies for the %d clusters read from a URL or tarball", s.remote)
+	}
+	if len(s.folders) > 0 {
+		slog.Info(fmt.Sprintf("📦 Opened the sources of %d clusters, %d remote, downloaded %d SVG files (%d not found)", len(s.folders), s.remote, s.files, s.missing), "clusters", len(s.folders), "remote", s.remote, "downloaded", s.files, "notFound", s.missing)
+	}
+	return s, nil
+}
+
+// Close removes the temporary copies of the remote sources. The SVG files are read from
+// IconsDir again afterwards.
+func (s *Sources) Close() error {
+	sourceRootsMu.Lock()
+	for _, folder := range s.folders {
+		delete(sourceRoots, folder)
+	}
+	sourceRootsMu.Unlock()
+	s.folders = nil
+
+	var errs []error
+	for _, dir := range s.tempDirs {
+		if err := os.RemoveAll(dir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	s.tempDirs = nil
+	return errors.Join(errs...)
+}
+
+// open returns the local directory holding the SVG files of a cluster read from spec
+func (s *Sources) open(ctx context.Context, spec sourceSpec, entry ClusterEntry, ownSource bool, workers int) (string, error) {
+	subdir := spec.subdir
+	if !ownSource {
+		subdir = path.Join(subdir, filepath.ToSlash(entry.SourceFolder))
+	}
+
+	switch {
+	case spec.isTarball():
+		s.remote++
+		dir, ok := s.tarballs[spec.location]
+		if !ok {
+			var err error
+			if dir, err = s.extract(ctx, spec); err != nil {
+				return "", err
+			}
+			s.tarballs[spec.location] = dir
+		}
+		return filepath.Join(dir, filepath.FromSlash(subdir)), nil
+	case spec.isURL():
+		s.remote++
+		baseURL := strings.TrimSuffix(spec.location, "/")
+		if subdir != "" {
+			baseURL += "/" + subdir
+		}
+		return s.download(ctx, baseURL, entry, workers)
+	default:
+		root := filepath.Join(spec.location, filepath.FromSlash(subdir))
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return "", fmt.Errorf("source directory %s not found", root)
+		}
+		return root, nil
+	}
+}
+
+// get starts a GET of rawURL, stopped by ctx or the timeout of the client
+func (s *Sources) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req)
+}
+
+// extract downloads or opens a tarball and extracts it to a new temporary directory
+func (s *Sources) extract(ctx context.Context, spec sourceSpec) (string, error) {
+	var r io.Reader
+	if spec.isURL() {
+		resp, err := s.get(ctx, spec.location)
+		if err != nil {
+			return "", fmt.Errorf("failed to download %s: %w", spec.location, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("failed to download %s: %s", spec.location, resp.Status)
+		}
+		r = resp.Body
+	} else {
+		file, err := os.Open(spec.location)
+		if err != nil {
+			return "", fmt.Errorf("failed to open %s: %w", spec.location, err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	dir, err := os.MkdirTemp("", "svg-source-")
+	if err != nil {
+		return "", err
+	}
+	s.tempDirs = append(s.tempDirs, dir)
+	if err := extractTarball(ctx, r, !strings.HasSuffix(strings.ToLower(spec.location), ".tar"), dir); err != nil {
+		return "", fmt.Errorf("failed to extract %s: %w", spec.location, err)
+	}
+	return dir, nil
+}
+
+// extractTarball writes the directories and regular files of a tar stream under dir. Entry
+// names are cleaned so none can leave dir, and links are skipped.
+func extractTarball(ctx context.Context, r io.Reader, gzipped bool, dir string) error {
+	if gzipped {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		relPath := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		if relPath == "" {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(relPath))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeStream(target, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeStream creates a file and its parent directories with the content of r
+func writeStream(target string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// download fetches the SVG files of a cluster from an HTTP base URL into a new temporary
+// directory, with up to workers requests at once. Files the server does not have (404) are
+// left out, to be reported as missing files like local ones.
+func (s *Sources) download(ctx context.Context, baseURL string, entry ClusterEntry, workers int) (string, error) {
+	dir, err := os.MkdirTemp("", "svg-source-")
+	if err != nil {
+		return "", err
+	}
+	s.tempDirs = append(s.tempDirs, dir)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var firstErr error
+	relPaths := make(chan string)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for relPath := range relPaths {
+				found, err := s.downloadFile(ctx, baseURL, relPath, dir)
+				mu.Lock()
+				switch {
+				case err != nil && firstErr == nil:
+					firstErr = err
+					cancel()
+				case err == nil && found:
+					s.files++
+				case err == nil:
+					s.missing++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	func() {
+		defer close(relPaths)
+		for _, fileName := range entry.FileNames {
+			select {
+			case relPaths <- svgRelativePath(fileName.FileName):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	if firstErr != nil {
+		return "", firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// downloadFile fetches baseURL/relPath to dir/relPath, false when the server answers 404
+func (s *Sources) downloadFile(ctx context.Context, baseURL, relPath, dir string) (bool, error) {
+	segments := strings.Split(relPath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	fileURL := baseURL + "/" + strings.Join(segments, "/")
+
+	resp, err := s.get(ctx, fileURL)
+	if err != nil {
+		return false, fmt.Errorf("failed to download %s: %w", fileURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to download %s: %s", fileURL, resp.Status)
+	}
+	if err := writeStream(filepath.Join(dir, filepath.FromSlash(relPath)), resp.Body); err != nil {
+		return false, fmt.Errorf("failed to download %s: %w", fileURL, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,249 @@
+package svgicons
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testTarball builds a tar stream of files by entry name, gzipped unless plain is set.
+// Names ending in / are directories and a "->" in a name makes a symbolic link.
+func testTarball(t *testing.T, plain bool, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var header tar.Header
+		switch {
+		case strings.HasSuffix(name, "/"):
+			header = tar.Header{Name: name, Typeflag: tar.TypeDir, Mode: 0755}
+		case strings.Contains(name, "->"):
+			parts := strings.SplitN(name, "->", 2)
+			header = tar.Header{Name: parts[0], Typeflag: tar.TypeSymlink, Linkname: parts[1], Mode: 0644}
+		default:
+			header = tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(files[name]))}
+		}
+		if err := tw.WriteHeader(&header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			tw.Write([]byte(files[name]))
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if plain {
+		return buf.Bytes()
+	}
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write(buf.Bytes())
+	gz.Close()
+	return gzipped.Bytes()
+}
+
+func TestParseSourceSpec(t *testing.T) {
+	tests := []struct {
+		source   string
+		location string
+		subdir   string
+		tarball  bool
+		url      bool
+	}{
+		{"../vendor/feather", "../vendor/feather", "", false, false},
+		{"https://example.com/feather-4.29.tgz#feather-4.29/icons", "https://example.com/feather-4.29.tgz", "feather-4.29/icons", true, true},
+		{"packs/feather.tar#/icons/", "packs/feather.tar", "icons", true, false},
+		{"packs/feather.TAR.GZ#../../icons", "packs/feather.TAR.GZ", "icons", true, false},
+		{"HTTP://cdn.example.com/icons", "HTTP://cdn.example.com/icons", "", false, true},
+	}
+	for _, tc := range tests {
+		spec := parseSourceSpec(tc.source)
+		if spec.location != tc.location || spec.subdir != tc.subdir || spec.isTarball() != tc.tarball || spec.isURL() != tc.url {
+			t.Errorf("parseSourceSpec(%q) = %+v, tarball %v, URL %v; want %s, %q, %v, %v", tc.source, spec, spec.isTarball(), spec.isURL(), tc.location, tc.subdir, tc.tarball, tc.url)
+		}
+	}
+}
+
+func TestExtractTarball(t *testing.T) {
+	files := map[string]string{
+		"feather/":                     "",
+		"feather/home.svg":             testSVG,
+		"./feather/social/x.svg":       "x",
+		"../../escape.svg":             "escape",
+		"/absolute.svg":                "absolute",
+		"feather/link.svg->/etc/hosts": "",
+	}
+	for _, plain := range []bool{false, true} {
+		dir := t.TempDir()
+		if err := extractTarball(context.Background(), bytes.NewReader(testTarball(t, plain, files)), !plain, dir); err != nil {
+			t.Fatalf("plain %v: extractTarball: %v", plain, err)
+		}
+
+		// Every entry stays inside dir, and links are not followed or created
+		want := map[string]string{
+			"feather/home.svg":     testSVG,
+			"feather/social/x.svg": "x",
+			"escape.svg":           "escape",
+			"absolute.svg":         "absolute",
+		}
+		got := make(map[string]string)
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				rel, _ := filepath.Rel(dir, path)
+				content, _ := ioutil.ReadFile(path)
+				got[filepath.ToSlash(rel)] = string(content)
+			}
+			return err
+		})
+		if len(got) != len(want) {
+			t.Errorf("plain %v: extracted %v, want %v", plain, got, want)
+		}
+		for name, content := range want {
+			if got[name] != content {
+				t.Errorf("plain %v: %s = %q, want %q", plain, name, got[name], content)
+			}
+		}
+	}
+}
+
+func TestExtractTarballErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tarball := testTarball(t, false, map[string]string{"home.svg": testSVG})
+	if err := extractTarball(ctx, bytes.NewReader(tarball), true, t.TempDir()); !errors.Is(err, context.Canceled) {
+		t.Errorf("extractTarball after cancel = %v, want context.Canceled", err)
+	}
+	if err := extractTarball(context.Background(), bytes.NewReader([]byte("not a tarball")), true, t.TempDir()); err == nil {
+		t.Error("extractTarball of garbage succeeded")
+	}
+	if err := extractTarball(context.Background(), bytes.NewReader(tarball[:len(tarball)/2]), true, t.TempDir()); err == nil {
+		t.Error("extractTarball of a truncated tarball succeeded")
+	}
+}
+
+func TestOpenSourcesTarball(t *testing.T) {
+	tt := newTestTree(t)
+	tarball := filepath.Join(tt.root, "feather-4.29.tgz")
+	content := testTarball(t, false, map[string]string{"feather-4.29/icons/home.svg": testSVG, "feather-4.29/icons/social/bell.svg": testSVG})
+	if err := ioutil.WriteFile(tarball, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tt.writeFile(DefaultClusterPath, `{"clusters": {"feather": {"source_folder": "feather", "source": "`+filepath.ToSlash(tarball)+`#feather-4.29/icons", "fileNames": [{"fileName": "home.svg"}, {"fileName": "social/bell.svg"}]}}}`)
+
+	sources, err := OpenSources(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("OpenSources: %v", err)
+	}
+	root := FilePath("feather", "home.svg")
+	icons, _ := tt.generate(Options{})
+	if len(icons) != 2 || iconByImage(t, icons, "/svg_icons/feather/social/bell.svg").ViewBox != "0 0 24 24" {
+		t.Errorf("icons = %+v, want both icons read from the tarball", icons)
+	}
+
+	// Close removes the extracted copy and files are looked up in IconsDir again
+	if err := sources.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Errorf("%s still exists after Close", root)
+	}
+	if got := FilePath("feather", "home.svg"); got != IconsDirPath("feather", "home.svg") {
+		t.Errorf("FilePath after Close = %s, want %s", got, IconsDirPath("feather", "home.svg"))
+	}
+}
+
+func TestOpenSourcesHTTP(t *testing.T) {
+	tt := newTestTree(t)
+	tarball := testTarball(t, false, map[string]string{"material/cog.svg": testSVG})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feather/home.svg", "/feather/social%20media/x.svg", "/feather/social media/x.svg":
+			w.Write([]byte(testSVG))
+		case "/packs/icons.tgz":
+			w.Write(tarball)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	// A base URL for one cluster, a tarball URL laid out like IconsDir for the others
+	tt.writeFile(DefaultClusterPath, `{"clusters": {
+		"feather": {"source_folder": "feather", "source": "`+server.URL+`/feather", "fileNames": [{"fileName": "home.svg"}, {"fileName": "social media/x.svg"}, {"fileName": "gone.svg"}]},
+		"material": {"source_folder": "material", "fileNames": [{"fileName": "cog.svg"}]}
+	}}`)
+	sources, err := OpenSources(context.Background(), Options{Source: server.URL + "/packs/icons.tgz"})
+	if err != nil {
+		t.Fatalf("OpenSources: %v", err)
+	}
+	defer sources.Close()
+	if sources.remote != 2 || sources.files != 2 || sources.missing != 1 {
+		t.Errorf("opened %d remote clusters, downloaded %d files with %d missing; want 2, 2, 1", sources.remote, sources.files, sources.missing)
+	}
+
+	icons, report := tt.generate(Options{Source: server.URL + "/packs/icons.tgz"})
+	if len(icons) != 4 {
+		t.Errorf("got %d icons, want 4", len(icons))
+	}
+	if missing := warningsOfKind(report, warnMissingFile); len(missing) != 1 || !strings.Contains(missing[0].Message, "gone.svg") {
+		t.Errorf("missing-file warnings = %+v, want gone.svg", missing)
+	}
+}
+
+func TestOpenSourcesHTTPTimeout(t *testing.T) {
+	tt := newTestTree(t)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", Source: server.URL, FileNames: testFiles("home.svg")},
+	})
+
+	start := time.Now()
+	_, err := OpenSources(context.Background(), Options{SourceTimeout: 50 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), `cluster "feather"`) {
+		t.Errorf("OpenSources of a stalled server = %v, want an error naming the cluster", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("OpenSources took %v, want it stopped by the timeout", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := OpenSources(ctx, Options{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("OpenSources after cancel = %v, want context.Canceled", err)
+	}
+}
+
+func TestOpenSourcesMissingDirectory(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(map[string]ClusterEntry{
+		"feather": {SourceFolder: "feather", Source: filepath.Join(tt.root, "nowhere"), FileNames: testFiles("home.svg")},
+	})
+	if _, err := OpenSources(context.Background(), Options{}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("OpenSources of a missing directory = %v, want a not found error", err)
+	}
+}
@@ -0,0 +1,54 @@
+package svgicons
+
+import (
+	"strings"
+	"unicode"
+)
+
+// descriptionEllipsis ends a truncated description, counted as one character
+const descriptionEllipsis = "…"
+
+// TruncateText shortens text to at most maxLen characters including a trailing ellipsis,
+// cutting at the last word boundary that fits, or mid-word only when the first word alone
+// is too long. Text of at most maxLen characters is returned as is, as is any text when
+// maxLen is 0 or less.
+func TruncateText(text string, maxLen int) string {
+	runes := []rune(text)
+	if maxLen <= 0 || len(runes) <= maxLen {
+		return text
+	}
+
+	cut := maxLen - 1 // Room for the ellipsis
+	if !unicode.IsSpace(runes[cut]) {
+		// Back up to the start of the word that does not fit
+		for i := cut; i > 0; i-- {
+			if unicode.IsSpace(runes[i-1]) {
+				cut = i
+				break
+			}
+		}
+	}
+
+	kept := strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(",;:-", r)
+	})
+	return kept + descriptionEllipsis
+}
+
+// truncateDescriptions caps the description of every icon at maxLen characters, keeping the
+// full text in DescriptionFull when keepFull is set. Returns the number of icons truncated.
+func truncateDescriptions(icons []Icon, maxLen int, keepFull bool) int {
+	truncated := 0
+	for i := range icons {
+		description := TruncateText(icons[i].Description, maxLen)
+		if description == icons[i].Description {
+			continue
+		}
+		if keepFull {
+			icons[i].DescriptionFull = icons[i].Description
+		}
+		icons[i].Description = description
+		truncated++
+	}
+	return truncated
+}
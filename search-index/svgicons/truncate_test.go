@@ -0,0 +1,62 @@
+package svgicons
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		text   string
+		maxLen int
+		want   string
+	}{
+		// Exactly maxLen characters, or fewer, is left alone
+		{"The quick brown fox", 19, "The quick brown fox"},
+		{"The quick brown fox", 20, "The quick brown fox"},
+		// A cut mid-word backs up to the previous word
+		{"The quick brown fox", 18, "The quick brown…"},
+		// A cut on a space keeps the whole word before it
+		{"The quick brown fox", 16, "The quick brown…"},
+		// A single word too long is cut mid-word
+		{"Supercalifragilistic", 6, "Super…"},
+		{"Supercalifragilistic", 1, "…"},
+		// Trailing punctuation does not come before the ellipsis
+		{"Home, garden and yard", 8, "Home…"},
+		// Characters are counted, not bytes
+		{"Café über alles", 10, "Café über…"},
+		{"The quick brown fox", 0, "The quick brown fox"},
+		{"", 5, ""},
+	}
+	for _, tc := range tests {
+		got := TruncateText(tc.text, tc.maxLen)
+		if got != tc.want {
+			t.Errorf("TruncateText(%q, %d) = %q, want %q", tc.text, tc.maxLen, got, tc.want)
+		}
+		if tc.maxLen > 0 && utf8.RuneCountInString(got) > tc.maxLen {
+			t.Errorf("TruncateText(%q, %d) has %d characters", tc.text, tc.maxLen, utf8.RuneCountInString(got))
+		}
+	}
+}
+
+func TestTruncateDescriptions(t *testing.T) {
+	icons := []Icon{
+		{Description: "A house with a chimney"},
+		{Description: "A bell"},
+	}
+	if got := truncateDescriptions(icons, 12, true); got != 1 {
+		t.Errorf("truncateDescriptions = %d, want 1", got)
+	}
+	if icons[0].Description != "A house…" || icons[0].DescriptionFull != "A house with a chimney" {
+		t.Errorf("truncated icon = %q, %q; want the cut and the full text", icons[0].Description, icons[0].DescriptionFull)
+	}
+	if icons[1].Description != "A bell" || icons[1].DescriptionFull != "" {
+		t.Errorf("short icon = %q, %q; want it untouched", icons[1].Description, icons[1].DescriptionFull)
+	}
+
+	icons = []Icon{{Description: "A house with a chimney"}}
+	truncateDescriptions(icons, 12, false)
+	if icons[0].DescriptionFull != "" {
+		t.Errorf("DescriptionFull = %q without keepFull, want empty", icons[0].DescriptionFull)
+	}
+}
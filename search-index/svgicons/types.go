@@ -0,0 +1,70 @@
+package svgicons
+
+// Icon is the search record of an SVG icon
+type Icon struct {
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	Description     string            `json:"description"`
+	DescriptionFull string            `json:"descriptionFull,omitempty"` // Description before --max-description-length, with --keep-full-description
+	Path            string            `json:"path"`
+	Image           string            `json:"image"` // Changed from "imagePath" to "image" to match Python
+	Category        string            `json:"category"`
+	Collection      string            `json:"collection"` // Author of the icon collection, from the cluster's author
+	License         string            `json:"license"`    // License of the icon collection, from the cluster's license
+	LicenseURL      string            `json:"licenseUrl"` // Where the collection and its license are published, from the cluster's source_url
+	AriaLabel       string            `json:"ariaLabel"`  // Suggested aria-label: the SVG <title>, or the name followed by "icon"
+	Width           float64           `json:"width,omitempty"`
+	Height          float64           `json:"height,omitempty"`
+	ViewBox         string            `json:"viewBox,omitempty"`
+	Colors          []string          `json:"colors,omitempty"`
+	Monochrome      bool              `json:"monochrome,omitempty"`     // Only uses currentColor, so it can be themed
+	Aliases         []string          `json:"aliases,omitempty"`        // IDs of identical icons folded into this one by --dedupe
+	Tags            []string          `json:"tags,omitempty"`           // Lowercased words of the file name, e.g. arrow, up, circle
+	Keywords        []string          `json:"keywords,omitempty"`       // Search keywords authored in the cluster entry, e.g. settings, gear for cog
+	Related         []string          `json:"related,omitempty"`        // IDs of the icons sharing the most tags, from --related
+	DataURI         string            `json:"dataUri,omitempty"`        // Minified SVG as a base64 data URI, from --inline-svg
+	RasterImage     string            `json:"rasterImage,omitempty"`    // PNG or JPEG fallback next to the SVG, from --rasterize
+	Complexity      int               `json:"complexity,omitempty"`     // Drawing elements plus path commands, 0 if the SVG could not be parsed
+	OriginalBytes   int               `json:"originalBytes,omitempty"`  // Size of the source SVG, from --optimize
+	OptimizedBytes  int               `json:"optimizedBytes,omitempty"` // Size of the SVG written by --optimize
+	Popularity      int               `json:"popularity,omitempty"`     // Usage score from popularity.json, 0 when not listed
+	Variants        map[string]string `json:"variants,omitempty"`       // Style to ID of the style variants folded into this icon by --group-variants
+	ModifiedAt      string            `json:"modifiedAt,omitempty"`     // Last change of the SVG file in RFC 3339 UTC, from its mtime or git, omitted when unknown
+	System          bool              `json:"system,omitempty"`         // Hidden file (leading underscore) indexed as an internal or system icon, from --hidden system
+}
+
+// Cluster represents the structure of cluster_svg.json
+type Cluster struct {
+	Clusters map[string]ClusterEntry `json:"clusters"`
+}
+
+// ClusterEntry represents a single cluster in the SVG icons data
+type ClusterEntry struct {
+	Name         string     `json:"name"`
+	SourceFolder string     `json:"source_folder"`
+	Path         string     `json:"path"`
+	Keywords     []string   `json:"keywords"`
+	Features     []string   `json:"features"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	FileNames    []FileName `json:"fileNames"`
+	Enhanced     bool       `json:"enhanced"`
+	Author       string     `json:"author"`     // Optional author of the icon collection, for attribution
+	License      string     `json:"license"`    // Optional license of the icon collection, e.g. MIT
+	SourceURL    string     `json:"source_url"` // Optional page of the collection and its license
+	Source       string     `json:"source"`     // Optional directory, HTTP base URL or tarball holding the files, see OpenSources
+	IDPrefix     string     `json:"id_prefix"`  // Optional prefix of the cluster's icon IDs instead of svg-icons-<source_folder>, see ClusterIconID
+}
+
+// FileName represents a file entry in the cluster with all available fields
+type FileName struct {
+	FileName      string   `json:"fileName"`
+	Description   string   `json:"description"`
+	Usecases      string   `json:"usecases"`
+	Synonyms      []string `json:"synonyms"`
+	Tags          []string `json:"tags"`
+	Keywords      []string `json:"keywords"` // Search keywords copied to Icon.Keywords, ranking like the name
+	Industry      string   `json:"industry"`
+	EmotionalCues string   `json:"emotional_cues"`
+	Enhanced      bool     `json:"enhanced"`
+}
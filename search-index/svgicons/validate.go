@@ -0,0 +1,90 @@
+package svgicons
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// ValidationResult is what Validate found in the cluster files
+type ValidationResult struct {
+	ClusterFiles int
+	Clusters     int
+	Files        int            // SVG files listed by the checked clusters
+	Issues       []ClusterIssue // In cluster file order, then by line and column
+}
+
+// Validate checks the cluster files without generating anything: every file must parse and
+// pass the checks of LoadClusters, every SVG listed by a cluster selected by Folder and not
+// ignored by LoadIgnore must exist, and no source folder may list the same file twice. Every
+// problem is returned as an issue at the line of the cluster key it is about; the SVG files
+// are not read, so it stays fast on large catalogs. An error is returned only when a cluster file could not be checked at all.
+func (o Options) Validate() (*ValidationResult, error) {
+	clusterPaths, err := o.ResolveClusterPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ValidationResult{ClusterFiles: len(clusterPaths)}
+	keyFiles := make(map[string]string)
+	listedBy := make(map[string]string) // Source folder and file to the cluster listing it first
+
+	for _, clusterPath := range clusterPaths {
+		cluster, content, err := o.readClusterFile(clusterPath)
+		var clusterErr *svgClusterError
+		if errors.As(err, &clusterErr) {
+			result.Issues = append(result.Issues, clusterErr.Issues...)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		offsets, _ := clusterKeyOffsets(content)
+		issueAt := func(key, format string, args ...interface{}) ClusterIssue {
+			line, column := lineColumn(content, offsets[key])
+			return ClusterIssue{File: clusterPath, Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+		}
+
+		keys := make([]string, 0, len(cluster.Clusters))
+		for key := range cluster.Clusters {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return offsets[keys[i]] < offsets[keys[j]] })
+
+		for _, key := range keys {
+			if other, ok := keyFiles[key]; ok {
+				result.Issues = append(result.Issues, issueAt(key, "cluster %q is also defined in %s", key, other))
+				continue
+			}
+			keyFiles[key] = clusterPath
+
+			entry := cluster.Clusters[key]
+			if !o.MatchesFolder(entry.SourceFolder) || folderIgnored(entry.SourceFolder) {
+				continue
+			}
+			result.Clusters++
+
+			for i, fileName := range entry.FileNames {
+				if fileIgnored(entry.SourceFolder, fileName.FileName) {
+					continue
+				}
+				result.Files++
+				relPath := svgRelativePath(fileName.FileName)
+				listing := entry.SourceFolder + "/" + relPath
+				if first, ok := listedBy[listing]; ok {
+					result.Issues = append(result.Issues, issueAt(key, "cluster %q lists %s at fileNames[%d], already listed by %s", key, relPath, i, first))
+					continue
+				}
+				listedBy[listing] = fmt.Sprintf("cluster %q", key)
+
+				svgFile := FilePath(entry.SourceFolder, fileName.FileName)
+				if _, err := os.Stat(svgFile); os.IsNotExist(err) {
+					result.Issues = append(result.Issues, issueAt(key, "cluster %q lists %s at fileNames[%d] but %s does not exist", key, relPath, i, svgFile))
+				}
+			}
+		}
+	}
+	return result, nil
+}
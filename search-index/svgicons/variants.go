@@ -0,0 +1,141 @@
+package svgicons
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultVariantSuffixes are the style suffixes --group-variants recognizes, e.g. home-filled
+var DefaultVariantSuffixes = []string{"filled", "outline", "solid", "regular", "duotone"}
+
+// variantStyle splits a variant suffix off an icon file name, e.g. home-filled gives home and
+// filled. The suffix must follow a hyphen or underscore and match case-insensitively.
+// Names without a known suffix are returned as is with an empty style.
+func variantStyle(iconName string, suffixes []string) (string, string) {
+	lower := strings.ToLower(iconName)
+	for _, suffix := range suffixes {
+		suffix = strings.ToLower(suffix)
+		if len(lower) <= len(suffix)+1 || !strings.HasSuffix(lower, suffix) {
+			continue
+		}
+		base := iconName[:len(iconName)-len(suffix)]
+		if sep := base[len(base)-1]; sep == '-' || sep == '_' {
+			return base[:len(base)-1], suffix
+		}
+	}
+	return iconName, ""
+}
+
+// groupSVGVariants folds the style variants of an icon, e.g. home-filled and home-outline in
+// the same folder, into a single record listing them in Variants by style. The icon without a
+// suffix is the base record when there is one, otherwise the first variant in suffix order is,
+// renamed to drop its suffix. A name with a single variant and no base is left alone.
+// The icons keep their order. Returns the kept icons and the number of icons folded.
+func groupSVGVariants(icons []Icon, suffixes []string) ([]Icon, int) {
+	type variantGroup struct {
+		base     int            // Index in icons of the plain icon, -1 if there is none
+		styles   map[string]int // Style to index in icons
+		baseName string         // File name without the suffix
+	}
+
+	groups := make(map[string]*variantGroup)
+	var keys []string
+	for i, icon := range icons {
+		dir, name := iconPathName(icon.Path)
+		base, style := variantStyle(name, suffixes)
+		key := dir + base
+		group, ok := groups[key]
+		if !ok {
+			group = &variantGroup{base: -1, styles: make(map[string]int), baseName: base}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		if style == "" {
+			if group.base < 0 {
+				group.base = i
+			}
+		} else if _, taken := group.styles[style]; !taken {
+			group.styles[style] = i
+		}
+	}
+
+	suffixOrder := make(map[string]int, len(suffixes))
+	for i, suffix := range suffixes {
+		suffixOrder[strings.ToLower(suffix)] = i
+	}
+
+	folded := make(map[int]int) // Index of a folded variant to index of its base record
+	for _, key := range keys {
+		group := groups[key]
+		if len(group.styles) == 0 || (group.base < 0 && len(group.styles) == 1) {
+			continue
+		}
+
+		styles := make([]string, 0, len(group.styles))
+		for style := range group.styles {
+			styles = append(styles, style)
+		}
+		sort.Slice(styles, func(i, j int) bool { return suffixOrder[styles[i]] < suffixOrder[styles[j]] })
+
+		base := group.base
+		if base < 0 {
+			base = group.styles[styles[0]]
+			renameVariantBase(&icons[base], group.baseName)
+		}
+
+		icons[base].Variants = make(map[string]string, len(styles))
+		for _, style := range styles {
+			i := group.styles[style]
+			icons[base].Variants[style] = icons[i].ID
+			if !ContainsString(icons[base].Tags, style) {
+				icons[base].Tags = append(icons[base].Tags, style)
+			}
+			if i != base {
+				folded[i] = base
+			}
+		}
+	}
+
+	kept := make([]Icon, 0, len(icons)-len(folded))
+	keptIndex := make(map[int]int, len(icons))
+	for i, icon := range icons {
+		if _, ok := folded[i]; ok {
+			continue
+		}
+		keptIndex[i] = len(kept)
+		kept = append(kept, icon)
+	}
+	// Identical icons folded into a variant by --dedupe now belong to its base record
+	for i, base := range folded {
+		if aliases := icons[i].Aliases; len(aliases) > 0 {
+			record := &kept[keptIndex[base]]
+			record.Aliases = append(record.Aliases, aliases...)
+			sort.Strings(record.Aliases)
+		}
+	}
+
+	return kept, len(folded)
+}
+
+// renameVariantBase gives a variant standing in for its group the name of the group, keeping
+// the ID, path and image of its own file. A generated description and aria label follow the new name.
+func renameVariantBase(icon *Icon, baseName string) {
+	oldName := icon.Name
+	icon.Name = cleanText(FormatIconName(strings.TrimPrefix(baseName, "_")))
+	icon.Tags = iconTags(strings.TrimPrefix(baseName, "_"))
+	if icon.Description == fmt.Sprintf("SVG icon for %s", oldName) {
+		icon.Description = fmt.Sprintf("SVG icon for %s", icon.Name)
+	}
+	if icon.AriaLabel == ariaLabel(oldName, "") {
+		icon.AriaLabel = ariaLabel(icon.Name, "")
+	}
+}
+
+// iconPathName splits an icon page path into its folder and icon name,
+// e.g. /freedevtools/svg_icons/feather/home/ gives /freedevtools/svg_icons/feather/ and home
+func iconPathName(iconPath string) (string, string) {
+	trimmed := strings.TrimSuffix(iconPath, "/")
+	slash := strings.LastIndex(trimmed, "/")
+	return trimmed[:slash+1], trimmed[slash+1:]
+}
@@ -0,0 +1,138 @@
+package svgicons
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVariantStyle(t *testing.T) {
+	tests := []struct {
+		iconName  string
+		wantBase  string
+		wantStyle string
+	}{
+		{"home-filled", "home", "filled"},
+		{"home_outline", "home", "outline"},
+		{"Home-Solid", "Home", "solid"},
+		{"arrow-up-duotone", "arrow-up", "duotone"},
+		{"home", "home", ""},
+		// The suffix must be a whole word after a separator
+		{"unfilled", "unfilled", ""},
+		{"homefilled", "homefilled", ""},
+		{"-filled", "-filled", ""},
+		{"filled", "filled", ""},
+	}
+	for _, tc := range tests {
+		base, style := variantStyle(tc.iconName, DefaultVariantSuffixes)
+		if base != tc.wantBase || style != tc.wantStyle {
+			t.Errorf("variantStyle(%q) = %q, %q; want %q, %q", tc.iconName, base, style, tc.wantBase, tc.wantStyle)
+		}
+	}
+	if base, style := variantStyle("home-sharp", []string{"Sharp"}); base != "home" || style != "sharp" {
+		t.Errorf("variantStyle with a custom suffix = %q, %q; want home, sharp", base, style)
+	}
+}
+
+// variantIcon is an icon of folder as generated before grouping
+func variantIcon(folder, name string) Icon {
+	return Icon{
+		ID:          "svg-icons-" + folder + "-" + name,
+		Name:        FormatIconName(name),
+		Description: "SVG icon for " + FormatIconName(name),
+		Path:        svgIconsBasePath + folder + "/" + name + "/",
+		AriaLabel:   ariaLabel(FormatIconName(name), ""),
+		Tags:        iconTags(name),
+	}
+}
+
+func TestGroupSVGVariants(t *testing.T) {
+	tests := []struct {
+		name       string
+		icons      []Icon
+		wantIDs    []string
+		wantGroups map[string]map[string]string // Kept ID to its Variants
+		wantFolded int
+	}{
+		{
+			name:    "plain icon is the base",
+			icons:   []Icon{variantIcon("a", "home-outline"), variantIcon("a", "home"), variantIcon("a", "home-filled")},
+			wantIDs: []string{"svg-icons-a-home"},
+			wantGroups: map[string]map[string]string{
+				"svg-icons-a-home": {"filled": "svg-icons-a-home-filled", "outline": "svg-icons-a-home-outline"},
+			},
+			wantFolded: 2,
+		},
+		{
+			name:    "first suffix stands in for a missing base",
+			icons:   []Icon{variantIcon("a", "bell-outline"), variantIcon("a", "bell-filled")},
+			wantIDs: []string{"svg-icons-a-bell-filled"},
+			wantGroups: map[string]map[string]string{
+				"svg-icons-a-bell-filled": {"filled": "svg-icons-a-bell-filled", "outline": "svg-icons-a-bell-outline"},
+			},
+			wantFolded: 1,
+		},
+		{
+			name:       "a single variant is left alone",
+			icons:      []Icon{variantIcon("a", "cog-solid"), variantIcon("a", "user")},
+			wantIDs:    []string{"svg-icons-a-cog-solid", "svg-icons-a-user"},
+			wantFolded: 0,
+		},
+		{
+			name:       "folders are grouped separately",
+			icons:      []Icon{variantIcon("a", "home"), variantIcon("b", "home-filled")},
+			wantIDs:    []string{"svg-icons-a-home", "svg-icons-b-home-filled"},
+			wantFolded: 0,
+		},
+	}
+	for _, tc := range tests {
+		kept, folded := groupSVGVariants(tc.icons, DefaultVariantSuffixes)
+		var ids []string
+		for _, icon := range kept {
+			ids = append(ids, icon.ID)
+			if want := tc.wantGroups[icon.ID]; !reflect.DeepEqual(icon.Variants, want) {
+				t.Errorf("%s: %s variants = %v, want %v", tc.name, icon.ID, icon.Variants, want)
+			}
+		}
+		if !reflect.DeepEqual(ids, tc.wantIDs) || folded != tc.wantFolded {
+			t.Errorf("%s: kept %v, folded %d; want %v, %d", tc.name, ids, folded, tc.wantIDs, tc.wantFolded)
+		}
+	}
+}
+
+func TestGroupSVGVariantsRenamesBase(t *testing.T) {
+	kept, _ := groupSVGVariants([]Icon{variantIcon("a", "bell-outline"), variantIcon("a", "bell-filled")}, DefaultVariantSuffixes)
+	bell := kept[0]
+	if bell.Name != "Bell" || bell.Description != "SVG icon for Bell" || bell.AriaLabel != "Bell icon" {
+		t.Errorf("base record = %q, %q, %q; want it named after the group", bell.Name, bell.Description, bell.AriaLabel)
+	}
+	// The styles are searchable from the base record
+	if !reflect.DeepEqual(bell.Tags, []string{"bell", "filled", "outline"}) {
+		t.Errorf("tags = %v, want [bell filled outline]", bell.Tags)
+	}
+}
+
+func TestGroupSVGVariantsMovesAliases(t *testing.T) {
+	outline := variantIcon("a", "home-outline")
+	outline.Aliases = []string{"svg-icons-a-house-outline"}
+	kept, _ := groupSVGVariants([]Icon{variantIcon("a", "home"), outline}, DefaultVariantSuffixes)
+	if len(kept) != 1 || !reflect.DeepEqual(kept[0].Aliases, []string{"svg-icons-a-house-outline"}) {
+		t.Errorf("kept %+v, want home holding the aliases of its outline variant", kept)
+	}
+}
+
+func TestGenerateVariantSuffixes(t *testing.T) {
+	tt := newTestTree(t)
+	tt.writeClusters(map[string]ClusterEntry{
+		"a": {SourceFolder: "a", FileNames: testFiles("home.svg", "home-sharp.svg", "home-filled.svg")},
+	})
+
+	// Only the configured suffixes are variants
+	icons, report := tt.generate(Options{GroupVariants: true, VariantSuffixes: []string{"sharp"}})
+	if len(icons) != 2 || report.Variants != 1 {
+		t.Fatalf("got %d icons, %d folded; want home-filled kept and home-sharp folded", len(icons), report.Variants)
+	}
+	home := iconByImage(t, icons, "/svg_icons/a/home.svg")
+	if !reflect.DeepEqual(home.Variants, map[string]string{"sharp": "svg-icons-a-home-sharp"}) {
+		t.Errorf("home variants = %v, want only sharp", home.Variants)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	jargon_stemmer "search-index/jargon-stemmer"
+)
+
+// svgIndexFile is the inverted index written next to svg_icons.json
+const svgIndexFile = "svg_icons_index.json"
+
+// synonymWeight scales the term frequency of tokens added from synonyms.json, so icons
+// matching the query term itself rank above icons only matching a synonym of it
+const synonymWeight = 0.5
+
+// SearchIndex maps each stemmed token to the icons whose name or description contains it
+type SearchIndex struct {
+	TotalDocuments int                    `json:"totalDocuments"`
+	Tokens         map[string]*IndexEntry `json:"tokens"`
+	NGramSize      int                    `json:"ngramSize,omitempty"`
+	NGrams         map[string][]string    `json:"ngrams,omitempty"`   // Character n-gram to icon IDs, only with --ngrams
+	Phonetic       map[string][]string    `json:"phonetic,omitempty"` // Double Metaphone code to icon IDs, only with --phonetic
+}
+
+// IndexEntry lists the icons containing a token
+type IndexEntry struct {
+	DocumentFrequency int       `json:"df"`      // Number of icons containing the token
+	IDs               []string  `json:"ids"`     // Icon IDs in output order
+	Weights           []float64 `json:"weights"` // TF-IDF weight of the token for each icon in IDs
+}
+
+// buildSearchIndex builds the inverted index from the stemmed Name, Tags and Description of each
+// icon, each token counted at the boost of its field, plus their synonyms at a reduced weight.
+// Weights are raised slightly for popular icons.
+func buildSearchIndex(icons []SVGIconData, stemOpts *jargon_stemmer.Options, ngramSize int, boosts fieldBoosts) *SearchIndex {
+	index := &SearchIndex{
+		TotalDocuments: len(icons),
+		Tokens:         make(map[string]*IndexEntry),
+	}
+
+	// Term frequencies per icon, kept until document frequencies are known
+	frequencies := make([]map[string]float64, len(icons))
+
+	for i, icon := range icons {
+		nameWords := stemTokens(icon.Name, stemOpts)
+		descriptionWords := stemTokens(icon.Description, stemOpts)
+		tagWords := stemTokens(strings.Join(icon.Tags, " "), stemOpts)
+		// Authored keywords are as telling as the name, so they count at its boost
+		nameWords = append(nameWords, stemTokens(strings.Join(icon.Keywords, " "), stemOpts)...)
+		frequencies[i] = boostedTermFrequencies(nameWords, tagWords, descriptionWords, boosts)
+		tokens := append(append(append([]string{}, nameWords...), descriptionWords...), tagWords...)
+		for _, synonym := range jargon_stemmer.ExpandSynonyms(tokens) {
+			frequencies[i][synonym] = synonymWeight / float64(len(tokens))
+		}
+		for _, token := range sortedKeys(frequencies[i]) {
+			entry, ok := index.Tokens[token]
+			if !ok {
+				entry = &IndexEntry{}
+				index.Tokens[token] = entry
+			}
+			entry.DocumentFrequency++
+			entry.IDs = append(entry.IDs, icon.ID)
+		}
+	}
+
+	// Weights follow the order of IDs, which were appended in icon order
+	for i := range icons {
+		for _, token := range sortedKeys(frequencies[i]) {
+			entry := index.Tokens[token]
+			idf := inverseDocumentFrequency(entry.DocumentFrequency, index.TotalDocuments)
+			entry.Weights = append(entry.Weights, roundWeight(frequencies[i][token]*idf*popularityBoost(icons[i].Popularity)))
+		}
+	}
+
+	if ngramSize > 0 {
+		index.NGramSize = ngramSize
+		index.NGrams = buildNGrams(icons, ngramSize)
+	}
+
+	return index
+}
+
+// buildSVGSearchIndex builds the search index of the SVG icons with the options of the run
+func buildSVGSearchIndex(icons []SVGIconData, opts svgOptions) *SearchIndex {
+	index := buildSearchIndex(icons, opts.stemOptions(), opts.ngramSize(), svgFieldBoosts)
+	// Kept apart from Tokens so sound-alike matches never boost exact matches
+	if opts.Phonetic {
+		index.Phonetic = buildPhoneticIndex(icons)
+	}
+	return index
+}
+
+// buildNGrams maps the character n-grams of every lowercased word of each icon's Name and
+// Description to the icon IDs, so clients can match inside words ("row" finds "arrow").
+// The words are not stemmed, since stemming cuts the endings users type.
+func buildNGrams(icons []SVGIconData, n int) map[string][]string {
+	grams := make(map[string][]string)
+	for _, icon := range icons {
+		seen := make(map[string]bool)
+		for _, word := range nameTokens(icon.Name + " " + icon.Description) {
+			for _, gram := range ngrams(word, n) {
+				if seen[gram] {
+					continue
+				}
+				seen[gram] = true
+				grams[gram] = append(grams[gram], icon.ID)
+			}
+		}
+	}
+	return grams
+}
+
+// ngrams returns the n-rune substrings of word in order, e.g. "arrow" with n 3 gives
+// "arr", "rro", "row". Words shorter than n give none.
+func ngrams(word string, n int) []string {
+	runes := []rune(word)
+	if n <= 0 || len(runes) < n {
+		return nil
+	}
+	grams := make([]string, 0, len(runes)-n+1)
+	for i := 0; i+n <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+n]))
+	}
+	return grams
+}
+
+// inverseDocumentFrequency is log(N/df). Tokens present in every icon get 0,
+// since they cannot tell icons apart.
+func inverseDocumentFrequency(documentFrequency, totalDocuments int) float64 {
+	if documentFrequency == 0 || totalDocuments == 0 {
+		return 0
+	}
+	return math.Log(float64(totalDocuments) / float64(documentFrequency))
+}
+
+// roundWeight keeps weights short and stable in the JSON output
+func roundWeight(weight float64) float64 {
+	return math.Round(weight*1e6) / 1e6
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stemTokens runs text through the stemmer and returns the lowercased word tokens,
+// dropping punctuation
+func stemTokens(text string, stemOpts *jargon_stemmer.Options) []string {
+	var tokens []string
+	for _, token := range strings.Fields(jargon_stemmer.ProcessTextOptions(text, *stemOpts)) {
+		token = strings.ToLower(token)
+		if strings.IndexFunc(token, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
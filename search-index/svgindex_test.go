@@ -0,0 +1,157 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	jargon_stemmer "search-index/jargon-stemmer"
+)
+
+func TestNGrams(t *testing.T) {
+	tests := []struct {
+		word string
+		n    int
+		want []string
+	}{
+		{"arrow", 3, []string{"arr", "rro", "row"}},
+		{"arrow", 2, []string{"ar", "rr", "ro", "ow"}},
+		{"row", 3, []string{"row"}},
+		{"up", 3, nil},
+		{"café", 3, []string{"caf", "afé"}},
+		{"arrow", 0, nil},
+	}
+	for _, tt := range tests {
+		if got := ngrams(tt.word, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ngrams(%q, %d) = %q, want %q", tt.word, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestBuildSearchIndexNGrams(t *testing.T) {
+	icons := []SVGIconData{
+		{ID: "svg-icons-a-arrow", Name: "Arrow", Description: "Points below"},
+		{ID: "svg-icons-a-home", Name: "Home"},
+	}
+
+	index := buildSearchIndex(icons, svgOptions{}.stemOptions(), 3, defaultFieldBoosts)
+	if index.NGramSize != 3 {
+		t.Errorf("NGramSize = %d, want 3", index.NGramSize)
+	}
+	// Substrings of the name and description, listing each icon once
+	if got := index.NGrams["row"]; !reflect.DeepEqual(got, []string{"svg-icons-a-arrow"}) {
+		t.Errorf(`NGrams["row"] = %v, want the arrow icon`, got)
+	}
+	if got := index.NGrams["low"]; !reflect.DeepEqual(got, []string{"svg-icons-a-arrow"}) {
+		t.Errorf(`NGrams["low"] = %v, want the arrow icon from its description`, got)
+	}
+	if _, ok := index.Tokens["row"]; ok {
+		t.Error(`n-gram "row" is in Tokens, want it kept apart from the exact tokens`)
+	}
+
+	// Off unless asked for
+	if index := buildSearchIndex(icons, svgOptions{}.stemOptions(), 0, defaultFieldBoosts); index.NGrams != nil || index.NGramSize != 0 {
+		t.Errorf("ngramSize 0 built %d n-grams of size %d, want none", len(index.NGrams), index.NGramSize)
+	}
+}
+
+func TestBuildSearchIndexKeywords(t *testing.T) {
+	icons := []SVGIconData{
+		{ID: "svg-icons-a-cog", Name: "Cog", Keywords: []string{"settings", "gear"}},
+		{ID: "svg-icons-a-sliders", Name: "Sliders", Description: "Adjust the settings"},
+		{ID: "svg-icons-a-home", Name: "Home"},
+	}
+	index := buildSearchIndex(icons, svgOptions{}.stemOptions(), 0, defaultFieldBoosts)
+
+	// Authored keywords are searchable and rank like the name
+	if got := index.Tokens["gear"]; got == nil || !reflect.DeepEqual(got.IDs, []string{"svg-icons-a-cog"}) {
+		t.Fatalf(`Tokens["gear"] = %+v, want the cog icon`, got)
+	}
+	keyword, description := indexWeight(index, "set", "svg-icons-a-cog"), indexWeight(index, "set", "svg-icons-a-sliders")
+	if description == 0 || keyword <= description {
+		t.Errorf(`"settings" weighs %v as a keyword and %v in a description, want the keyword first`, keyword, description)
+	}
+}
+
+func TestStemStepKeywords(t *testing.T) {
+	useOutputDir(t, true)
+	if err := saveToJSON("svg_icons.json", []SVGIconData{{ID: "svg-icons-a-cog", Name: "Cog", Keywords: []string{"settings", "gear"}}}); err != nil {
+		t.Fatal(err)
+	}
+	filePath := filepath.Join(outputDir, "svg_icons.json")
+	if err := jargon_stemmer.ProcessJSONFileWithOptions(filePath, gzip.DefaultCompression, svgOptions{}.stemOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	var records []map[string]interface{}
+	if err := json.Unmarshal(readOutput(t, "svg_icons.json"), &records); err != nil {
+		t.Fatal(err)
+	}
+	if got := records[0]["altKeywords"]; got != "set gear" {
+		t.Errorf("altKeywords = %v, want the stemmed keywords", got)
+	}
+}
+
+func TestIndexOnlySynonymsChange(t *testing.T) {
+	useOutputDir(t, true)
+	synonymsPath := filepath.Join(t.TempDir(), jargon_stemmer.SynonymsFile)
+	opts := svgOptions{}
+	loadSynonyms := func() {
+		if err := jargon_stemmer.LoadSynonymsOptions(synonymsPath, *opts.stemOptions()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The missing file leaves the other tests without synonyms
+	t.Cleanup(func() {
+		os.Remove(synonymsPath)
+		loadSynonyms()
+	})
+	if err := saveToJSON("svg_icons.json", []SVGIconData{{ID: "svg-icons-a-home", Name: "Home", Description: "Home page"}}); err != nil {
+		t.Fatal(err)
+	}
+	indexOnly := func() (map[string]interface{}, *SearchIndex) {
+		t.Helper()
+		loadSynonyms()
+		if err := runSVGIndexOnly(context.Background(), opts, time.Now()); err != nil {
+			t.Fatalf("runSVGIndexOnly: %v", err)
+		}
+		var records []map[string]interface{}
+		if err := json.Unmarshal(readOutput(t, "svg_icons.json"), &records); err != nil {
+			t.Fatal(err)
+		}
+		var index SearchIndex
+		if err := json.Unmarshal(readOutput(t, svgIndexFile), &index); err != nil {
+			t.Fatal(err)
+		}
+		return records[0], &index
+	}
+
+	if err := ioutil.WriteFile(synonymsPath, []byte(`[["home", "house"]]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	record, index := indexOnly()
+	if got := record["altSynonyms"]; got != "hous" {
+		t.Errorf("altSynonyms = %v, want hous", got)
+	}
+	if index.Tokens["hous"] == nil {
+		t.Error("the synonym hous is not indexed")
+	}
+
+	// Deleting the dictionary drops the synonyms of the last run from both files
+	if err := os.Remove(synonymsPath); err != nil {
+		t.Fatal(err)
+	}
+	record, index = indexOnly()
+	if got, ok := record["altSynonyms"]; ok {
+		t.Errorf("altSynonyms = %v after deleting %s, want none", got, jargon_stemmer.SynonymsFile)
+	}
+	if index.Tokens["hous"] != nil {
+		t.Errorf("hous still indexed after deleting %s", jargon_stemmer.SynonymsFile)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	jargon_stemmer "search-index/jargon-stemmer"
+)
+
+// loadSVGIndexOnlyIcons reads the icons --index-only rebuilds the index of: the existing SVG
+// icons JSON output, which must be there and hold at least one icon
+func loadSVGIndexOnlyIcons(opts svgOptions) ([]SVGIconData, error) {
+	filePath := filepath.Join(outputDir, opts.svgJSONFile())
+	icons, err := loadPreviousSVGIcons(opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w, regenerate it without --index-only", err)
+	}
+	if icons == nil {
+		return nil, fmt.Errorf("--index-only rebuilds the index from %s, which does not exist; generate it without --index-only first", filePath)
+	}
+	if len(icons) == 0 {
+		return nil, fmt.Errorf("%s holds no icons to index, regenerate it without --index-only", filePath)
+	}
+	return icons, nil
+}
+
+// loadExistingSVGIndex reads the search index of the last run, nil if there is none or it
+// cannot be read, since it is only compared with
+func loadExistingSVGIndex() *SearchIndex {
+	content, err := ioutil.ReadFile(filepath.Join(outputDir, svgIndexFile))
+	if err != nil {
+		return nil
+	}
+	var index SearchIndex
+	if err := json.Unmarshal(content, &index); err != nil {
+		return nil
+	}
+	return &index
+}
+
+// printSVGIndexChanges prints how many tokens the rebuilt index adds and drops compared with
+// the existing one, listing the first of each, and how many kept tokens match other icons or
+// weigh them differently
+func printSVGIndexChanges(existing, index *SearchIndex) {
+	if existing == nil {
+		slog.Info(fmt.Sprintf("   • No existing %s to compare with", svgIndexFile))
+		return
+	}
+
+	var added, removed []string
+	changed := 0
+	for token, entry := range index.Tokens {
+		old, ok := existing.Tokens[token]
+		if !ok {
+			added = append(added, token)
+			continue
+		}
+		if fmt.Sprint(old.IDs, old.Weights) != fmt.Sprint(entry.IDs, entry.Weights) {
+			changed++
+		}
+	}
+	for token := range existing.Tokens {
+		if _, ok := index.Tokens[token]; !ok {
+			removed = append(removed, token)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	slog.Info(fmt.Sprintf("   • Against the existing %s: %d tokens added, %d removed, %d reweighted", svgIndexFile, len(added), len(removed), changed), "added", len(added), "removed", len(removed), "reweighted", changed)
+	printSVGDiffIDs("Added tokens", added)
+	printSVGDiffIDs("Removed tokens", removed)
+}
+
+// runSVGIndexOnly rebuilds the stemmed fields, search index and autocomplete of the existing
+// svg_icons.json with the current stemmer, synonyms, stop words and boosts for --index-only,
+// and its --emit-offsets offsets, without reading the cluster or SVG files. With --dry-run it only prints how the index
+// would change.
+func runSVGIndexOnly(ctx context.Context, opts svgOptions, start time.Time) error {
+	icons, err := loadSVGIndexOnlyIcons(opts)
+	if err != nil {
+		return err
+	}
+	filePath := filepath.Join(outputDir, opts.svgJSONFile())
+	slog.Info(fmt.Sprintf("📂 --index-only: loaded %d icons from %s, skipping cluster processing", len(icons), filePath), "iconCount", len(icons))
+
+	slog.Info("\n🗂️ Building search index...")
+	index := buildSVGSearchIndex(icons, opts)
+	autocomplete := buildAutocomplete(icons, rankPopular)
+	slog.Info(fmt.Sprintf("   • Search index tokens: %d", len(index.Tokens)))
+	slog.Info(fmt.Sprintf("   • Autocomplete prefixes: %d", len(autocomplete)))
+	printSVGIndexChanges(loadExistingSVGIndex(), index)
+
+	var tokens tokenFrequencyReport
+	if opts.ReportTokens {
+		tokens = buildTokenFrequencies(icons, index, opts)
+		printTokenFrequencies(tokens)
+	}
+
+	if opts.DryRun {
+		slog.Info(fmt.Sprintf("\n🧪 Dry run completed in %v, no files were written", time.Since(start)), "category", "svg_icons", "iconCount", len(icons), "elapsed", time.Since(start).String())
+		return nil
+	}
+
+	// Re-stem the existing file, replacing the alt fields of the previous stemmer
+	slog.Info("\n🔍 Running stem processing...")
+	if err := jargon_stemmer.ProcessJSONFileContext(ctx, filePath, opts.GzipLevel, svgStemOptions(icons, opts)); err != nil {
+		return fmt.Errorf("Stem processing failed: %w", err)
+	}
+	ndjsonPath := filepath.Join(outputDir, "svg_icons.ndjson")
+	if _, err := os.Stat(ndjsonPath); err == nil && opts.hasFormat("ndjson") {
+		if err := jargon_stemmer.ProcessNDJSONFileWithOptions(ndjsonPath, opts.stemOptions()); err != nil {
+			return fmt.Errorf("Stem processing failed: %w", err)
+		}
+	}
+	slog.Info("✅ Stem processing completed!")
+
+	// Re-stemming moves the icon records, so their offsets are rebuilt too
+	if err := saveSVGOffsets(opts); err != nil {
+		return err
+	}
+
+	if err := saveToJSON(svgIndexFile, index); err != nil {
+		return fmt.Errorf("Failed to save search index: %w", err)
+	}
+	slog.Info(fmt.Sprintf("💾 Indexed %d tokens to %s", len(index.Tokens), filepath.Join(outputDir, svgIndexFile)))
+	if opts.ReportTokens {
+		if err := saveToJSON(svgTokenFrequencyFile, tokens); err != nil {
+			return fmt.Errorf("Failed to save token frequencies: %w", err)
+		}
+		slog.Info(fmt.Sprintf("💾 Token frequencies saved to %s", filepath.Join(outputDir, svgTokenFrequencyFile)))
+	}
+	if err := saveToJSON(svgAutocompleteFile, autocomplete); err != nil {
+		return fmt.Errorf("Failed to save autocomplete data: %w", err)
+	}
+	slog.Info(fmt.Sprintf("💾 Saved %d autocomplete prefixes to %s", len(autocomplete), filepath.Join(outputDir, svgAutocompleteFile)))
+
+	slog.Info(fmt.Sprintf("\n🎉 SVG icons index rebuilt in %v", time.Since(start)), "category", "svg_icons", "iconCount", len(icons), "elapsed", time.Since(start).String())
+	return checkSVGOutputBudget(opts)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	jargon_stemmer "search-index/jargon-stemmer"
+	"search-index/svgicons"
+)
+
+// svgManifestFile records the input and output hashes of the last SVG icons run
+const svgManifestFile = ".manifest.json"
+
+// svgManifest lets a run with unchanged inputs and intact outputs skip regeneration
+type svgManifest struct {
+	InputHash  string   `json:"inputHash"`  // Cluster file, referenced SVG files, config files and options
+	OutputHash string   `json:"outputHash"` // Contents of Files
+	Files      []string `json:"files"`      // Generated files, relative to the output directory
+}
+
+// svgOutputFiles lists the files a run with these options writes to the output directory
+func (o svgOptions) svgOutputFiles() []string {
+	var files []string
+	if o.hasFormat("json") {
+		files = append(files, o.svgJSONFile())
+	}
+	if o.hasFormat("ndjson") {
+		files = append(files, "svg_icons.ndjson")
+	}
+	if o.hasFormat("algolia") {
+		files = append(files, svgAlgoliaFile)
+	}
+	if o.hasFormat("csv") {
+		files = append(files, svgCSVFile)
+	}
+	if o.hasFormat("opensearch") {
+		files = append(files, svgOpenSearchFile)
+	}
+	if o.hasFormat("meilisearch") {
+		files = append(files, svgMeiliFile, svgMeiliSettingsFile)
+	}
+	if o.hasFormat("sqlite") {
+		files = append(files, svgSQLiteFile)
+	}
+	for _, lang := range o.Langs {
+		files = append(files, o.svgLangJSONFile(lang))
+	}
+	if o.IDStyle == svgicons.IDStyleHash {
+		files = append(files, svgHashIDsFile)
+	}
+	if o.ReportDiffJSON {
+		files = append(files, svgChangesFile)
+	}
+	if o.ComparePython != "" {
+		files = append(files, svgParityFile)
+	}
+	if o.ReportSimilarNames {
+		files = append(files, svgSimilarNamesFile)
+	}
+	if o.ReportVisualDupes {
+		files = append(files, svgVisualDupesFile)
+	}
+	if o.ReportTokens {
+		files = append(files, svgTokenFrequencyFile)
+	}
+	if o.EmitSchema {
+		files = append(files, svgSchemaFile)
+	}
+	if o.EmitOffsets {
+		files = append(files, svgOffsetsFile)
+	}
+	if o.Sitemap {
+		files = append(files, svgSitemapFile)
+	}
+	return append(files, svgIndexFile, svgAutocompleteFile, svgFacetsFile, svgicons.StatsFile)
+}
+
+// hashSVGInputs hashes everything the SVG icons output depends on: the cluster file, every
+// SVG file it references, the optional config files and the options changing the output
+func hashSVGInputs(opts svgOptions) (string, error) {
+	clusterPaths, err := opts.ResolveClusterPaths()
+	if err != nil {
+		return "", err
+	}
+	cluster, _, err := opts.LoadClusters(clusterPaths)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "version %d\n", svgicons.DataVersion)
+	fmt.Fprintf(h, "options %q %t %d %t %s %d %t %t %d\n", opts.Formats, opts.Gzip, opts.GzipLevel, opts.Dedupe, opts.StemmerName, opts.ngramSize(), opts.Strict, opts.Related, opts.RelatedCount)
+	fmt.Fprintf(h, "inline %t %d limit %d folder %q\n", opts.InlineSVG, opts.InlineSVGMaxBytes, opts.Limit, opts.Folder)
+	fmt.Fprintf(h, "sitemap %t %q complexity %d optimize %t schema %t\n", opts.Sitemap, opts.SitemapBaseURL, opts.ComplexityThreshold, opts.Optimize, opts.EmitSchema)
+	fmt.Fprintf(h, "allow %q opensearch %q phonetic %t\n", opts.Allow, opts.OpenSearchIndex, opts.Phonetic)
+	fmt.Fprintf(h, "variants %t %q\n", opts.GroupVariants, opts.VariantSuffixes)
+	fmt.Fprintf(h, "langs %q id style %s sort %s base path %s\n", opts.Langs, opts.IDStyle, opts.Sort, opts.ResolveBasePath())
+	fmt.Fprintf(h, "description length %d full %t diff %t %t\n", opts.MaxDescriptionLen, opts.KeepFullDescription, opts.ReportDiff, opts.ReportDiffJSON)
+	fmt.Fprintf(h, "rasterize %t %d %s\n", opts.Rasterize, opts.RasterSize, opts.RasterFormat)
+	fmt.Fprintf(h, "similar %t %d visual dupes %t\n", opts.ReportSimilarNames, opts.SimilarNamesDistance, opts.ReportVisualDupes)
+	fmt.Fprintf(h, "lock verify %t update %t\n", opts.Verify, opts.UpdateLock)
+	fmt.Fprintf(h, "report tokens %t\n", opts.ReportTokens)
+	fmt.Fprintf(h, "source %q compact %t\n", opts.Source, opts.Compact)
+	fmt.Fprintf(h, "modified from %s offsets %t\n", opts.ModifiedFrom, opts.EmitOffsets)
+	fmt.Fprintf(h, "hidden %s compare python %q max output bytes %d\n", opts.Hidden, opts.ComparePython, opts.MaxOutputBytes)
+	for _, clusterPath := range clusterPaths {
+		fileHash, err := hashFileIfExists(clusterPath)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "cluster %s\n", fileHash)
+	}
+
+	// SVG files are named by their place in IconsDir, which stays the same for the temporary
+	// copies of remote sources
+	type svgFile struct{ name, file string }
+	var svgFiles []svgFile
+	for _, clusterEntry := range cluster.Clusters {
+		for _, fileName := range clusterEntry.FileNames {
+			svgFiles = append(svgFiles, svgFile{name: svgicons.IconsDirPath(clusterEntry.SourceFolder, fileName.FileName), file: svgicons.FilePath(clusterEntry.SourceFolder, fileName.FileName)})
+		}
+	}
+	sort.Slice(svgFiles, func(i, j int) bool { return svgFiles[i].name < svgFiles[j].name })
+
+	configFiles := []string{svgicons.NameCasingFile, svgicons.PopularityFile, svgicons.IgnoreFile, jargon_stemmer.SynonymsFile, jargon_stemmer.StopWordsFile, fieldBoostsFile}
+	for _, lang := range opts.Langs {
+		configFiles = append(configFiles, svgTranslationsFile(lang))
+	}
+	if opts.Verify || opts.UpdateLock {
+		configFiles = append(configFiles, svgicons.LockFile)
+	}
+	if opts.ComparePython != "" {
+		configFiles = append(configFiles, opts.ComparePython)
+	}
+	for _, file := range configFiles {
+		fileHash, err := hashFileIfExists(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %s\n", file, fileHash)
+	}
+	for _, svg := range svgFiles {
+		fileHash, err := hashFileIfExists(svg.file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %s\n", svg.name, fileHash)
+		// With mtime dates, touching a file changes its modifiedAt without changing its content
+		if opts.ModifiedFrom == svgicons.ModifiedFromMtime {
+			if info, err := os.Stat(svg.file); err == nil {
+				fmt.Fprintf(h, "%s mtime %d\n", svg.name, info.ModTime().UnixNano())
+			}
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashOutputFiles hashes the generated files in order
+func hashOutputFiles(files []string) (string, error) {
+	h := sha256.New()
+	for _, file := range files {
+		content, err := ioutil.ReadFile(filepath.Join(outputDir, file))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %s\n", file, hashContent(content))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFileIfExists returns the content hash of a file, or "missing" if it does not exist
+func hashFileIfExists(filePath string) (string, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return "missing", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	return hashContent(content), nil
+}
+
+// hashContent returns the hex encoded SHA-256 of the content
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadSVGManifest reads the manifest of the last run, nil if there is none
+func loadSVGManifest() (*svgManifest, error) {
+	manifestPath := filepath.Join(outputDir, svgManifestFile)
+	content, err := ioutil.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", manifestPath, err)
+	}
+
+	var manifest svgManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestPath, err)
+	}
+	return &manifest, nil
+}
+
+// upToDate reports whether the last run had the same inputs and its outputs are unchanged
+func (m *svgManifest) upToDate(inputHash string) bool {
+	if m == nil || m.InputHash != inputHash {
+		return false
+	}
+	outputHash, err := hashOutputFiles(m.Files)
+	return err == nil && outputHash == m.OutputHash
+}
+
+// saveSVGManifest records the input hash and the hash of the files just written
+func saveSVGManifest(inputHash string, files []string) error {
+	outputHash, err := hashOutputFiles(files)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(svgManifest{InputHash: inputHash, OutputHash: outputHash, Files: files}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(outputDir, svgManifestFile), data)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// svgOffsetsFile maps every icon ID to where its record is in svg_icons.json, from --emit-offsets
+const svgOffsetsFile = "svg_icons.offsets.json"
+
+// svgIconOffsets is the content of svg_icons.offsets.json
+type svgIconOffsets struct {
+	File    string                   `json:"file"`    // Name of the JSON file the offsets are in
+	Size    int                      `json:"size"`    // Size of that file in bytes, to tell when the offsets are stale
+	Offsets map[string]svgIconOffset `json:"offsets"` // By icon ID
+}
+
+// svgIconOffset is the byte range of one icon record, from its opening to its closing brace
+type svgIconOffset struct {
+	Offset int `json:"offset"`
+	Length int `json:"length"`
+}
+
+// buildSVGIconOffsets finds the byte range of every record of a JSON array of icons as
+// written, after the stem step rewrote it, so a client can fetch a single icon with an HTTP
+// range request of bytes offset to offset+length-1 and parse it on its own
+func buildSVGIconOffsets(filename string) (svgIconOffsets, error) {
+	filePath := filepath.Join(outputDir, filename)
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return svgIconOffsets{}, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	offsets := svgIconOffsets{File: filename, Size: len(content), Offsets: make(map[string]svgIconOffset)}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return svgIconOffsets{}, fmt.Errorf("%s is not a JSON array", filePath)
+	}
+	for decoder.More() {
+		// The decoder stops after the previous record, before the comma and whitespace
+		start := int(decoder.InputOffset())
+		for start < len(content) && bytes.IndexByte([]byte(" \t\r\n,"), content[start]) >= 0 {
+			start++
+		}
+		var record struct {
+			ID string `json:"id"`
+		}
+		if err := decoder.Decode(&record); err != nil {
+			return svgIconOffsets{}, fmt.Errorf("failed to parse %s at byte %d: %w", filePath, start, err)
+		}
+		if _, ok := offsets.Offsets[record.ID]; ok {
+			return svgIconOffsets{}, fmt.Errorf("icon ID %q is in %s more than once", record.ID, filePath)
+		}
+		offsets.Offsets[record.ID] = svgIconOffset{Offset: start, Length: int(decoder.InputOffset()) - start}
+	}
+	return offsets, nil
+}
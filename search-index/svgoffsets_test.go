@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildSVGIconOffsetsSeek(t *testing.T) {
+	icons := []SVGIconData{
+		{ID: "svg-icons-feather-home", Name: "Home", Description: "A house, with {braces} and \"quotes\""},
+		{ID: "svg-icons-feather-bell", Name: "Bell", Tags: []string{"bell", "ring"}},
+		{ID: "svg-icons-feather-cog", Name: "Cog", Variants: map[string]string{"filled": "svg-icons-feather-cog-filled"}},
+	}
+
+	for _, compact := range []bool{false, true} {
+		useOutputDir(t, compact)
+		if err := saveToJSON("svg_icons.json", icons); err != nil {
+			t.Fatal(err)
+		}
+		// Offsets are taken once the stem step rewrote the file
+		if err := stemJSONFile(filepath.Join(outputDir, "svg_icons.json")); err != nil {
+			t.Fatal(err)
+		}
+
+		offsets, err := buildSVGIconOffsets("svg_icons.json")
+		if err != nil {
+			t.Fatalf("compact %v: buildSVGIconOffsets: %v", compact, err)
+		}
+		file, err := os.Open(filepath.Join(outputDir, "svg_icons.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		if info, _ := file.Stat(); offsets.File != "svg_icons.json" || offsets.Size != int(info.Size()) || len(offsets.Offsets) != len(icons) {
+			t.Errorf("compact %v: offsets of %s with size %d and %d records, want svg_icons.json, %d, %d", compact, offsets.File, offsets.Size, len(offsets.Offsets), info.Size(), len(icons))
+		}
+
+		// Read each record alone, like an HTTP range request would
+		for _, icon := range icons {
+			offset, ok := offsets.Offsets[icon.ID]
+			if !ok {
+				t.Fatalf("compact %v: no offset for %s", compact, icon.ID)
+			}
+			record := make([]byte, offset.Length)
+			if _, err := file.Seek(int64(offset.Offset), io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := io.ReadFull(file, record); err != nil {
+				t.Fatal(err)
+			}
+			var got struct {
+				SVGIconData
+				AltName string `json:"altName"`
+			}
+			if err := json.Unmarshal(record, &got); err != nil {
+				t.Errorf("compact %v: record of %s at %d is not valid JSON: %v\n%s", compact, icon.ID, offset.Offset, err, record)
+				continue
+			}
+			if got.ID != icon.ID || got.Name != icon.Name || got.AltName == "" {
+				t.Errorf("compact %v: record at %d is %s %q with altName %q, want the stemmed %s", compact, offset.Offset, got.ID, got.Name, got.AltName, icon.ID)
+			}
+		}
+	}
+}
+
+func TestBuildSVGIconOffsetsErrors(t *testing.T) {
+	useOutputDir(t, true)
+	if err := saveToJSON("svg_icons.json", map[string]string{"id": "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buildSVGIconOffsets("svg_icons.json"); err == nil {
+		t.Error("buildSVGIconOffsets of an object succeeded, want a JSON array error")
+	}
+	if err := saveToJSON("svg_icons.json", []SVGIconData{{ID: "a"}, {ID: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buildSVGIconOffsets("svg_icons.json"); err == nil {
+		t.Error("buildSVGIconOffsets with a repeated ID succeeded, want an error")
+	}
+}
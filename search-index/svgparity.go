@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// svgParityFile is the comparison with the output of the Python generator, written by --compare-python
+const svgParityFile = "python_parity.json"
+
+// svgParityFields are the fields the Go and Python generators must agree on, by JSON name
+var svgParityFields = []string{"id", "name", "path"}
+
+// pythonSVGIcon holds the compared fields of a record of the Python generator's svg_icons.json.
+// Its other fields, stemmed ones included, are ignored whatever their type.
+type pythonSVGIcon struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Image string `json:"image"`
+}
+
+// svgParityMismatch is a field of an icon on which the generators disagree
+type svgParityMismatch struct {
+	Image  string `json:"image"`
+	Field  string `json:"field"`
+	Python string `json:"python"`
+	Go     string `json:"go"`
+}
+
+// svgParity compares the icons of both generators, paired by image since that is the SVG file
+// both were generated from
+type svgParity struct {
+	Pass        bool                `json:"pass"`
+	PythonFile  string              `json:"pythonFile"`
+	PythonIcons int                 `json:"pythonIcons"`
+	GoIcons     int                 `json:"goIcons"`
+	Matched     int                 `json:"matched"`    // Icons paired by image
+	OnlyPython  []string            `json:"onlyPython"` // Images only the Python generator indexed
+	OnlyGo      []string            `json:"onlyGo"`     // Images only the Go generator indexed
+	Mismatches  []svgParityMismatch `json:"mismatches"` // By image, then field
+	FieldCounts map[string]int      `json:"fieldCounts"`
+}
+
+// loadPythonSVGIcons reads the svg_icons.json written by the Python generator, gzip compressed
+// when its name ends in .gz
+func loadPythonSVGIcons(filePath string) ([]pythonSVGIcon, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var r io.Reader = bytes.NewReader(content)
+	if strings.HasSuffix(filePath, ".gz") {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var icons []pythonSVGIcon
+	if err := json.NewDecoder(r).Decode(&icons); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return icons, nil
+}
+
+// compareSVGIconsWithPython pairs the icons of both generators by image, in order for an image
+// listed more than once, and compares svgParityFields of each pair. The run passes when every
+// image is indexed by both and every compared field is the same.
+func compareSVGIconsWithPython(icons []SVGIconData, python []pythonSVGIcon, pythonFile string) *svgParity {
+	parity := &svgParity{
+		PythonFile:  pythonFile,
+		PythonIcons: len(python),
+		GoIcons:     len(icons),
+		OnlyPython:  []string{},
+		OnlyGo:      []string{},
+		Mismatches:  []svgParityMismatch{},
+		FieldCounts: map[string]int{},
+	}
+
+	byImage := make(map[string][]pythonSVGIcon, len(python))
+	for _, icon := range python {
+		byImage[icon.Image] = append(byImage[icon.Image], icon)
+	}
+
+	for _, icon := range icons {
+		pending := byImage[icon.Image]
+		if len(pending) == 0 {
+			parity.OnlyGo = append(parity.OnlyGo, icon.Image)
+			continue
+		}
+		old := pending[0]
+		byImage[icon.Image] = pending[1:]
+		parity.Matched++
+
+		for _, field := range [][3]string{{"id", old.ID, icon.ID}, {"name", old.Name, icon.Name}, {"path", old.Path, icon.Path}} {
+			if field[1] != field[2] {
+				parity.Mismatches = append(parity.Mismatches, svgParityMismatch{Image: icon.Image, Field: field[0], Python: field[1], Go: field[2]})
+				parity.FieldCounts[field[0]]++
+			}
+		}
+	}
+	for image, pending := range byImage {
+		for range pending {
+			parity.OnlyPython = append(parity.OnlyPython, image)
+		}
+	}
+
+	sort.Strings(parity.OnlyPython)
+	sort.Strings(parity.OnlyGo)
+	sort.SliceStable(parity.Mismatches, func(i, j int) bool { return parity.Mismatches[i].Image < parity.Mismatches[j].Image })
+	parity.Pass = len(parity.OnlyPython) == 0 && len(parity.OnlyGo) == 0 && len(parity.Mismatches) == 0
+	return parity
+}
+
+// printSVGParity prints whether the generators agree, the mismatches per field and the first
+// images of each kind of difference
+func printSVGParity(parity *svgParity) {
+	if parity.Pass {
+		slog.Info(fmt.Sprintf("\n✅ Python parity: all %d icons of %s match on %s", parity.Matched, parity.PythonFile, strings.Join(svgParityFields, ", ")), "matched", parity.Matched)
+		return
+	}
+
+	slog.Warn(fmt.Sprintf("\n❌ Python parity failed against %s: %d icons matched, %d only in Python, %d only in Go, %d field mismatches", parity.PythonFile, parity.Matched, len(parity.OnlyPython), len(parity.OnlyGo), len(parity.Mismatches)), "matched", parity.Matched, "onlyPython", len(parity.OnlyPython), "onlyGo", len(parity.OnlyGo), "mismatches", len(parity.Mismatches))
+	for _, field := range svgParityFields {
+		if count := parity.FieldCounts[field]; count > 0 {
+			slog.Warn(fmt.Sprintf("   • %s differs on %d icons", field, count), "field", field, "icons", count)
+		}
+	}
+
+	printSVGDiffIDs("Only in Python", parity.OnlyPython)
+	printSVGDiffIDs("Only in Go", parity.OnlyGo)
+	mismatches := make([]string, len(parity.Mismatches))
+	for i, m := range parity.Mismatches {
+		mismatches[i] = fmt.Sprintf("%s %s: %q in Python, %q in Go", m.Image, m.Field, m.Python, m.Go)
+	}
+	printSVGDiffIDs("Mismatches", mismatches)
+}
+
+// reportSVGParity compares the icons with the Python output named by --compare-python,
+// printing the summary and writing python_parity.json unless it is a dry run. The caller fails
+// the run once the rest of the output is written when the generators disagree.
+func reportSVGParity(icons []SVGIconData, opts svgOptions) (*svgParity, error) {
+	python, err := loadPythonSVGIcons(opts.ComparePython)
+	if err != nil {
+		return nil, err
+	}
+	parity := compareSVGIconsWithPython(icons, python, opts.ComparePython)
+	printSVGParity(parity)
+
+	if !opts.DryRun {
+		if err := saveToJSON(svgParityFile, parity); err != nil {
+			return nil, fmt.Errorf("failed to save %s: %w", svgParityFile, err)
+		}
+		slog.Info(fmt.Sprintf("💾 Python parity report saved to %s", filepath.Join(outputDir, svgParityFile)))
+	}
+	return parity, nil
+}
+
+// checkSVGParity fails a --compare-python run on which the generators disagree
+func checkSVGParity(parity *svgParity) error {
+	if parity == nil || parity.Pass {
+		return nil
+	}
+	return fmt.Errorf("Python parity failed: %d only in Python, %d only in Go, %d field mismatches", len(parity.OnlyPython), len(parity.OnlyGo), len(parity.Mismatches))
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/antzucaro/matchr"
+)
+
+// phoneticCodes returns the Double Metaphone codes of a word, the primary code first and the
+// alternate one when it differs. Words without a sound code, such as numbers, give none.
+func phoneticCodes(word string) []string {
+	primary, alternate := matchr.DoubleMetaphone(word)
+	var codes []string
+	if primary != "" {
+		codes = append(codes, primary)
+	}
+	if alternate != "" && alternate != primary {
+		codes = append(codes, alternate)
+	}
+	return codes
+}
+
+// buildPhoneticIndex maps the Double Metaphone codes of the words of each icon's Name and Tags
+// to the icon IDs, so clients can fall back to matching how a misspelled query sounds
+// ("calender" and "calendar" are both KLNT). The words are not stemmed: clients encode the
+// words users type. Descriptions are left out, names are what users misspell and search for.
+func buildPhoneticIndex(icons []SVGIconData) map[string][]string {
+	codes := make(map[string][]string)
+	for _, icon := range icons {
+		seen := make(map[string]bool)
+		for _, word := range nameTokens(icon.Name + " " + strings.Join(icon.Tags, " ")) {
+			for _, code := range phoneticCodes(word) {
+				if seen[code] {
+					continue
+				}
+				seen[code] = true
+				codes[code] = append(codes[code], icon.ID)
+			}
+		}
+	}
+	return codes
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPhoneticCodesMisspellings(t *testing.T) {
+	pairs := [][2]string{
+		{"calendar", "calender"},
+		{"bell", "bel"},
+		{"scissors", "sissors"},
+	}
+	for _, pair := range pairs {
+		if got, want := phoneticCodes(pair[1]), phoneticCodes(pair[0]); len(want) == 0 || !reflect.DeepEqual(got, want) {
+			t.Errorf("phoneticCodes(%q) = %v, want the codes of %q %v", pair[1], got, pair[0], want)
+		}
+	}
+	if got := phoneticCodes("24"); len(got) != 0 {
+		t.Errorf(`phoneticCodes("24") = %v, want none`, got)
+	}
+}
+
+func TestBuildPhoneticIndex(t *testing.T) {
+	icons := []SVGIconData{
+		{ID: "svg-icons-a-calendar", Name: "Calendar", Tags: []string{"calendar"}},
+		{ID: "svg-icons-a-calendar-2", Name: "Calendar 2"},
+		{ID: "svg-icons-a-home", Name: "Home"},
+	}
+	index := buildPhoneticIndex(icons)
+
+	// A misspelled query finds both calendars, each listed once although its tag repeats the name
+	code := phoneticCodes("calender")[0]
+	if got := index[code]; !reflect.DeepEqual(got, []string{"svg-icons-a-calendar", "svg-icons-a-calendar-2"}) {
+		t.Errorf("index[%s] = %v, want both calendar icons", code, got)
+	}
+	code = phoneticCodes("home")[0]
+	if got := index[code]; !reflect.DeepEqual(got, []string{"svg-icons-a-home"}) {
+		t.Errorf("index[%s] = %v, want only the home icon", code, got)
+	}
+}
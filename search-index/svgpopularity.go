@@ -0,0 +1,24 @@
+package main
+
+import "math"
+
+// popularityWeight scales how much popularity raises the index weight of an icon, so usage
+// breaks ties and nudges the ranking without overriding how well an icon matches the query
+const popularityWeight = 0.1
+
+// popularityBoost is the factor applied to the index weights of an icon: 1 for unused icons,
+// growing with the logarithm of the score so a few very popular icons do not dominate
+func popularityBoost(score int) float64 {
+	if score <= 0 {
+		return 1
+	}
+	return 1 + popularityWeight*math.Log10(1+float64(score))
+}
+
+// rankPopular suggests the most popular icons first, in name order for equal popularity
+func rankPopular(a, b *SVGIconData) bool {
+	if a.Popularity != b.Popularity {
+		return a.Popularity > b.Popularity
+	}
+	return rankAlphabetical(a, b)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	svgProgressEvery    = 1000                   // Icons between progress log lines
+	svgProgressInterval = 2 * time.Second        // Longest quiet time between progress log lines
+	svgProgressRedraw   = 250 * time.Millisecond // Redraw interval of the terminal progress bar
+	svgProgressBarWidth = 30
+)
+
+// svgProgress reports how far SVG icon processing got, with an ETA. In a terminal with the
+// text log format it redraws a single bar line; otherwise, e.g. in CI or with --log-format
+// json, it logs a line every svgProgressEvery icons or svgProgressInterval. Small runs
+// finishing before the first report print nothing.
+type svgProgress struct {
+	w        io.Writer // Where the bar is drawn, nil to log lines instead
+	start    time.Time
+	last     time.Time // Time of the last report
+	lastDone int       // Icons processed at the last report
+	reported bool
+}
+
+// newSVGProgress returns the progress reporter for the given output options
+func newSVGProgress(opts svgOptions) *svgProgress {
+	p := &svgProgress{start: time.Now()}
+	p.last = p.start
+	if opts.LogFormat == "text" && isTerminal(os.Stdout) && slog.Default().Enabled(context.Background(), slog.LevelInfo) {
+		p.w = os.Stdout
+	}
+	return p
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or a file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// update is the svgicons.ProgressFunc of the run
+func (p *svgProgress) update(done, total int) {
+	now := time.Now()
+	finished := done >= total
+
+	if p.w != nil {
+		// Start drawing once the run is big or slow enough to need it
+		if !p.reported && total < svgProgressEvery && now.Sub(p.start) < svgProgressInterval {
+			return
+		}
+		if !finished && now.Sub(p.last) < svgProgressRedraw && p.reported {
+			return
+		}
+		fmt.Fprintf(p.w, "\r\033[K%s", p.bar(done, total, now))
+		if finished {
+			fmt.Fprintln(p.w)
+		}
+		p.last, p.lastDone, p.reported = now, done, true
+		return
+	}
+
+	due := done-p.lastDone >= svgProgressEvery || now.Sub(p.last) >= svgProgressInterval
+	if !due && !(finished && p.reported) {
+		return
+	}
+	eta := p.eta(done, total, now)
+	slog.Info(fmt.Sprintf("⏳ Processed %d/%d SVG icons (%d%%), ETA %v", done, total, percent(done, total), eta), "done", done, "total", total, "etaSeconds", int(eta.Seconds()))
+	p.last, p.lastDone, p.reported = now, done, true
+}
+
+// bar renders the terminal progress line, e.g. ⏳ [█████░░░░░] 50% 4000/8000 icons, ETA 3s
+func (p *svgProgress) bar(done, total int, now time.Time) string {
+	filled := svgProgressBarWidth * percent(done, total) / 100
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", svgProgressBarWidth-filled)
+	return fmt.Sprintf("⏳ [%s] %3d%% %d/%d icons, ETA %v", bar, percent(done, total), done, total, p.eta(done, total, now))
+}
+
+// eta extrapolates the time left from the average time per icon so far
+func (p *svgProgress) eta(done, total int, now time.Time) time.Duration {
+	if done == 0 || done >= total {
+		return 0
+	}
+	perIcon := now.Sub(p.start) / time.Duration(done)
+	return (perIcon * time.Duration(total-done)).Round(time.Second)
+}
+
+func percent(done, total int) int {
+	if total == 0 {
+		return 100
+	}
+	return 100 * done / total
+}
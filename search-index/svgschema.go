@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// svgSchemaFile is the JSON Schema of svg_icons.json, written by --emit-schema
+const svgSchemaFile = "svg_icons.schema.json"
+
+// jsonSchema is the subset of JSON Schema draft 2020-12 used to describe the output
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"` // false or a *jsonSchema
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+// svgIconsSchema describes svg_icons.json: an array of SVGIconData objects, with the alt*
+// fields added by the stem step. It is built by reflecting over SVGIconData, so a field
+// added to the struct is in the schema without touching this file. Fields without omitempty
+// are required, and other fields are rejected so consumers notice a renamed one.
+func svgIconsSchema(stemFields []string) (*jsonSchema, error) {
+	icon, err := structSchema(reflect.TypeOf(SVGIconData{}))
+	if err != nil {
+		return nil, err
+	}
+	icon.Title = "SVGIconData"
+	icon.Description = "An SVG icon of the FreeDevTools catalog"
+
+	// The stem step stores the processed text of each stemmed field next to it
+	for _, field := range stemFields {
+		icon.Properties["alt"+field] = &jsonSchema{Type: "string", Description: "Stemmed " + strings.ToLower(field) + " for search, from the stem step"}
+	}
+	icon.Properties["altSynonyms"] = &jsonSchema{Type: "string", Description: "Synonyms of the stemmed tokens, from synonyms.json"}
+
+	return &jsonSchema{
+		Schema:      "https://json-schema.org/draft/2020-12/schema",
+		ID:          svgSchemaFile,
+		Title:       "SVG icons",
+		Description: "The SVG icons written to svg_icons.json by the search index generator",
+		Type:        "array",
+		Items:       &jsonSchema{Ref: "#/$defs/SVGIconData"},
+		Defs:        map[string]*jsonSchema{"SVGIconData": icon},
+	}, nil
+}
+
+// structSchema describes a struct type from its fields and their json tags
+func structSchema(t reflect.Type) (*jsonSchema, error) {
+	closed := false
+	schema := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema), AdditionalProperties: &closed}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		property, err := typeSchema(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		schema.Properties[name] = property
+		if !strings.Contains(","+options+",", ",omitempty,") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema, nil
+}
+
+// typeSchema describes the JSON encoding of a Go type
+func typeSchema(t reflect.Type) (*jsonSchema, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return &jsonSchema{Type: "string"}, nil
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "array", Items: items}, nil
+	case reflect.Struct:
+		return structSchema(t)
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			break
+		}
+		values, err := typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "object", AdditionalProperties: values}, nil
+	}
+	return nil, fmt.Errorf("no JSON Schema for Go type %s", t)
+}
+
+// saveSVGIconsSchema writes svg_icons.schema.json to the output directory
+func saveSVGIconsSchema(opts svgOptions) error {
+	schema, err := svgIconsSchema(opts.stemOptions().Fields)
+	if err != nil {
+		return fmt.Errorf("failed to build %s: %w", svgSchemaFile, err)
+	}
+	return saveToJSON(svgSchemaFile, schema)
+}
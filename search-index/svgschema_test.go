@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// fillValue sets every field of v to a non-zero value, so each one is in its JSON encoding
+func fillValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillValue(v.Index(0))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		value := reflect.New(v.Type().Elem()).Elem()
+		fillValue(value)
+		v.SetMapIndex(reflect.ValueOf("x"), value)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillValue(v.Field(i))
+			}
+		}
+	}
+}
+
+// validateSchema checks a decoded JSON value against the subset of JSON Schema svgschema.go
+// writes, resolving $ref in defs
+func validateSchema(schema *jsonSchema, defs map[string]*jsonSchema, value interface{}, at string) error {
+	if schema.Ref != "" {
+		def, ok := defs[schema.Ref[len("#/$defs/"):]]
+		if !ok {
+			return fmt.Errorf("%s: unresolved $ref %s", at, schema.Ref)
+		}
+		return validateSchema(def, defs, value, at)
+	}
+
+	switch schema.Type {
+	case "string", "boolean", "number":
+		kinds := map[string]reflect.Kind{"string": reflect.String, "boolean": reflect.Bool, "number": reflect.Float64}
+		if reflect.ValueOf(value).Kind() != kinds[schema.Type] {
+			return fmt.Errorf("%s: %v is not a %s", at, value, schema.Type)
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s: %v is not an integer", at, value)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v is not an array", at, value)
+		}
+		for i, item := range items {
+			if err := validateSchema(schema.Items, defs, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v is not an object", at, value)
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s: required property %s is missing", at, name)
+			}
+		}
+		for name, property := range object {
+			propertySchema, ok := schema.Properties[name]
+			if !ok {
+				additional, isSchema := schema.AdditionalProperties.(*jsonSchema)
+				if !isSchema {
+					return fmt.Errorf("%s: property %s is not in the schema", at, name)
+				}
+				propertySchema = additional
+			}
+			if err := validateSchema(propertySchema, defs, property, at+"."+name); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%s: unknown schema type %q", at, schema.Type)
+	}
+	return nil
+}
+
+// decodeJSON round-trips data through its JSON encoding
+func decodeJSON(t *testing.T, data interface{}) interface{} {
+	t.Helper()
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestSVGIconsSchemaMatchesStruct(t *testing.T) {
+	schema, err := svgIconsSchema(svgOptions{}.stemOptions().Fields)
+	if err != nil {
+		t.Fatalf("svgIconsSchema: %v", err)
+	}
+
+	// Every field of the struct is described, with the type it is encoded as
+	var full SVGIconData
+	fillValue(reflect.ValueOf(&full).Elem())
+	if err := validateSchema(schema, schema.Defs, decodeJSON(t, []SVGIconData{full}), "icons"); err != nil {
+		t.Errorf("fully populated icon: %v", err)
+	}
+
+	// Exactly the fields a zero icon still writes are required
+	zero := decodeJSON(t, SVGIconData{}).(map[string]interface{})
+	var written []string
+	for name := range zero {
+		written = append(written, name)
+	}
+	sort.Strings(written)
+	required := append([]string(nil), schema.Defs["SVGIconData"].Required...)
+	sort.Strings(required)
+	if !reflect.DeepEqual(written, required) {
+		t.Errorf("required = %v, want the fields without omitempty %v", required, written)
+	}
+}
+
+func TestSVGIconsSchemaStemFields(t *testing.T) {
+	schema, err := svgIconsSchema(svgOptions{}.stemOptions().Fields)
+	if err != nil {
+		t.Fatalf("svgIconsSchema: %v", err)
+	}
+	stemmed := decodeJSON(t, []SVGIconData{{ID: "svg-icons-a-home", Name: "Home"}}).([]interface{})
+	stemmed[0].(map[string]interface{})["altName"] = "home"
+	stemmed[0].(map[string]interface{})["altSynonyms"] = "house"
+	if err := validateSchema(schema, schema.Defs, stemmed, "icons"); err != nil {
+		t.Errorf("stemmed icon: %v", err)
+	}
+
+	stemmed[0].(map[string]interface{})["imagePath"] = "/svg_icons/a/home.svg"
+	if err := validateSchema(schema, schema.Defs, stemmed, "icons"); err == nil {
+		t.Error("an icon with an unknown property is valid, want it rejected")
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	serveDefaultLimit    = 20              // Results returned when the request has no limit
+	serveMaxLimit        = 200             // Most results returned whatever the limit asked for
+	serveShutdownTimeout = 5 * time.Second // Time in-flight requests get to finish on Ctrl-C
+)
+
+// svgSearchServer answers search and autocomplete queries from the generated SVG icons files
+type svgSearchServer struct {
+	icons        map[string]SVGIconData // By ID
+	index        *SearchIndex
+	autocomplete map[string][]string
+	opts         svgOptions
+}
+
+// svgSearchResult is an icon matching every token of a search, with its TF-IDF score
+type svgSearchResult struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Path        string  `json:"path"`
+	Image       string  `json:"image"`
+	Score       float64 `json:"score"`
+}
+
+// svgSearchResponse is the body of /search
+type svgSearchResponse struct {
+	Query   string            `json:"query"`
+	Tokens  []string          `json:"tokens"` // Stemmed tokens every result contains
+	Total   int               `json:"total"`  // Matching icons, before limit
+	Results []svgSearchResult `json:"results"`
+}
+
+// svgSuggestion is an icon suggested by /autocomplete
+type svgSuggestion struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// svgAutocompleteResponse is the body of /autocomplete
+type svgAutocompleteResponse struct {
+	Query       string          `json:"query"`
+	Prefix      string          `json:"prefix"` // Lowercased last word of the query, looked up in the autocomplete data
+	Suggestions []svgSuggestion `json:"suggestions"`
+}
+
+// loadSVGSearchServer reads the icons, search index and autocomplete data from the output
+// directory. The index and autocomplete data are built from the icons when not there.
+func loadSVGSearchServer(opts svgOptions) (*svgSearchServer, error) {
+	icons, err := loadPreviousSVGIcons(opts)
+	if err != nil {
+		return nil, err
+	}
+	if icons == nil {
+		return nil, fmt.Errorf("%s not found, generate it first with category=svg_icons", filepath.Join(outputDir, opts.svgJSONFile()))
+	}
+
+	server := &svgSearchServer{icons: make(map[string]SVGIconData, len(icons)), opts: opts}
+	for _, icon := range icons {
+		server.icons[icon.ID] = icon
+	}
+
+	if err := loadServeFile(svgIndexFile, &server.index); err != nil {
+		return nil, err
+	}
+	if server.index == nil {
+		slog.Info(fmt.Sprintf("🗂️ No %s, building the search index in memory", svgIndexFile))
+		server.index = buildSVGSearchIndex(icons, opts)
+	}
+	if err := loadServeFile(svgAutocompleteFile, &server.autocomplete); err != nil {
+		return nil, err
+	}
+	if server.autocomplete == nil {
+		server.autocomplete = buildAutocomplete(icons, rankPopular)
+	}
+	return server, nil
+}
+
+// loadServeFile decodes a JSON file of the output directory into v, leaving it untouched
+// when the file does not exist
+func loadServeFile(filename string, v interface{}) error {
+	filePath := filepath.Join(outputDir, filename)
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// search returns the icons containing every stemmed token of the query, highest summed
+// TF-IDF weight first, then by ID
+func (s *svgSearchServer) search(query string, limit int) svgSearchResponse {
+	response := svgSearchResponse{Query: query, Tokens: stemTokens(query, s.opts.stemOptions()), Results: []svgSearchResult{}}
+	if len(response.Tokens) == 0 {
+		response.Tokens = []string{}
+		return response
+	}
+
+	var scores map[string]float64
+	for _, token := range response.Tokens {
+		entry := s.index.Tokens[token]
+		if entry == nil {
+			return response
+		}
+		tokenScores := make(map[string]float64, len(entry.IDs))
+		for i, id := range entry.IDs {
+			if scores == nil || hasScore(scores, id) {
+				tokenScores[id] = scoreOf(scores, id) + weightAt(entry, i)
+			}
+		}
+		scores = tokenScores
+	}
+
+	for id, score := range scores {
+		icon, ok := s.icons[id]
+		if !ok {
+			continue
+		}
+		response.Results = append(response.Results, svgSearchResult{ID: id, Name: icon.Name, Description: icon.Description, Path: icon.Path, Image: icon.Image, Score: roundWeight(score)})
+	}
+	sort.Slice(response.Results, func(i, j int) bool {
+		if response.Results[i].Score != response.Results[j].Score {
+			return response.Results[i].Score > response.Results[j].Score
+		}
+		return response.Results[i].ID < response.Results[j].ID
+	})
+	response.Total = len(response.Results)
+	if len(response.Results) > limit {
+		response.Results = response.Results[:limit]
+	}
+	return response
+}
+
+func hasScore(scores map[string]float64, id string) bool {
+	_, ok := scores[id]
+	return ok
+}
+
+func scoreOf(scores map[string]float64, id string) float64 {
+	if scores == nil {
+		return 0
+	}
+	return scores[id]
+}
+
+// weightAt returns the weight of the i-th icon of an index entry, 0 for an index without weights
+func weightAt(entry *IndexEntry, i int) float64 {
+	if i < len(entry.Weights) {
+		return entry.Weights[i]
+	}
+	return 0
+}
+
+// suggest returns the icons suggested for the last word of the query
+func (s *svgSearchServer) suggest(query string, limit int) svgAutocompleteResponse {
+	response := svgAutocompleteResponse{Query: query, Suggestions: []svgSuggestion{}}
+	words := nameTokens(query)
+	if len(words) == 0 {
+		return response
+	}
+	prefix := []rune(words[len(words)-1])
+	if len(prefix) > autocompleteMaxPrefix {
+		prefix = prefix[:autocompleteMaxPrefix]
+	}
+	response.Prefix = string(prefix)
+
+	for _, id := range s.autocomplete[response.Prefix] {
+		if len(response.Suggestions) >= limit {
+			break
+		}
+		if icon, ok := s.icons[id]; ok {
+			response.Suggestions = append(response.Suggestions, svgSuggestion{ID: id, Name: icon.Name})
+		}
+	}
+	return response
+}
+
+// handler returns the routes of the server: /search?q=...&limit=... and /autocomplete?q=...
+func (s *svgSearchServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		limit, err := parseServeLimit(r)
+		if err != nil {
+			writeServeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeServeJSON(w, http.StatusOK, s.search(r.URL.Query().Get("q"), limit))
+	})
+	mux.HandleFunc("/autocomplete", func(w http.ResponseWriter, r *http.Request) {
+		limit, err := parseServeLimit(r)
+		if err != nil {
+			writeServeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeServeJSON(w, http.StatusOK, s.suggest(r.URL.Query().Get("q"), limit))
+	})
+	return mux
+}
+
+// parseServeLimit reads the limit query parameter, serveDefaultLimit when absent and at most serveMaxLimit
+func parseServeLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return serveDefaultLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("invalid limit %q, expected a positive number", value)
+	}
+	if limit > serveMaxLimit {
+		limit = serveMaxLimit
+	}
+	return limit, nil
+}
+
+func writeServeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(body); err != nil {
+		slog.Warn(fmt.Sprintf("⚠️  Warning: Failed to write response: %v", err))
+	}
+}
+
+// runSVGServe serves search and autocomplete over the generated SVG icons on --port until
+// ctx is cancelled by Ctrl-C or SIGTERM, then lets in-flight requests finish
+func runSVGServe(ctx context.Context, opts svgOptions) error {
+	server, err := loadSVGSearchServer(opts)
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf("localhost:%d", opts.ServePort),
+		Handler:           server.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+	slog.Info(fmt.Sprintf("🌐 Serving %d SVG icons on http://%s, try /search?q=arrow or /autocomplete?q=arr (Ctrl-C to stop)", len(server.icons), httpServer.Addr), "icons", len(server.icons), "addr", httpServer.Addr)
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("🛑 Shutting down, waiting for open requests...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
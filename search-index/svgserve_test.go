@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// useServeIcons writes svg_icons.json to a temporary output directory for the server to load
+func useServeIcons(t *testing.T) {
+	t.Helper()
+	useOutputDir(t, false)
+	icons := []SVGIconData{
+		{ID: "svg-icons-feather-arrow-up", Name: "Arrow Up", Description: "An arrow pointing up", Path: "/freedevtools/svg_icons/feather/arrow-up/", Image: "/svg_icons/feather/arrow-up.svg", Tags: []string{"arrow", "up"}},
+		{ID: "svg-icons-feather-arrow-down", Name: "Arrow Down", Description: "An arrow pointing down", Path: "/freedevtools/svg_icons/feather/arrow-down/", Image: "/svg_icons/feather/arrow-down.svg", Tags: []string{"arrow", "down"}},
+		{ID: "svg-icons-feather-bell", Name: "Bell", Description: "A ringing bell", Path: "/freedevtools/svg_icons/feather/bell/", Image: "/svg_icons/feather/bell.svg", Tags: []string{"bell"}},
+	}
+	if err := saveToJSON("svg_icons.json", icons); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// getServeJSON decodes the JSON response to a GET of url, failing unless it has status want
+func getServeJSON(url string, want int, v interface{}) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != want {
+		return fmt.Errorf("GET %s = %s, want %d", url, resp.Status, want)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func TestSVGSearchServerHandler(t *testing.T) {
+	useServeIcons(t)
+	server, err := loadSVGSearchServer(svgOptions{})
+	if err != nil {
+		t.Fatalf("loadSVGSearchServer: %v", err)
+	}
+	ts := httptest.NewServer(server.handler())
+	defer ts.Close()
+
+	var search svgSearchResponse
+	if err := getServeJSON(ts.URL+"/search?q=arrows+up", http.StatusOK, &search); err != nil {
+		t.Fatal(err)
+	}
+	if search.Total != 1 || search.Results[0].ID != "svg-icons-feather-arrow-up" {
+		t.Errorf("/search?q=arrows up = %+v, want only arrow-up", search)
+	}
+	if err := getServeJSON(ts.URL+"/search?q=arrow&limit=1", http.StatusOK, &search); err != nil {
+		t.Fatal(err)
+	}
+	if search.Total != 2 || len(search.Results) != 1 {
+		t.Errorf("/search?q=arrow&limit=1 = %d of %d results, want 1 of 2", len(search.Results), search.Total)
+	}
+
+	var suggest svgAutocompleteResponse
+	if err := getServeJSON(ts.URL+"/autocomplete?q=be", http.StatusOK, &suggest); err != nil {
+		t.Fatal(err)
+	}
+	if len(suggest.Suggestions) != 1 || suggest.Suggestions[0].Name != "Bell" {
+		t.Errorf("/autocomplete?q=be = %+v, want Bell", suggest)
+	}
+
+	var failure map[string]string
+	if err := getServeJSON(ts.URL+"/search?q=arrow&limit=zero", http.StatusBadRequest, &failure); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunSVGServe(t *testing.T) {
+	useServeIcons(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	served := make(chan error, 1)
+	go func() {
+		served <- runSVGServe(ctx, svgOptions{ServePort: port})
+	}()
+
+	// Wait for the listener, then query from several goroutines at once
+	url := fmt.Sprintf("http://localhost:%d", port)
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		var search svgSearchResponse
+		if getServeJSON(url+"/search?q=bell", http.StatusOK, &search) == nil {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("the server never answered")
+		}
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var search svgSearchResponse
+			if err := getServeJSON(url+"/search?q=arrow", http.StatusOK, &search); err != nil {
+				t.Error(err)
+			} else if search.Total != 2 {
+				t.Errorf("/search?q=arrow found %d icons, want 2", search.Total)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Cancelling shuts the server down cleanly
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("runSVGServe = %v, want nil after cancel", err)
+		}
+	case <-time.After(serveShutdownTimeout + time.Second):
+		t.Fatal("runSVGServe did not return after cancel")
+	}
+}
+
+func TestRunSVGServeWithoutIcons(t *testing.T) {
+	useOutputDir(t, false)
+	if err := runSVGServe(context.Background(), svgOptions{ServePort: 1}); err == nil {
+		t.Error("runSVGServe without svg_icons.json succeeded")
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/antzucaro/matchr"
+)
+
+// svgSimilarNamesFile lists the pairs of icons with near-identical names, from --report-similar-names
+const svgSimilarNamesFile = "svg_icons_similar_names.json"
+
+// svgSimilarName is a pair of distinct icons of the same source folder whose names are
+// likely to be confused
+type svgSimilarName struct {
+	Distance int    `json:"distance"` // Levenshtein distance of the names ignoring case and punctuation, 0 when only those differ
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	OtherID  string `json:"otherId"`
+	Other    string `json:"otherName"`
+}
+
+// similarNameKey lowercases a display name and keeps only its letters and digits, so
+// Arrow Left, Arrow-Left and arrowleft get the same key
+func similarNameKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// nameDeletions returns the key and every string made by deleting up to distance runes
+// from it. Two keys within that Levenshtein distance always share one of them, so only
+// icons sharing a deletion need to be compared.
+func nameDeletions(key string, distance int) []string {
+	seen := map[string]bool{key: true}
+	level := []string{key}
+	for d := 0; d < distance; d++ {
+		var next []string
+		for _, s := range level {
+			runes := []rune(s)
+			for i := range runes {
+				deleted := string(runes[:i]) + string(runes[i+1:])
+				if !seen[deleted] {
+					seen[deleted] = true
+					next = append(next, deleted)
+				}
+			}
+		}
+		level = next
+	}
+
+	deletions := make([]string, 0, len(seen))
+	for s := range seen {
+		deletions = append(deletions, s)
+	}
+	return deletions
+}
+
+// differOnlyInDigits reports whether two keys are the same once digits are dropped,
+// as in a numbered series such as Layer 1 and Layer 2
+func differOnlyInDigits(a, b string) bool {
+	stripDigits := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsDigit(r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	return a != b && stripDigits(a) == stripDigits(b)
+}
+
+// findSimilarSVGNames returns the pairs of icons in the same source folder whose names are
+// equal ignoring case and punctuation, or within maxDistance edits of each other. Numbered
+// series and names too short for the distance to mean much (at most 3 runes per edit) are
+// skipped. Pairs are sorted by distance, then by ID.
+func findSimilarSVGNames(icons []SVGIconData, maxDistance int) []svgSimilarName {
+	keys := make([]string, len(icons))
+	candidates := make(map[string][]int) // Folder and deletion to the icons having it
+	for i, icon := range icons {
+		keys[i] = similarNameKey(icon.Name)
+		if keys[i] == "" {
+			continue
+		}
+		folder := path.Dir(icon.Image)
+		for _, deletion := range nameDeletions(keys[i], maxDistance) {
+			candidate := folder + "\x00" + deletion
+			candidates[candidate] = append(candidates[candidate], i)
+		}
+	}
+
+	seen := make(map[[2]int]bool)
+	pairs := []svgSimilarName{}
+	for _, indexes := range candidates {
+		for x := 0; x < len(indexes); x++ {
+			for y := x + 1; y < len(indexes); y++ {
+				i, j := indexes[x], indexes[y]
+				if seen[[2]int{i, j}] {
+					continue
+				}
+				seen[[2]int{i, j}] = true
+
+				a, b := keys[i], keys[j]
+				distance := 0
+				if a != b {
+					if differOnlyInDigits(a, b) {
+						continue
+					}
+					distance = matchr.Levenshtein(a, b)
+					shortest := len([]rune(a))
+					if n := len([]rune(b)); n < shortest {
+						shortest = n
+					}
+					if distance > maxDistance || shortest <= 3*distance {
+						continue
+					}
+				}
+
+				first, second := icons[i], icons[j]
+				if second.ID < first.ID {
+					first, second = second, first
+				}
+				pairs = append(pairs, svgSimilarName{Distance: distance, ID: first.ID, Name: first.Name, OtherID: second.ID, Other: second.Name})
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Distance != pairs[j].Distance {
+			return pairs[i].Distance < pairs[j].Distance
+		}
+		if pairs[i].ID != pairs[j].ID {
+			return pairs[i].ID < pairs[j].ID
+		}
+		return pairs[i].OtherID < pairs[j].OtherID
+	})
+	return pairs
+}
+
+// reportSimilarSVGNames logs the pairs of icons with near-identical names for
+// --report-similar-names. It is advisory and never fails the run.
+func reportSimilarSVGNames(pairs []svgSimilarName) {
+	if len(pairs) == 0 {
+		slog.Info("\n🔎 No SVG icons with similar names")
+		return
+	}
+	slog.Info(fmt.Sprintf("\n🔎 %d pairs of SVG icons with similar names, consider merging or renaming:", len(pairs)), "pairs", len(pairs))
+	for _, pair := range pairs {
+		slog.Info(fmt.Sprintf("   • %q (%s) and %q (%s), distance %d", pair.Name, pair.ID, pair.Other, pair.OtherID, pair.Distance), "id", pair.ID, "otherId", pair.OtherID, "distance", pair.Distance)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"search-index/svgicons"
+)
+
+const (
+	// svgSitemapFile is the sitemap of the icon pages, or the sitemap index once sharded
+	svgSitemapFile = "sitemap.xml"
+	// svgSitemapMaxURLs is the most URLs a single sitemap may list per the sitemaps protocol
+	svgSitemapMaxURLs = 50000
+	// defaultSitemapBaseURL is the site the icon pages are served from
+	defaultSitemapBaseURL = "https://hexmos.com"
+)
+
+// sitemapURLSet is a sitemap listing one URL per icon page
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// sitemapIndex lists the sitemap shards when there are too many icons for one sitemap
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+const sitemapXMLNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// saveSVGSitemap writes sitemap.xml with the page of every icon, its lastmod being the
+// ModifiedAt of the icon, or the modification time of the SVG file without one. Past svgSitemapMaxURLs icons the pages are split over
+// sitemap-1.xml, sitemap-2.xml, ... and sitemap.xml becomes the index pointing at them.
+// Returns the number of pages listed.
+func saveSVGSitemap(icons []SVGIconData, baseURL string) (int, error) {
+	if err := ensureOutputDir(); err != nil {
+		return 0, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	// Clusters sharing a source folder can yield the same page more than once, list it once
+	urls := make([]sitemapURL, 0, len(icons))
+	seen := make(map[string]bool, len(icons))
+	for _, icon := range icons {
+		entry := sitemapURL{Loc: baseURL + (&url.URL{Path: icon.Path}).EscapedPath()}
+		if seen[entry.Loc] {
+			continue
+		}
+		seen[entry.Loc] = true
+		if icon.ModifiedAt != "" {
+			entry.LastMod = icon.ModifiedAt
+		} else if info, err := os.Stat(svgicons.SourceFile(icon)); err == nil {
+			entry.LastMod = formatSitemapTime(info.ModTime())
+		}
+		urls = append(urls, entry)
+	}
+
+	if len(urls) <= svgSitemapMaxURLs {
+		if err := saveSitemapXML(svgSitemapFile, sitemapURLSet{XMLNS: sitemapXMLNS, URLs: urls}); err != nil {
+			return 0, err
+		}
+		removeStaleSitemapShards(1)
+		return len(urls), nil
+	}
+
+	index := sitemapIndex{XMLNS: sitemapXMLNS}
+	for start := 0; start < len(urls); start += svgSitemapMaxURLs {
+		end := start + svgSitemapMaxURLs
+		if end > len(urls) {
+			end = len(urls)
+		}
+		shard := sitemapShardFile(len(index.Sitemaps) + 1)
+		if err := saveSitemapXML(shard, sitemapURLSet{XMLNS: sitemapXMLNS, URLs: urls[start:end]}); err != nil {
+			return 0, err
+		}
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{Loc: baseURL + "/" + shard, LastMod: latestSitemapTime(urls[start:end])})
+	}
+	if err := saveSitemapXML(svgSitemapFile, index); err != nil {
+		return 0, err
+	}
+	removeStaleSitemapShards(len(index.Sitemaps) + 1)
+	return len(urls), nil
+}
+
+// sitemapShardFile names the n-th sitemap shard, counting from 1
+func sitemapShardFile(n int) string {
+	return fmt.Sprintf("sitemap-%d.xml", n)
+}
+
+// removeStaleSitemapShards deletes the shards from n on left behind by an earlier, larger run
+func removeStaleSitemapShards(n int) {
+	for ; ; n++ {
+		if err := os.Remove(filepath.Join(outputDir, sitemapShardFile(n))); err != nil {
+			return
+		}
+	}
+}
+
+// saveSitemapXML writes v as an XML document to fileName in the output directory
+func saveSitemapXML(fileName string, v interface{}) error {
+	return createFileAtomic(filepath.Join(outputDir, fileName), func(w io.Writer) error {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		encoder := xml.NewEncoder(w)
+		encoder.Indent("", "  ")
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	})
+}
+
+// formatSitemapTime formats a lastmod in the W3C datetime format, in UTC
+func formatSitemapTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// latestSitemapTime returns the most recent lastmod of urls, which sort as strings
+func latestSitemapTime(urls []sitemapURL) string {
+	latest := ""
+	for _, u := range urls {
+		if u.LastMod > latest {
+			latest = u.LastMod
+		}
+	}
+	return latest
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+)
+
+// svgTokenFrequencyFile lists the document frequency of every stemmed token, from --report-tokens
+const svgTokenFrequencyFile = "token_frequency.json"
+
+// tokenReportTop is the number of most common tokens printed by --report-tokens
+const tokenReportTop = 15
+
+// tokenFrequencyReport is the content of token_frequency.json
+type tokenFrequencyReport struct {
+	TotalDocuments int              `json:"totalDocuments"`
+	Tokens         []tokenFrequency `json:"tokens"` // Most common first, then by token
+}
+
+// tokenFrequency is how many icons contain a stemmed token
+type tokenFrequency struct {
+	Token             string  `json:"token"`
+	DocumentFrequency int     `json:"df"`
+	Share             float64 `json:"share"`   // DocumentFrequency over TotalDocuments, 1 for a token in every icon
+	Indexed           bool    `json:"indexed"` // False when stop word removal drops it from every icon
+}
+
+// buildTokenFrequencies counts the icons containing each token of their stemmed Name,
+// Description, Tags and Keywords, stemmed like the search index but keeping stop words, so tokens
+// weighing nothing in search show up next to the stop words that are already dropped
+func buildTokenFrequencies(icons []SVGIconData, index *SearchIndex, opts svgOptions) tokenFrequencyReport {
+	stemOpts := opts.stemOptions()
+	stemOpts.KeepStopWords = true
+
+	counts := make(map[string]int)
+	for _, icon := range icons {
+		seen := make(map[string]bool)
+		for _, token := range stemTokens(icon.Name+" "+icon.Description+" "+strings.Join(icon.Tags, " ")+" "+strings.Join(icon.Keywords, " "), stemOpts) {
+			if !seen[token] {
+				seen[token] = true
+				counts[token]++
+			}
+		}
+	}
+
+	report := tokenFrequencyReport{TotalDocuments: len(icons), Tokens: make([]tokenFrequency, 0, len(counts))}
+	for token, count := range counts {
+		share := 0.0
+		if len(icons) > 0 {
+			share = math.Round(float64(count)/float64(len(icons))*1e4) / 1e4
+		}
+		report.Tokens = append(report.Tokens, tokenFrequency{Token: token, DocumentFrequency: count, Share: share, Indexed: index.Tokens[token] != nil})
+	}
+	sort.Slice(report.Tokens, func(i, j int) bool {
+		if report.Tokens[i].DocumentFrequency != report.Tokens[j].DocumentFrequency {
+			return report.Tokens[i].DocumentFrequency > report.Tokens[j].DocumentFrequency
+		}
+		return report.Tokens[i].Token < report.Tokens[j].Token
+	})
+	return report
+}
+
+// printTokenFrequencies logs the most common tokens for --report-tokens
+func printTokenFrequencies(report tokenFrequencyReport) {
+	slog.Info(fmt.Sprintf("\n🔤 %d stemmed tokens in %d icons, most common:", len(report.Tokens), report.TotalDocuments), "tokens", len(report.Tokens), "documents", report.TotalDocuments)
+	for i, t := range report.Tokens {
+		if i == tokenReportTop {
+			break
+		}
+		note := ""
+		if !t.Indexed {
+			note = " (stop word)"
+		}
+		slog.Info(fmt.Sprintf("   • %s: %d icons (%.1f%%)%s", t.Token, t.DocumentFrequency, 100*t.Share, note), "token", t.Token, "df", t.DocumentFrequency, "indexed", t.Indexed)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// svgTranslationsDir holds one <lang>.json per language, mapping icon IDs or base names
+// such as arrow-up to the translated display name
+const svgTranslationsDir = "translations"
+
+// svgLangPattern matches language codes such as fr, pt-BR or zh_Hant, so a --lang value
+// cannot point outside svgTranslationsDir
+var svgLangPattern = regexp.MustCompile(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]{2,8})*$`)
+
+// svgTranslationsFile returns the translation map of a language
+func svgTranslationsFile(lang string) string {
+	return filepath.Join(svgTranslationsDir, lang+".json")
+}
+
+// svgLangJSONFile returns the name of the localized SVG icons JSON output file of a language
+func (o svgOptions) svgLangJSONFile(lang string) string {
+	return strings.Replace(o.svgJSONFile(), "svg_icons.json", "svg_icons."+lang+".json", 1)
+}
+
+// loadSVGTranslations reads the translation map of a language. Unlike the other optional
+// config files a missing one is an error, since --lang asked for it.
+func loadSVGTranslations(lang string) (map[string]string, error) {
+	filePath := svgTranslationsFile(lang)
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no translations for --lang %s, expected %s", lang, filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var translations map[string]string
+	if err := json.Unmarshal(content, &translations); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return translations, nil
+}
+
+// translateSVGIcons returns a copy of the icons with each name replaced by its translation,
+// looked up by icon ID first and then by base name, keeping the English name otherwise.
+// The IDs of the icons without a translation are returned sorted.
+func translateSVGIcons(icons []SVGIconData, translations map[string]string) ([]SVGIconData, []string) {
+	localized := make([]SVGIconData, len(icons))
+	var missing []string
+	for i, icon := range icons {
+		baseName := strings.TrimSuffix(path.Base(icon.Image), path.Ext(icon.Image))
+		name, ok := translations[icon.ID]
+		if !ok {
+			name, ok = translations[baseName]
+		}
+		if name = strings.TrimSpace(name); ok && name != "" {
+			icon.Name = name
+		} else {
+			missing = append(missing, icon.ID)
+		}
+		localized[i] = icon
+	}
+	sort.Strings(missing)
+	return localized, missing
+}
+
+// saveSVGTranslations writes svg_icons.<lang>.json for every --lang, logging the icons
+// still missing a translation so translators know what is outstanding. All translation
+// maps are read before writing, so a missing one leaves the output untouched.
+func saveSVGTranslations(icons []SVGIconData, opts svgOptions) error {
+	translationsByLang := make(map[string]map[string]string, len(opts.Langs))
+	for _, lang := range opts.Langs {
+		translations, err := loadSVGTranslations(lang)
+		if err != nil {
+			return err
+		}
+		translationsByLang[lang] = translations
+	}
+
+	for _, lang := range opts.Langs {
+		var err error
+		localized, missing := translateSVGIcons(icons, translationsByLang[lang])
+		fileName := opts.svgLangJSONFile(lang)
+		if opts.Gzip {
+			err = saveToJSONGz(fileName, localized, opts.GzipLevel)
+		} else {
+			err = saveToJSON(fileName, localized)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(missing) > 0 {
+			slog.Warn(fmt.Sprintf("⚠️  Warning: %d of %d SVG icons have no %s translation in %s, using the English name (--log-level debug lists them)", len(missing), len(icons), lang, svgTranslationsFile(lang)), "lang", lang, "missing", len(missing))
+			for _, id := range missing {
+				slog.Debug(fmt.Sprintf("   • %s", id), "lang", lang, "id", id)
+			}
+		}
+	}
+	return nil
+}
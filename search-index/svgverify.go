@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// svgIconsVerifier returns the --verify-output check of a written SVG icons JSON file, run on
+// the temporary file before it replaces the previous one. The file must decode as a JSON array
+// of icons holding exactly the IDs of icons, in order. gzipped tells whether it is compressed,
+// as the temporary file names do not end in .gz.
+func svgIconsVerifier(icons []SVGIconData, gzipped bool) func(tmpPath string) error {
+	return func(tmpPath string) error {
+		written, err := readSVGIconsFile(tmpPath, gzipped)
+		if err != nil {
+			return fmt.Errorf("output verification failed, the written file does not parse: %w", err)
+		}
+		if len(written) != len(icons) {
+			return fmt.Errorf("output verification failed, wrote %d icons but read back %d", len(icons), len(written))
+		}
+		for i := range icons {
+			if written[i].ID != icons[i].ID {
+				return fmt.Errorf("output verification failed, icon %d should be %q but read back %q", i, icons[i].ID, written[i].ID)
+			}
+		}
+		return nil
+	}
+}
+
+// readSVGIconsFile decodes a JSON array of icons, rejecting anything after the array such as
+// the remains of an earlier, longer file
+func readSVGIconsFile(filePath string, gzipped bool) ([]SVGIconData, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = bufio.NewReader(file)
+	if gzipped {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	decoder := json.NewDecoder(reader)
+	var icons []SVGIconData
+	if err := decoder.Decode(&icons); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the icons array")
+	}
+	return icons, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSVGIconsVerifierCorruptedWrite(t *testing.T) {
+	icons := []SVGIconData{{ID: "svg-icons-a-home", Name: "Home"}, {ID: "svg-icons-a-bell", Name: "Bell"}}
+	valid := `[{"id":"svg-icons-a-home"},{"id":"svg-icons-a-bell"}]`
+
+	tests := []struct {
+		name    string
+		content string
+		want    string // Part of the error, empty for none
+	}{
+		{"as written", valid, ""},
+		{"truncated", valid[:30], "does not parse"},
+		{"tail of a longer file", valid + `,{"id":"svg-icons-a-cog"}]`, "does not parse"},
+		{"icon lost", `[{"id":"svg-icons-a-home"}]`, "wrote 2 icons but read back 1"},
+		{"icons swapped", `[{"id":"svg-icons-a-bell"},{"id":"svg-icons-a-home"}]`, `icon 0 should be "svg-icons-a-home" but read back "svg-icons-a-bell"`},
+	}
+	for _, gzipped := range []bool{false, true} {
+		for _, tc := range tests {
+			content := []byte(tc.content)
+			if gzipped {
+				var buf bytes.Buffer
+				gz := gzip.NewWriter(&buf)
+				gz.Write(content)
+				gz.Close()
+				content = buf.Bytes()
+			}
+			tmpPath := filepath.Join(t.TempDir(), "svg_icons.json.tmp-1")
+			if err := ioutil.WriteFile(tmpPath, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := svgIconsVerifier(icons, gzipped)(tmpPath)
+			switch {
+			case tc.want == "" && err != nil:
+				t.Errorf("gzipped %v, %s: %v, want no error", gzipped, tc.name, err)
+			case tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)):
+				t.Errorf("gzipped %v, %s: %v, want an error saying %q", gzipped, tc.name, err, tc.want)
+			}
+		}
+	}
+}
+
+func TestSaveToJSONVerifiedKeepsPreviousFile(t *testing.T) {
+	useOutputDir(t, false)
+	icons := []SVGIconData{{ID: "svg-icons-a-home"}, {ID: "svg-icons-a-bell"}}
+	if err := saveToJSONVerified("svg_icons.json", icons, svgIconsVerifier(icons, false)); err != nil {
+		t.Fatalf("saveToJSONVerified: %v", err)
+	}
+	previous := readOutput(t, "svg_icons.json")
+
+	// A write that does not hold what was meant to be written never replaces the last good file
+	if err := saveToJSONVerified("svg_icons.json", icons[:1], svgIconsVerifier(icons, false)); err == nil {
+		t.Fatal("saveToJSONVerified of a corrupted write succeeded")
+	}
+	if got := readOutput(t, "svg_icons.json"); !bytes.Equal(got, previous) {
+		t.Errorf("the failed write replaced svg_icons.json:\n%s", got)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(outputDir, "*.tmp-*")); len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
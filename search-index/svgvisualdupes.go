@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"search-index/svgicons"
+)
+
+// svgVisualDupesFile lists the groups of icons drawing the same paths, from --report-visual-dupes
+const svgVisualDupesFile = "svg_icons_visual_dupes.json"
+
+// pathTokenPattern matches the commands and numbers of path data
+var pathTokenPattern = regexp.MustCompile(`[A-Za-z]|[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+// svgVisualDupe is a group of icons with the same geometry hash
+type svgVisualDupe struct {
+	Geometry string   `json:"geometry"` // Geometry hash shared by the icons
+	IDs      []string `json:"ids"`      // Sorted
+	Names    []string `json:"names"`    // In the order of IDs
+}
+
+// normalizePathData rewrites path data as its commands and numbers separated by single
+// spaces, numbers in their shortest form, so 0.50,1 and .5 1.0 are the same
+func normalizePathData(d string) string {
+	tokens := pathTokenPattern.FindAllString(d, -1)
+	for i, token := range tokens {
+		if n, err := strconv.ParseFloat(token, 64); err == nil {
+			tokens[i] = strconv.FormatFloat(n, 'g', -1, 64)
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+// geometryHash hashes the sorted, normalized d attributes of the <path> elements of an SVG,
+// empty when it has none or is not well-formed. Byte-different files drawing the same paths,
+// e.g. with other comments, colors or attribute order, get the same hash.
+func geometryHash(content []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var paths []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ""
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "path" {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "d" {
+				if d := normalizePathData(attr.Value); d != "" {
+					paths = append(paths, d)
+				}
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return ""
+	}
+
+	sort.Strings(paths)
+	sum := sha256.Sum256([]byte(strings.Join(paths, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// findSVGVisualDupes groups the icons whose SVG files have the same geometry hash, keeping
+// the groups of more than one icon sorted by their first ID. Icons whose file cannot be read
+// or draws no path are left out.
+func findSVGVisualDupes(icons []SVGIconData) []svgVisualDupe {
+	byHash := make(map[string][]SVGIconData)
+	for _, icon := range icons {
+		content, err := ioutil.ReadFile(svgicons.SourceFile(icon))
+		if err != nil {
+			continue
+		}
+		if hash := geometryHash(content); hash != "" {
+			byHash[hash] = append(byHash[hash], icon)
+		}
+	}
+
+	groups := []svgVisualDupe{}
+	for hash, members := range byHash {
+		if len(members) < 2 {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+		group := svgVisualDupe{Geometry: hash}
+		for _, icon := range members {
+			group.IDs = append(group.IDs, icon.ID)
+			group.Names = append(group.Names, icon.Name)
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].IDs[0] < groups[j].IDs[0] })
+	return groups
+}
+
+// reportSVGVisualDupes logs the groups of icons that look the same for --report-visual-dupes.
+// It is advisory, nothing is folded and the run never fails.
+func reportSVGVisualDupes(groups []svgVisualDupe) {
+	if len(groups) == 0 {
+		slog.Info("\n👯 No SVG icons drawing the same paths")
+		return
+	}
+	icons := 0
+	for _, group := range groups {
+		icons += len(group.IDs)
+	}
+	slog.Info(fmt.Sprintf("\n👯 %d groups of SVG icons drawing the same paths (%d icons), check they are meant to be distinct:", len(groups), icons), "groups", len(groups), "icons", icons)
+	for _, group := range groups {
+		slog.Info(fmt.Sprintf("   • %s", strings.Join(group.IDs, ", ")), "ids", group.IDs)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"search-index/svgicons"
+)
+
+// svgWatchDebounce is how long the watcher waits after the last change before regenerating,
+// so saving many files at once triggers a single run
+const svgWatchDebounce = 300 * time.Millisecond
+
+// watchSVGIcons regenerates the SVG icons whenever a cluster file or an SVG source folder
+// changes, until interrupted with Ctrl-C. Each regeneration runs this binary again without
+// --watch, so a broken cluster file fails that run instead of stopping the watcher.
+func watchSVGIcons(ctx context.Context, opts svgOptions) {
+	clusterPaths, err := opts.ResolveClusterPaths()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Fatalf("❌ Failed to start file watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	// Editors often save by replacing the file, so watch the directories rather than the files
+	for _, clusterPath := range clusterPaths {
+		if err := watcher.Add(filepath.Dir(clusterPath)); err != nil {
+			log.Fatalf("❌ Failed to watch %s: %v", filepath.Dir(clusterPath), err)
+		}
+	}
+	addSVGWatchDirs(watcher, opts, clusterPaths)
+
+	runSVGWatchRegeneration(ctx)
+	slog.Info(fmt.Sprintf("👀 Watching %s and %s for changes (Ctrl-C to stop)", strings.Join(clusterPaths, ", "), svgicons.IconsDir))
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("\n👋 Stopped watching")
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if isSVGWatchEvent(event, opts, clusterPaths) {
+				debounce = time.After(svgWatchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn(fmt.Sprintf("⚠️  Warning: File watcher error: %v", err))
+		case <-debounce:
+			debounce = nil
+			// Pick up cluster files matching a --cluster glob and source folders added since the last run
+			if paths, err := opts.ResolveClusterPaths(); err == nil {
+				clusterPaths = paths
+			}
+			addSVGWatchDirs(watcher, opts, clusterPaths)
+			runSVGWatchRegeneration(ctx)
+		}
+	}
+}
+
+// isSVGWatchEvent reports whether a file system event should trigger a regeneration:
+// a change to a cluster file, or a new file matching a --cluster glob, or to any SVG file
+// or folder in a watched directory
+func isSVGWatchEvent(event fsnotify.Event, opts svgOptions, clusterPaths []string) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	name := filepath.Clean(event.Name)
+	if svgicons.ContainsString(clusterPaths, name) {
+		return true
+	}
+	patterns, _ := opts.ClusterPatterns()
+	for _, pattern := range patterns {
+		if absPattern, err := filepath.Abs(pattern); err == nil {
+			if matched, _ := filepath.Match(absPattern, name); matched {
+				return true
+			}
+		}
+	}
+	for _, clusterPath := range clusterPaths {
+		if filepath.Dir(name) == filepath.Dir(clusterPath) {
+			return false
+		}
+	}
+	return strings.EqualFold(filepath.Ext(event.Name), ".svg") || filepath.Ext(event.Name) == ""
+}
+
+// addSVGWatchDirs watches every directory below the SVG source folders of the cluster files.
+// Cluster files that cannot be read are skipped, the next regeneration reports them.
+func addSVGWatchDirs(watcher *fsnotify.Watcher, opts svgOptions, clusterPaths []string) {
+	// Cluster files matching a glob may live in a directory not watched yet
+	for _, clusterPath := range clusterPaths {
+		watcher.Add(filepath.Dir(clusterPath))
+	}
+
+	cluster, _, err := opts.LoadClusters(clusterPaths)
+	if err != nil {
+		return
+	}
+
+	for _, clusterEntry := range cluster.Clusters {
+		root := filepath.Join(svgicons.IconsDir, clusterEntry.SourceFolder)
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			if err := watcher.Add(path); err != nil {
+				slog.Warn(fmt.Sprintf("⚠️  Warning: Failed to watch %s: %v", path, err))
+			}
+			return nil
+		})
+	}
+}
+
+// runSVGWatchRegeneration runs category=svg_icons with the same arguments minus --watch
+// and logs when it finished and how long it took
+func runSVGWatchRegeneration(ctx context.Context) {
+	executable, err := os.Executable()
+	if err != nil {
+		slog.Error(fmt.Sprintf("❌ Failed to find the search-index binary: %v", err))
+		return
+	}
+
+	var args []string
+	for _, arg := range os.Args[1:] {
+		name := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]
+		if strings.HasPrefix(arg, "-") && name == "watch" {
+			continue
+		}
+		args = append(args, arg)
+	}
+
+	start := time.Now()
+	slog.Info(fmt.Sprintf("\n🔄 [%s] Regenerating SVG icons...", start.Format("15:04:05")))
+
+	cmd := exec.CommandContext(ctx, executable, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+
+	elapsed := time.Since(start).Round(time.Millisecond)
+	switch {
+	case ctx.Err() != nil:
+		return
+	case err != nil:
+		slog.Error(fmt.Sprintf("❌ [%s] Regeneration failed after %v: %v", time.Now().Format("15:04:05"), elapsed, err), "category", "svg_icons", "elapsed", elapsed.String())
+	default:
+		slog.Info(fmt.Sprintf("✅ [%s] Regenerated in %v", time.Now().Format("15:04:05"), elapsed), "category", "svg_icons", "elapsed", elapsed.String())
+	}
+}
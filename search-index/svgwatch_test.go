@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"search-index/svgicons"
+)
+
+// svgWatchRecordEnv makes the test binary, when run again by runSVGWatchRegeneration, append
+// its arguments to the file it names and exit instead of running the tests, see TestMain
+const svgWatchRecordEnv = "SEARCH_INDEX_TEST_WATCH_RECORD"
+
+// recordSVGWatchRun appends the arguments of this process to the file named by svgWatchRecordEnv
+func recordSVGWatchRun(recordFile string) {
+	file, err := os.OpenFile(recordFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		os.Exit(1)
+	}
+	file.WriteString(strings.Join(os.Args[1:], " ") + "\n")
+	file.Close()
+}
+
+// svgWatchRuns returns the arguments of each regeneration recorded so far
+func svgWatchRuns(recordFile string) []string {
+	content, _ := ioutil.ReadFile(recordFile)
+	lines := strings.Split(string(content), "\n")
+	return lines[:len(lines)-1]
+}
+
+// waitSVGWatchRuns waits until count regenerations were recorded
+func waitSVGWatchRuns(t *testing.T, recordFile string, count int) []string {
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(20 * time.Millisecond) {
+		if runs := svgWatchRuns(recordFile); len(runs) >= count {
+			return runs
+		}
+	}
+	t.Fatalf("%d regenerations after 10s, want %d", len(svgWatchRuns(recordFile)), count)
+	return nil
+}
+
+// useSVGWatchTree creates a checkout with one cluster file and source folder, moving into
+// its search-index directory until the test ends. Returns the absolute cluster file path.
+func useSVGWatchTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, dir := range []string{"search-index", "frontend/data", "frontend/public/svg_icons/feather"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clusterPath := filepath.Join(root, "frontend", "data", "cluster_svg.json")
+	if err := ioutil.WriteFile(clusterPath, []byte(`{"clusters": {"feather": {"source_folder": "feather", "fileNames": [{"fileName": "home.svg"}]}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "frontend", "public", "svg_icons", "feather", "home.svg"), []byte(`<svg/>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(root, "search-index")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return clusterPath
+}
+
+func TestWatchSVGIcons(t *testing.T) {
+	clusterPath := useSVGWatchTree(t)
+	recordFile := filepath.Join(t.TempDir(), "runs")
+	t.Setenv(svgWatchRecordEnv, recordFile)
+	args := os.Args
+	os.Args = []string{args[0], "category=svg_icons", "--watch", "--workers=2"}
+	defer func() { os.Args = args }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		watchSVGIcons(ctx, svgOptions{Options: svgicons.Options{ClusterPaths: []string{clusterPath}}})
+	}()
+
+	// The first regeneration runs at once, without --watch
+	if runs := waitSVGWatchRuns(t, recordFile, 1); runs[0] != "category=svg_icons --workers=2" {
+		t.Errorf("regeneration arguments = %q, want them without --watch", runs[0])
+	}
+
+	// Saving several files at once regenerates once the changes settle
+	feather := filepath.Join(svgicons.IconsDir, "feather")
+	for _, name := range []string{"home.svg", "bell.svg", "cog.svg"} {
+		if err := ioutil.WriteFile(filepath.Join(feather, name), []byte(`<svg viewBox="0 0 24 24"/>`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitSVGWatchRuns(t, recordFile, 2)
+
+	// A new subfolder is watched after the regeneration it triggers
+	if err := os.Mkdir(filepath.Join(feather, "social"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	waitSVGWatchRuns(t, recordFile, 3)
+	time.Sleep(2 * svgWatchDebounce)
+	if err := ioutil.WriteFile(filepath.Join(feather, "social", "x.svg"), []byte(`<svg/>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitSVGWatchRuns(t, recordFile, 4)
+
+	// Editing the cluster file regenerates too
+	if err := ioutil.WriteFile(clusterPath, []byte(`{"clusters": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitSVGWatchRuns(t, recordFile, 5)
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchSVGIcons did not return after cancel")
+	}
+	if runs := svgWatchRuns(recordFile); len(runs) != 5 {
+		t.Errorf("%d regenerations, want 5: one at start and one per settled change", len(runs))
+	}
+}
+
+func TestIsSVGWatchEvent(t *testing.T) {
+	clusterPath := useSVGWatchTree(t)
+	opts := svgOptions{Options: svgicons.Options{ClusterPaths: []string{clusterPath}}}
+	iconsDir := filepath.Join(svgicons.IconsDir, "feather")
+	tests := []struct {
+		name string
+		op   fsnotify.Op
+		want bool
+	}{
+		{clusterPath, fsnotify.Write, true},
+		{clusterPath, fsnotify.Chmod, false},
+		{filepath.Join(filepath.Dir(clusterPath), "other.json"), fsnotify.Create, false},
+		{filepath.Join(iconsDir, "home.svg"), fsnotify.Write, true},
+		{filepath.Join(iconsDir, "HOME.SVG"), fsnotify.Remove, true},
+		{filepath.Join(iconsDir, "home.png"), fsnotify.Create, false},
+		{filepath.Join(iconsDir, "social"), fsnotify.Create, true},
+	}
+	for _, tc := range tests {
+		if got := isSVGWatchEvent(fsnotify.Event{Name: tc.name, Op: tc.op}, opts, []string{clusterPath}); got != tc.want {
+			t.Errorf("isSVGWatchEvent(%s %s) = %v, want %v", tc.op, tc.name, got, tc.want)
+		}
+	}
+}
@@ -5,9 +5,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"log/slog"
 	"path/filepath"
-	"regexp"
-	jargon_stemmer "search-index/jargon-stemmer"
 	"sort"
 	"strings"
 	"time"
@@ -16,7 +15,7 @@ import (
 )
 
 func generateTLDRData(ctx context.Context) ([]TLDRData, error) {
-	fmt.Println("📚 Generating TLDR data...")
+	slog.Info("📚 Generating TLDR data...")
 
 	// Path to TLDR markdown files
 	basePath := "../frontend/data/tldr"
@@ -37,7 +36,7 @@ func generateTLDRData(ctx context.Context) ([]TLDRData, error) {
 
 		data, err := processTLDRFile(file)
 		if err != nil {
-			fmt.Printf("⚠️  Warning: Failed to process %s: %v\n", file, err)
+			slog.Warn(fmt.Sprintf("⚠️  Warning: Failed to process %s: %v", file, err))
 			continue
 		}
 
@@ -51,7 +50,7 @@ func generateTLDRData(ctx context.Context) ([]TLDRData, error) {
 		return tldrData[i].ID < tldrData[j].ID
 	})
 
-	fmt.Printf("📚 Processed %d TLDR files\n", len(tldrData))
+	slog.Info(fmt.Sprintf("📚 Processed %d TLDR files", len(tldrData)))
 	return tldrData, nil
 }
 
@@ -116,8 +115,7 @@ func generateIDFromPath(path string) string {
 	cleanPath = strings.Replace(cleanPath, "/", "-", -1)
 
 	// Replace any invalid characters with underscores
-	reg := regexp.MustCompile(`[^a-zA-Z0-9\-_]`)
-	cleanPath = reg.ReplaceAllString(cleanPath, "_")
+	cleanPath = invalidIDChars.ReplaceAllString(cleanPath, "_")
 
 	return fmt.Sprintf("tldr-%s", sanitizeID(cleanPath))
 }
@@ -132,7 +130,7 @@ func capitalizeFirstLetter(name string) string {
 
 
 func RunTLDROnly(ctx context.Context, start time.Time) {
-	fmt.Println("📚 Generating TLDR data only...")
+	slog.Info("📚 Generating TLDR data only...")
 
 	tldr, err := generateTLDRData(ctx)
 	if err != nil {
@@ -145,30 +143,30 @@ func RunTLDROnly(ctx context.Context, start time.Time) {
 	}
 
 	elapsed := time.Since(start)
-	fmt.Printf("\n🎉 TLDR data generation completed in %v\n", elapsed)
-	fmt.Printf("📊 Generated %d TLDR pages\n", len(tldr))
+	slog.Info(fmt.Sprintf("\n🎉 TLDR data generation completed in %v", elapsed), "category", "tldr", "elapsed", elapsed.String())
+	slog.Info(fmt.Sprintf("📊 Generated %d TLDR pages", len(tldr)), "category", "tldr", "count", len(tldr))
 
 	// Show sample data
-	fmt.Println("\n📝 Sample TLDR pages:")
+	slog.Info("\n📝 Sample TLDR pages:")
 	for i, page := range tldr {
 		if i >= 10 { // Show first 10
-			fmt.Printf("  ... and %d more pages\n", len(tldr)-10)
+			slog.Info(fmt.Sprintf("  ... and %d more pages", len(tldr)-10))
 			break
 		}
-		fmt.Printf("  %d. %s (ID: %s, Category: %s)\n", i+1, page.Name, page.ID, page.Category)
+		slog.Info(fmt.Sprintf("  %d. %s (ID: %s, Category: %s)", i+1, page.Name, page.ID, page.Category))
 		if page.Description != "" {
-			fmt.Printf("     Description: %s\n", truncateString(page.Description, 80))
+			slog.Info(fmt.Sprintf("     Description: %s", truncateString(page.Description, 80)))
 		}
-		fmt.Printf("     Path: %s\n", page.Path)
-		fmt.Println()
+		slog.Info(fmt.Sprintf("     Path: %s", page.Path))
+		slog.Info("")
 	}
 
-	fmt.Printf("💾 Data saved to output/tldr_pages.json\n")
+	slog.Info(fmt.Sprintf("💾 Data saved to %s", filepath.Join(outputDir, "tldr_pages.json")))
 	
 	// Automatically run stem processing
-	fmt.Println("\n🔍 Running stem processing...")
-	if err := jargon_stemmer.ProcessJSONFile("output/tldr_pages.json"); err != nil {
+	slog.Info("\n🔍 Running stem processing...")
+	if err := stemJSONFile(filepath.Join(outputDir, "tldr_pages.json")); err != nil {
 		log.Fatalf("❌ Stem processing failed: %v", err)
 	}
-	fmt.Println("✅ Stem processing completed!")
+	slog.Info("✅ Stem processing completed!")
 }
\ No newline at end of file
@@ -5,14 +5,15 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"log/slog"
+	"path/filepath"
 	"regexp"
-	jargon_stemmer "search-index/jargon-stemmer"
 	"strings"
 	"time"
 )
 
 func generateToolsData(ctx context.Context) ([]ToolData, error) {
-	fmt.Println("📱 Generating tools data...")
+	slog.Info("📱 Generating tools data...")
 
 	// Read the TypeScript tools config file
 	content, err := ioutil.ReadFile("../frontend/src/config/tools.ts")
@@ -25,24 +26,38 @@ func generateToolsData(ctx context.Context) ([]ToolData, error) {
 		return nil, fmt.Errorf("failed to parse tools config: %w", err)
 	}
 
-	fmt.Printf("📱 Parsed %d tools from config\n", len(tools))
+	slog.Info(fmt.Sprintf("📱 Parsed %d tools from config", len(tools)))
 	return tools, nil
 }
 
+// Patterns of the tools TypeScript config, compiled once rather than for every tool
+var (
+	toolsConfigRegex = regexp.MustCompile(`export const TOOLS_CONFIG:[\s\S]*?=\s*\{([\s\S]*?)\}\s*;`)
+	toolKeyRegex     = regexp.MustCompile(`['"]([^'"]+)['"]\s*:\s*\{`)
+)
+
+// toolFieldRegexps holds the patterns of extractStringField for the fields read from every
+// tool, other fields are compiled when asked for
+var toolFieldRegexps = map[string][]*regexp.Regexp{
+	"name":        compileToolFieldRegexps("name"),
+	"title":       compileToolFieldRegexps("title"),
+	"description": compileToolFieldRegexps("description"),
+	"path":        compileToolFieldRegexps("path"),
+}
+
 func parseToolsConfig(tsContent string) ([]ToolData, error) {
 	var tools []ToolData
 
 	// Find the TOOLS_CONFIG object - simplified approach
-	objectRegex := regexp.MustCompile(`export const TOOLS_CONFIG:[\s\S]*?=\s*\{([\s\S]*?)\}\s*;`)
-	objectMatch := objectRegex.FindStringSubmatch(tsContent)
+	objectMatch := toolsConfigRegex.FindStringSubmatch(tsContent)
 	if len(objectMatch) < 2 {
-		fmt.Printf("❌ TOOLS_CONFIG object not found in TypeScript file\n")
-		fmt.Printf("File length: %d characters\n", len(tsContent))
+		slog.Error("❌ TOOLS_CONFIG object not found in TypeScript file")
+		slog.Info(fmt.Sprintf("File length: %d characters", len(tsContent)))
 		previewLen := 200
 		if len(tsContent) < previewLen {
 			previewLen = len(tsContent)
 		}
-		fmt.Printf("First 200 chars: %s\n", tsContent[:previewLen])
+		slog.Info(fmt.Sprintf("First 200 chars: %s", tsContent[:previewLen]))
 		return tools, fmt.Errorf("TOOLS_CONFIG object not found")
 	}
 
@@ -58,9 +73,8 @@ func parseToolEntries(body string) []ToolData {
 	var tools []ToolData
 
 	// Find tool keys first (handle both single and double quotes)
-	keyRegex := regexp.MustCompile(`['"]([^'"]+)['"]\s*:\s*\{`)
-	keyMatches := keyRegex.FindAllStringSubmatch(body, -1)
-	keyIndices := keyRegex.FindAllStringIndex(body, -1)
+	keyMatches := toolKeyRegex.FindAllStringSubmatch(body, -1)
+	keyIndices := toolKeyRegex.FindAllStringIndex(body, -1)
 
 
 	for i, keyMatch := range keyMatches {
@@ -161,11 +175,29 @@ func generateToolIDFromPath(path string) string {
 }
 
 func extractStringField(block, field string) string {
+	regexps, ok := toolFieldRegexps[field]
+	if !ok {
+		regexps = compileToolFieldRegexps(field)
+	}
+	for _, regex := range regexps {
+		match := regex.FindStringSubmatch(block)
+		if len(match) > 1 {
+			value := strings.TrimSpace(match[1])
+			if value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// compileToolFieldRegexps returns the patterns matching the string value of a tool field, tried in order
+func compileToolFieldRegexps(field string) []*regexp.Regexp {
 	// Handle both single and double quotes, and multiline values
 	patterns := []string{
 		// Single quotes
 		fmt.Sprintf(`%s\s*:\s*'([^']*)'`, field),
-		// Double quotes  
+		// Double quotes
 		fmt.Sprintf(`%s\s*:\s*"([^"]*)"`, field),
 		// Multiline single quotes
 		fmt.Sprintf(`%s\s*:\s*'([^']*(?:'[^']*)*)'`, field),
@@ -173,22 +205,16 @@ func extractStringField(block, field string) string {
 		fmt.Sprintf(`%s\s*:\s*"([^"]*(?:"[^"]*)*)"`, field),
 	}
 
-	for _, pattern := range patterns {
-		regex := regexp.MustCompile(pattern)
-		match := regex.FindStringSubmatch(block)
-		if len(match) > 1 {
-			value := strings.TrimSpace(match[1])
-			if value != "" {
-				return value
-			}
-		}
+	regexps := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		regexps[i] = regexp.MustCompile(pattern)
 	}
-	return ""
+	return regexps
 }
 
 
 func RunToolsOnly(ctx context.Context, start time.Time) {
-	fmt.Println("📱 Generating tools data only...")
+	slog.Info("📱 Generating tools data only...")
 
 	tools, err := generateToolsData(ctx)
 	if err != nil {
@@ -201,15 +227,15 @@ func RunToolsOnly(ctx context.Context, start time.Time) {
 	}
 
 	elapsed := time.Since(start)
-	fmt.Printf("\n🎉 Tools data generation completed in %v\n", elapsed)
-	fmt.Printf("📊 Generated %d tools\n", len(tools))
+	slog.Info(fmt.Sprintf("\n🎉 Tools data generation completed in %v", elapsed), "category", "tools", "elapsed", elapsed.String())
+	slog.Info(fmt.Sprintf("📊 Generated %d tools", len(tools)), "category", "tools", "count", len(tools))
 
-	fmt.Printf("💾 Data saved to output/tools.json\n")
+	slog.Info(fmt.Sprintf("💾 Data saved to %s", filepath.Join(outputDir, "tools.json")))
 	
 	// Automatically run stem processing
-	fmt.Println("\n🔍 Running stem processing...")
-	if err := jargon_stemmer.ProcessJSONFile("output/tools.json"); err != nil {
+	slog.Info("\n🔍 Running stem processing...")
+	if err := stemJSONFile(filepath.Join(outputDir, "tools.json")); err != nil {
 		log.Fatalf("❌ Stem processing failed: %v", err)
 	}
-	fmt.Println("✅ Stem processing completed!")
+	slog.Info("✅ Stem processing completed!")
 }
\ No newline at end of file
@@ -1,5 +1,7 @@
 package main
 
+import "search-index/svgicons"
+
 // ToolData represents a tool configuration entry (simplified)
 type ToolData struct {
 	ID          string `json:"id"`
@@ -28,15 +30,8 @@ type EmojiData struct {
 	Category    string `json:"category"`
 }
 
-// SVGIconData represents an SVG icon entry
-type SVGIconData struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Path        string `json:"path"`
-	Image       string `json:"image"` // Changed from "imagePath" to "image" to match Python
-	Category    string `json:"category"`
-}
+// SVGIconData represents an SVG icon entry, generated by the svgicons package
+type SVGIconData = svgicons.Icon
 
 // CheatsheetData represents a cheatsheet entry
 type CheatsheetData struct {
@@ -70,35 +65,10 @@ type FrontMatter struct {
 	Features    []string `yaml:"features"`
 }
 
-// SVGCluster represents the structure of cluster.json
-type SVGCluster struct {
-	Clusters map[string]ClusterEntry `json:"clusters"`
-}
+// SVGCluster represents the structure of cluster.json, read by the svgicons package and for PNG icons
+type SVGCluster = svgicons.Cluster
 
-// ClusterEntry represents a single cluster in the SVG icons data
-type ClusterEntry struct {
-	Name         string     `json:"name"`
-	SourceFolder string     `json:"source_folder"`
-	Path         string     `json:"path"`
-	Keywords     []string   `json:"keywords"`
-	Features     []string   `json:"features"`
-	Title        string     `json:"title"`
-	Description  string     `json:"description"`
-	FileNames    []FileName `json:"fileNames"`
-	Enhanced     bool       `json:"enhanced"`
-}
 
-// FileName represents a file entry in the cluster with all available fields
-type FileName struct {
-	FileName      string   `json:"fileName"`
-	Description   string   `json:"description"`
-	Usecases      string   `json:"usecases"`
-	Synonyms      []string `json:"synonyms"`
-	Tags          []string `json:"tags"`
-	Industry      string   `json:"industry"`
-	EmotionalCues string   `json:"emotional_cues"`
-	Enhanced      bool     `json:"enhanced"`
-}
 
 // EmojiJSONData represents the structure of emoji JSON files
 type EmojiJSONData struct {
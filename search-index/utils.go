@@ -1,10 +1,70 @@
 package main
 
-import "regexp"
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
 
-// sanitizeID replaces invalid characters with underscores
-// Only allows alphanumeric characters, hyphens, and underscores
+	"search-index/svgicons"
+)
+
+// invalidIDChars matches the characters the category ID generators replace with underscores,
+// compiled once rather than for every record
+var invalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9\-_]`)
+
+// sanitizeID replaces invalid characters with underscores, using the same rules as SVG icon IDs
 func sanitizeID(id string) string {
-	reg := regexp.MustCompile(`[^a-zA-Z0-9\-_]`)
-	return reg.ReplaceAllString(id, "_")
+	return svgicons.SanitizeID(id)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and renames it into place,
+// so readers see either the old file or the complete new one
+func writeFileAtomic(filePath string, data []byte) error {
+	return createFileAtomic(filePath, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// createFileAtomic streams the output of write to a temporary file in the same directory and
+// renames it into place once write succeeds. On any error the temporary file is removed and
+// an existing file at filePath is left untouched.
+func createFileAtomic(filePath string, write func(w io.Writer) error) error {
+	return createFileAtomicVerified(filePath, write, nil)
+}
+
+// createFileAtomicVerified is createFileAtomic checking the written temporary file with verify
+// before the rename, so a file failing it never replaces the previous one. A nil verify
+// checks nothing.
+func createFileAtomicVerified(filePath string, write func(w io.Writer) error, verify func(tmpPath string) error) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	if err := write(writer); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	if verify != nil {
+		if err := verify(tmp.Name()); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), filePath)
 }